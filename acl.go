@@ -22,6 +22,11 @@ type ACL struct {
 	AllowWrite  bool   `json:"allow_write"`
 }
 
+// ResourceStatus returns the acl status.
+func (a ACL) ResourceStatus() Status {
+	return a.Status
+}
+
 // ACLCreateOpts represents options for the acl Create request.
 type ACLCreateOpts struct {
 	DatastoreID string `json:"datastore_id"`
@@ -47,6 +52,7 @@ type ACLQueryParams struct {
 	PatternType string `json:"pattern_type,omitempty"`
 	UserID      string `json:"user_id,omitempty"`
 	Status      Status `json:"status,omitempty"`
+	Page
 }
 
 const ACLsURI = "/acls"
@@ -96,6 +102,9 @@ func (api *API) ACL(ctx context.Context, aclID string) (ACL, error) {
 
 // CreateACL creates a new acl.
 func (api *API) CreateACL(ctx context.Context, opts ACLCreateOpts) (ACL, error) {
+	if err := api.guardEngine(ctx, opts.DatastoreID, CapabilityACLs); err != nil {
+		return ACL{}, err
+	}
 	createACLOpts := struct {
 		ACL ACLCreateOpts `json:"acl"`
 	}{
@@ -162,3 +171,37 @@ func (api *API) DeleteACL(ctx context.Context, aclID string) error {
 
 	return nil
 }
+
+// CreateACLAndWait creates a new acl and waits until it becomes active.
+func (api *API) CreateACLAndWait(ctx context.Context, opts ACLCreateOpts, waitOpts *WaitOpts) (ACL, error) {
+	acl, err := api.CreateACL(ctx, opts)
+	if err != nil {
+		return ACL{}, err
+	}
+
+	return WaitFor(ctx, api.ACL, acl.ID, []Status{StatusActive}, waitOpts)
+}
+
+// UpdateACLAndWait updates an existing acl and waits until it becomes active.
+func (api *API) UpdateACLAndWait(
+	ctx context.Context,
+	aclID string,
+	opts ACLUpdateOpts,
+	waitOpts *WaitOpts,
+) (ACL, error) {
+	acl, err := api.UpdateACL(ctx, aclID, opts)
+	if err != nil {
+		return ACL{}, err
+	}
+
+	return waitForUpdate(ctx, api.ACL, aclID, acl, waitOpts)
+}
+
+// DeleteACLAndWait deletes an existing acl and waits until it disappears.
+func (api *API) DeleteACLAndWait(ctx context.Context, aclID string, waitOpts *WaitOpts) error {
+	if err := api.DeleteACL(ctx, aclID); err != nil {
+		return err
+	}
+
+	return WaitForDeleted(ctx, api.ACL, aclID, waitOpts)
+}
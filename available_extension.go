@@ -15,11 +15,25 @@ type AvailableExtension struct {
 	DependencyIDs    []string `json:"dependency_ids"`
 }
 
+// AvailableExtensionQueryParams represents available query parameters for available extension.
+type AvailableExtensionQueryParams struct {
+	DatastoreTypeID string `json:"datastore_type_id,omitempty"`
+	Name            string `json:"name,omitempty"`
+}
+
 const AvailableExtensionsURI = "/available-extensions"
 
-// AvailableExtensions returns all available extensions.
-func (api *API) AvailableExtensions(ctx context.Context) ([]AvailableExtension, error) {
-	resp, err := api.makeRequest(ctx, http.MethodGet, AvailableExtensionsURI, nil)
+// AvailableExtensions returns all available extensions or the extensions that match the optional params.
+func (api *API) AvailableExtensions(
+	ctx context.Context,
+	params ...*AvailableExtensionQueryParams,
+) ([]AvailableExtension, error) {
+	uri, err := setQueryParams(AvailableExtensionsURI, optionalParams(params))
+	if err != nil {
+		return []AvailableExtension{}, err
+	}
+
+	resp, err := api.makeRequest(ctx, http.MethodGet, uri, nil)
 	if err != nil {
 		return []AvailableExtension{}, err
 	}
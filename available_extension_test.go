@@ -90,6 +90,23 @@ func TestAvailableExtensions(t *testing.T) {
 	assert.Equal(t, expected, actual)
 }
 
+func TestAvailableExtensionsWithParams(t *testing.T) {
+	httpmock.Activate()
+	testClient := SetupTestClient()
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponderWithQuery("GET", testClient.Endpoint+AvailableExtensionsURI,
+		"datastore_type_id=20d7bcf4-f8d6-4bf6-b8f6-46cb440a87f4",
+		httpmock.NewStringResponder(200, testAvailableExtensionsResponse))
+
+	actual, err := testClient.AvailableExtensions(context.Background(), &AvailableExtensionQueryParams{
+		DatastoreTypeID: datastoreTypeID,
+	})
+
+	require.NoError(t, err)
+	assert.Len(t, actual, 2)
+}
+
 func TestAvailableExtension(t *testing.T) {
 	httpmock.Activate()
 	testClient := SetupTestClient()
@@ -0,0 +1,137 @@
+package dbaas
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// ErrUnsupportedOperation is returned if the engine of the datastore doesn't support the operation.
+var ErrUnsupportedOperation = errors.New("operation is not supported by the engine")
+
+// Capability is a group of operations that only some engines support.
+type Capability string
+
+const (
+	CapabilityUsers                   Capability = "users"
+	CapabilityDatabases               Capability = "databases"
+	CapabilityGrants                  Capability = "grants"
+	CapabilityTopics                  Capability = "topics"
+	CapabilityACLs                    Capability = "acls"
+	CapabilityExtensions              Capability = "extensions"
+	CapabilityLogicalReplicationSlots Capability = "logical replication slots"
+	CapabilityPooler                  Capability = "pooler"
+	CapabilityPassword                Capability = "datastore password"
+)
+
+// EngineCapabilities returns the capabilities of the engine.
+// It returns nil for unknown engines.
+func EngineCapabilities(engine string) []Capability {
+	switch engine {
+	case EnginePostgreSQL:
+		return []Capability{
+			CapabilityUsers, CapabilityDatabases, CapabilityGrants,
+			CapabilityExtensions, CapabilityLogicalReplicationSlots, CapabilityPooler,
+		}
+	case EngineMySQL:
+		return []Capability{CapabilityUsers, CapabilityDatabases, CapabilityGrants}
+	case EngineRedis:
+		return []Capability{CapabilityPassword}
+	case EngineKafka:
+		return []Capability{CapabilityUsers, CapabilityTopics, CapabilityACLs}
+	default:
+		return nil
+	}
+}
+
+// EngineSupports reports whether the engine supports the capability.
+// Unknown engines are assumed to support everything, so the server decides for them.
+func EngineSupports(engine string, capability Capability) bool {
+	capabilities := EngineCapabilities(engine)
+	if capabilities == nil {
+		return true
+	}
+	for _, c := range capabilities {
+		if c == capability {
+			return true
+		}
+	}
+	return false
+}
+
+// EngineGuard checks that the engine of the datastore supports the operation before the request is made.
+// Engines of datastores are cached, because the datastore type of a datastore never changes.
+type EngineGuard struct {
+	engines map[string]string
+	mu      sync.Mutex
+}
+
+// NewEngineGuard returns a new engine guard with an empty cache.
+func NewEngineGuard() *EngineGuard {
+	return &EngineGuard{engines: make(map[string]string)}
+}
+
+// engine returns the cached engine of the datastore or loads it.
+func (g *EngineGuard) engine(ctx context.Context, api *API, datastoreID string) (string, error) {
+	g.mu.Lock()
+	engine, ok := g.engines[datastoreID]
+	g.mu.Unlock()
+	if ok {
+		return engine, nil
+	}
+
+	engine, err := api.datastoreEngine(ctx, datastoreID)
+	if err != nil {
+		return "", err
+	}
+	g.mu.Lock()
+	g.engines[datastoreID] = engine
+	g.mu.Unlock()
+
+	return engine, nil
+}
+
+// CheckCapability returns ErrUnsupportedOperation if the engine of the datastore doesn't support the capability.
+// It uses the cache of the engine guard if the API has one.
+func (api *API) CheckCapability(ctx context.Context, datastoreID string, capability Capability) error {
+	var engine string
+	var err error
+	if api.EngineGuard != nil {
+		engine, err = api.EngineGuard.engine(ctx, api, datastoreID)
+	} else {
+		engine, err = api.datastoreEngine(ctx, datastoreID)
+	}
+	if err != nil {
+		return fmt.Errorf("check %s support: %w", capability, err)
+	}
+	if !EngineSupports(engine, capability) {
+		return fmt.Errorf("%w: %s datastore %s doesn't support %s",
+			ErrUnsupportedOperation, engine, datastoreID, capability)
+	}
+
+	return nil
+}
+
+// guardEngine checks the capability if the engine guard is enabled.
+func (api *API) guardEngine(ctx context.Context, datastoreID string, capability Capability) error {
+	if api.EngineGuard == nil {
+		return nil
+	}
+
+	return api.CheckCapability(ctx, datastoreID, capability)
+}
+
+// datastoreEngine loads the datastore and its datastore type.
+func (api *API) datastoreEngine(ctx context.Context, datastoreID string) (string, error) {
+	datastore, err := api.Datastore(ctx, datastoreID)
+	if err != nil {
+		return "", err
+	}
+	datastoreType, err := api.DatastoreTypeOf(ctx, datastore)
+	if err != nil {
+		return "", err
+	}
+
+	return datastoreType.Engine, nil
+}
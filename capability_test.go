@@ -0,0 +1,92 @@
+package dbaas
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEngineSupports(t *testing.T) {
+	tests := []struct {
+		engine     string
+		capability Capability
+		expected   bool
+	}{
+		{EngineKafka, CapabilityTopics, true},
+		{EngineKafka, CapabilityACLs, true},
+		{EngineKafka, CapabilityUsers, true},
+		{EngineKafka, CapabilityDatabases, false},
+		{EnginePostgreSQL, CapabilityTopics, false},
+		{EnginePostgreSQL, CapabilityExtensions, true},
+		{EnginePostgreSQL, CapabilityLogicalReplicationSlots, true},
+		{EnginePostgreSQL, CapabilityPassword, false},
+		{EngineMySQL, CapabilityExtensions, false},
+		{EngineMySQL, CapabilityGrants, true},
+		{EngineRedis, CapabilityPassword, true},
+		{EngineRedis, CapabilityDatabases, false},
+		{EngineRedis, CapabilityUsers, false},
+		{"clickhouse", CapabilityTopics, true},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.expected, EngineSupports(test.engine, test.capability),
+			"%s %s", test.engine, test.capability)
+	}
+}
+
+func TestEngineGuard(t *testing.T) {
+	httpmock.Activate()
+	testClient := SetupTestClient()
+	testClient.EngineGuard = NewEngineGuard()
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder("GET", testClient.Endpoint+DatastoresURI+"/"+datastoreID,
+		httpmock.NewStringResponder(200, testDatastoreResponse))
+	httpmock.RegisterResponder("GET", testClient.Endpoint+DatastoreTypesURI+"/"+datastoreTypeID,
+		httpmock.NewStringResponder(200, testDatastoreTypeResponse))
+	httpmock.RegisterResponder("POST", testClient.Endpoint+UsersURI,
+		httpmock.NewStringResponder(200, testUserResponse))
+
+	_, err := testClient.CreateTopic(context.Background(), TopicCreateOpts{DatastoreID: datastoreID, Name: "events"})
+
+	require.Error(t, err)
+	assert.ErrorIs(t, err, ErrUnsupportedOperation)
+	assert.Equal(t, "operation is not supported by the engine: mysql datastore "+datastoreID+" doesn't support topics",
+		err.Error())
+
+	_, err = testClient.PasswordDatastore(context.Background(), datastoreID, DatastorePasswordOpts{})
+
+	assert.ErrorIs(t, err, ErrUnsupportedOperation)
+
+	_, err = testClient.CreateUser(context.Background(), UserCreateOpts{DatastoreID: datastoreID, Name: "user"})
+
+	require.NoError(t, err)
+	calls := httpmock.GetCallCountInfo()
+	assert.Equal(t, 1, calls["GET "+testClient.Endpoint+DatastoresURI+"/"+datastoreID])
+	assert.Equal(t, 1, calls["GET "+testClient.Endpoint+DatastoreTypesURI+"/"+datastoreTypeID])
+	assert.Equal(t, 0, calls["POST "+testClient.Endpoint+TopicsURI])
+	assert.Equal(t, 1, calls["POST "+testClient.Endpoint+UsersURI])
+}
+
+func TestCheckCapabilityWithoutGuard(t *testing.T) {
+	httpmock.Activate()
+	testClient := SetupTestClient()
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder("GET", testClient.Endpoint+DatastoresURI+"/"+datastoreID,
+		httpmock.NewStringResponder(200, testDatastoreResponse))
+	httpmock.RegisterResponder("GET", testClient.Endpoint+DatastoreTypesURI+"/"+datastoreTypeID,
+		httpmock.NewStringResponder(200, testDatastoreTypeResponse))
+
+	err := testClient.CheckCapability(context.Background(), datastoreID, CapabilityDatabases)
+
+	require.NoError(t, err)
+
+	err = testClient.CheckCapability(context.Background(), datastoreID, CapabilityACLs)
+
+	assert.ErrorIs(t, err, ErrUnsupportedOperation)
+	assert.Equal(t, 2, httpmock.GetCallCountInfo()["GET "+testClient.Endpoint+DatastoresURI+"/"+datastoreID])
+}
@@ -0,0 +1,227 @@
+package dbaas
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gophercloud/gophercloud"
+	"github.com/gophercloud/gophercloud/openstack"
+)
+
+// ErrNoEndpoint is returned by New if neither endpoint nor endpoint discovery options are provided.
+var ErrNoEndpoint = errors.New("DBaaS endpoint is not specified")
+
+// Option configures the API created by New.
+type Option func(*clientOptions)
+
+// clientOptions stores settings collected from the options passed to New.
+type clientOptions struct {
+	httpClient      *http.Client
+	tokenSource     TokenSource
+	logger          Logger
+	retryPolicy     *RetryPolicy
+	engineGuard     *EngineGuard
+	headers         http.Header
+	identity        *identityOptions
+	token           string
+	userAgentSuffix string
+	projectID       string
+	timeout         time.Duration
+}
+
+// identityOptions stores settings needed to discover the DBaaS endpoint in the Keystone catalog.
+type identityOptions struct {
+	endpoint    string
+	region      string
+	serviceType string
+}
+
+// WithHTTPClient sets a custom HTTP client for both DBaaS and Keystone requests.
+// If the client is nil - default HTTP client will be used.
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(o *clientOptions) {
+		if httpClient != nil {
+			o.httpClient = httpClient
+		}
+	}
+}
+
+// WithToken sets a static authentication token.
+func WithToken(token string) Option {
+	return func(o *clientOptions) {
+		o.token = token
+	}
+}
+
+// WithTokenSource sets a token source that is consulted before every request.
+// It takes precedence over the static token.
+func WithTokenSource(tokenSource TokenSource) Option {
+	return func(o *clientOptions) {
+		o.tokenSource = tokenSource
+	}
+}
+
+// WithUserAgentSuffix appends the given suffix to the default user agent.
+func WithUserAgentSuffix(suffix string) Option {
+	return func(o *clientOptions) {
+		o.userAgentSuffix = suffix
+	}
+}
+
+// WithLogger sets a logger that receives details of every request attempt.
+func WithLogger(logger Logger) Option {
+	return func(o *clientOptions) {
+		o.logger = logger
+	}
+}
+
+// WithRetryPolicy sets a policy for retrying failed requests.
+func WithRetryPolicy(policy *RetryPolicy) Option {
+	return func(o *clientOptions) {
+		o.retryPolicy = policy
+	}
+}
+
+// WithEngineGuard enables checks that the datastore engine supports the operation, see EngineGuard.
+func WithEngineGuard() Option {
+	return func(o *clientOptions) {
+		o.engineGuard = NewEngineGuard()
+	}
+}
+
+// WithTimeout limits the time of a single HTTP request attempt.
+// The HTTP client passed with WithHTTPClient is copied and not modified.
+func WithTimeout(timeout time.Duration) Option {
+	return func(o *clientOptions) {
+		o.timeout = timeout
+	}
+}
+
+// WithHeaders sets headers that are added to every request.
+// Authentication and content headers can't be overridden by them.
+func WithHeaders(headers http.Header) Option {
+	return func(o *clientOptions) {
+		if o.headers == nil {
+			o.headers = make(http.Header)
+		}
+		for key, values := range headers {
+			for _, value := range values {
+				o.headers.Add(key, value)
+			}
+		}
+	}
+}
+
+// WithProjectID scopes the Keystone token to the given project.
+// It is used together with WithOpenstackEndpoint.
+func WithProjectID(projectID string) Option {
+	return func(o *clientOptions) {
+		o.projectID = projectID
+	}
+}
+
+// WithOpenstackEndpoint enables discovery of the DBaaS endpoint in the Keystone catalog.
+// It is used if the endpoint passed to New is empty.
+func WithOpenstackEndpoint(identityEndpoint, region, serviceType string) Option {
+	return func(o *clientOptions) {
+		o.identity = &identityOptions{
+			endpoint:    identityEndpoint,
+			region:      region,
+			serviceType: serviceType,
+		}
+	}
+}
+
+// New initializes a new DBaaS client for the V1 API.
+// Endpoint can be empty if the WithOpenstackEndpoint option is used.
+// Endpoint discovery authenticates with a token of the token source if it is set, otherwise with the static token.
+func New(endpoint string, opts ...Option) (*API, error) {
+	o := &clientOptions{
+		httpClient: http.DefaultClient,
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	httpClient := o.httpClient
+	if o.timeout > 0 {
+		customHTTPClient := *httpClient
+		customHTTPClient.Timeout = o.timeout
+		httpClient = &customHTTPClient
+	}
+
+	api := &API{
+		HTTPClient:  httpClient,
+		RetryPolicy: o.retryPolicy,
+		EngineGuard: o.engineGuard,
+		Logger:      o.logger,
+		TokenSource: o.tokenSource,
+		Headers:     o.headers,
+		Token:       o.token,
+		Endpoint:    endpoint,
+		UserAgent:   userAgent,
+	}
+	if o.userAgentSuffix != "" {
+		api.UserAgent = userAgent + " " + o.userAgentSuffix
+	}
+
+	if api.Endpoint == "" {
+		if o.identity == nil {
+			return nil, ErrNoEndpoint
+		}
+		token := o.token
+		if o.tokenSource != nil {
+			var err error
+			token, err = o.tokenSource.Token(context.Background())
+			if err != nil {
+				return nil, fmt.Errorf("could not get a token for endpoint discovery, %w", err)
+			}
+		}
+		token, endpoint, err := discoverEndpoint(httpClient, o.identity, token, o.projectID)
+		if err != nil {
+			return nil, err
+		}
+		api.Token = token
+		api.Endpoint = endpoint
+	}
+
+	return api, nil
+}
+
+// discoverEndpoint authenticates to Keystone with the given token and
+// locates the DBaaS endpoint in the service catalog.
+// It returns the token that should be used for DBaaS requests along with the endpoint.
+func discoverEndpoint(
+	httpClient *http.Client,
+	identity *identityOptions,
+	token, projectID string,
+) (string, string, error) {
+	provider, err := openstack.NewClient(identity.endpoint)
+	if err != nil {
+		return "", "", fmt.Errorf("could not create openstack client, %w", err)
+	}
+	provider.HTTPClient = *httpClient
+
+	authOpts := gophercloud.AuthOptions{
+		IdentityEndpoint: identity.endpoint,
+		TokenID:          token,
+	}
+	if projectID != "" {
+		authOpts.Scope = &gophercloud.AuthScope{ProjectID: projectID}
+	}
+	if err := openstack.Authenticate(provider, authOpts); err != nil {
+		return "", "", fmt.Errorf("could not authenticate to openstack, %w", err)
+	}
+
+	endpointOpts := gophercloud.EndpointOpts{Region: identity.region}
+	endpointOpts.ApplyDefaults(identity.serviceType)
+	endpoint, err := provider.EndpointLocator(endpointOpts)
+	if err != nil {
+		return "", "", fmt.Errorf("could not locate an endpoint, %w", err)
+	}
+
+	return provider.Token(), endpoint, nil
+}
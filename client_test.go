@@ -0,0 +1,114 @@
+package dbaas
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNew(t *testing.T) {
+	policy := NewRetryPolicy()
+	logger := LoggerFunc(func(context.Context, RequestInfo) {})
+
+	api, err := New("http://localhost/v1",
+		WithToken("test-token"),
+		WithUserAgentSuffix("my-app/1.0"),
+		WithRetryPolicy(policy),
+		WithLogger(logger),
+		WithEngineGuard(),
+	)
+
+	require.NoError(t, err)
+	assert.Equal(t, http.DefaultClient, api.HTTPClient)
+	assert.Equal(t, "test-token", api.Token)
+	assert.Equal(t, "http://localhost/v1", api.Endpoint)
+	assert.Equal(t, userAgent+" my-app/1.0", api.UserAgent)
+	assert.Equal(t, policy, api.RetryPolicy)
+	assert.NotNil(t, api.Logger)
+	assert.NotNil(t, api.EngineGuard)
+}
+
+func TestNewWithoutEndpoint(t *testing.T) {
+	_, err := New("", WithToken("test-token"))
+
+	require.ErrorIs(t, err, ErrNoEndpoint)
+}
+
+func TestNewWithTimeoutDoesNotModifyHTTPClient(t *testing.T) {
+	customHTTPClient := &http.Client{}
+
+	api, err := New("http://localhost/v1", WithHTTPClient(customHTTPClient), WithTimeout(time.Minute))
+
+	require.NoError(t, err)
+	assert.Equal(t, time.Minute, api.HTTPClient.Timeout)
+	assert.Zero(t, customHTTPClient.Timeout)
+}
+
+func TestNewRequestHeaders(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	testClient, err := New("http://localhost/v1",
+		WithTokenSource(StaticTokenSource("source-token")),
+		WithToken("static-token"),
+		WithHeaders(http.Header{"X-Request-Id": []string{"42"}, "X-Auth-Token": []string{"override"}}),
+	)
+	require.NoError(t, err)
+
+	httpmock.RegisterResponder("GET", testClient.Endpoint+UsersURI+"/"+userID,
+		func(req *http.Request) (*http.Response, error) {
+			assert.Equal(t, "42", req.Header.Get("X-Request-Id"))
+			assert.Equal(t, []string{"source-token"}, req.Header.Values("X-Auth-Token"))
+			assert.Equal(t, userAgent, req.Header.Get("User-Agent"))
+			return httpmock.NewStringResponse(200, testUserResponse), nil
+		})
+
+	_, err = testClient.User(context.Background(), userID)
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, httpmock.GetTotalCallCount())
+}
+
+func TestNewOpenstackEndpointUsesCustomHTTPClient(t *testing.T) {
+	transport := httpmock.NewMockTransport()
+	transport.RegisterNoResponder(httpmock.NewStringResponder(500, ""))
+	customHTTPClient := &http.Client{Transport: transport}
+
+	_, err := New("",
+		WithHTTPClient(customHTTPClient),
+		WithToken("test-token"),
+		WithOpenstackEndpoint("http://keystone/identity/v3", "ru-1", "dbaas"),
+	)
+
+	require.Error(t, err)
+	assert.Positive(t, transport.GetTotalCallCount())
+}
+
+func TestNewOpenstackEndpointUsesTokenSource(t *testing.T) {
+	transport := httpmock.NewMockTransport()
+	var authToken string
+	transport.RegisterResponder("GET", testKeystoneEndpoint+"auth/tokens",
+		func(req *http.Request) (*http.Response, error) {
+			authToken = req.Header.Get("X-Subject-Token")
+			expiresAt := time.Now().Add(time.Hour).UTC().Format(time.RFC3339)
+			resp := httpmock.NewStringResponse(200, fmt.Sprintf(testKeystoneTokenResponse, expiresAt))
+			resp.Header.Set("X-Subject-Token", authToken)
+			return resp, nil
+		})
+
+	testClient, err := New("",
+		WithHTTPClient(&http.Client{Transport: transport}),
+		WithTokenSource(StaticTokenSource("source-token")),
+		WithOpenstackEndpoint(testKeystoneEndpoint, "ru-1", "dbaas"),
+	)
+
+	require.NoError(t, err)
+	assert.Equal(t, "source-token", authToken)
+	assert.Equal(t, "https://ru-1.dbaas.selcloud.ru/v1/", testClient.Endpoint)
+}
@@ -0,0 +1,437 @@
+package dbaas
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrCloneFailed is returned by CloneResult.Err if some objects were not cloned.
+var ErrCloneFailed = errors.New("clone failed")
+
+// CloneObjectKind is a kind of the object reproduced in the clone.
+type CloneObjectKind string
+
+const (
+	CloneUser                   CloneObjectKind = "user"
+	CloneDatabase               CloneObjectKind = "database"
+	CloneGrant                  CloneObjectKind = "grant"
+	CloneExtension              CloneObjectKind = "extension"
+	CloneLogicalReplicationSlot CloneObjectKind = "logical_replication_slot"
+)
+
+// CloneAction is an outcome of reproducing the object in the clone.
+type CloneAction string
+
+const (
+	// CloneCreated means that the object was created in the clone.
+	CloneCreated CloneAction = "created"
+
+	// CloneExisting means that the clone already had the object, e.g. after a restore.
+	CloneExisting CloneAction = "existing"
+
+	// CloneSkipped means that the object was not created because its dependency was not cloned.
+	CloneSkipped CloneAction = "skipped"
+
+	// CloneFailed means that the object creation failed.
+	CloneFailed CloneAction = "failed"
+)
+
+// CloneOpts represents options of the datastore clone.
+type CloneOpts struct {
+	// Password returns a password for the user of the clone. It is required if the source has users.
+	Password func(user User) (string, error)
+
+	// WaitOpts are used to wait until the clone and every cloned object become active.
+	WaitOpts *WaitOpts
+
+	// TargetTime restores the clone from the backups of the source at this time.
+	// The clone is created as a new datastore if it is zero.
+	TargetTime time.Time
+
+	// Datastore overrides options inherited from the source, see RestoreDatastore.
+	Datastore DatastoreCreateOpts
+}
+
+// CloneObject is a result of reproducing the object of the source in the clone.
+type CloneObject struct {
+	// Err is nil if the object was created or already existed.
+	Err error `json:"-"`
+
+	Kind     CloneObjectKind `json:"kind"`
+	Action   CloneAction     `json:"action"`
+	Name     string          `json:"name"`
+	SourceID string          `json:"source_id"`
+	TargetID string          `json:"target_id,omitempty"`
+	Error    string          `json:"error,omitempty"`
+}
+
+// CloneResult is a result of the datastore clone. Objects are listed in the order of creation.
+type CloneResult struct {
+	Objects   []CloneObject `json:"objects"`
+	Datastore Datastore     `json:"datastore"`
+}
+
+// Err returns ErrCloneFailed with the errors of the objects that were not cloned.
+func (r CloneResult) Err() error {
+	var failures []error
+	for _, object := range r.Objects {
+		if object.Err != nil {
+			failures = append(failures, fmt.Errorf("%s %s: %w", object.Kind, object.Name, object.Err))
+		}
+	}
+	if len(failures) == 0 {
+		return nil
+	}
+
+	return fmt.Errorf("%w: %w", ErrCloneFailed, errors.Join(failures...))
+}
+
+// add records the result of reproducing the object.
+func (r *CloneResult) add(kind CloneObjectKind, name, sourceID, targetID string, action CloneAction, err error) {
+	object := CloneObject{
+		Err:      err,
+		Kind:     kind,
+		Action:   action,
+		Name:     name,
+		SourceID: sourceID,
+		TargetID: targetID,
+	}
+	if err != nil {
+		object.Error = err.Error()
+	}
+	r.Objects = append(r.Objects, object)
+}
+
+// cloneObjects are the logical objects of the datastore.
+type cloneObjects struct {
+	users      []User
+	databases  []Database
+	grants     []Grant
+	extensions []Extension
+	slots      []LogicalReplicationSlot
+}
+
+// CloneDatastore creates a copy of the source datastore and reproduces its users, databases, grants,
+// extensions and logical replication slots. The copy is restored from the backups of the source
+// if TargetTime is set, otherwise it is created empty with the options of the source.
+// Objects are created in dependency order, objects that the clone already has are kept.
+// The error is returned only if the clone itself can't be created, results of the objects
+// are reported in CloneResult.
+func (api *API) CloneDatastore(ctx context.Context, sourceID string, opts CloneOpts) (CloneResult, error) {
+	source, err := api.Datastore(ctx, sourceID)
+	if err != nil {
+		return CloneResult{}, err
+	}
+	datastoreType, err := api.DatastoreType(ctx, source.TypeID)
+	if err != nil {
+		return CloneResult{}, err
+	}
+	objects, err := api.listCloneObjects(ctx, source.ID, datastoreType.Engine)
+	if err != nil {
+		return CloneResult{}, fmt.Errorf("list objects of datastore %s: %w", source.ID, err)
+	}
+	if len(objects.users) > 0 && opts.Password == nil {
+		return CloneResult{}, errors.New("password callback is required to clone users")
+	}
+
+	var clone Datastore
+	if opts.TargetTime.IsZero() {
+		clone, err = api.CreateDatastore(ctx, inheritCreateOpts(source, opts.Datastore, "-clone"))
+	} else {
+		clone, err = api.RestoreDatastore(ctx, source.ID, opts.TargetTime, opts.Datastore)
+	}
+	if err != nil {
+		return CloneResult{}, err
+	}
+	clone, err = api.WaitForDatastoreStatus(ctx, clone.ID, []Status{StatusActive}, opts.WaitOpts)
+	if err != nil {
+		return CloneResult{Datastore: clone}, err
+	}
+	existing, err := api.listCloneObjects(ctx, clone.ID, datastoreType.Engine)
+	if err != nil {
+		return CloneResult{Datastore: clone}, fmt.Errorf("list objects of datastore %s: %w", clone.ID, err)
+	}
+
+	result := CloneResult{Datastore: clone, Objects: []CloneObject{}}
+	userIDs := api.cloneUsers(ctx, &result, objects.users, existing.users, opts)
+	databaseIDs := api.cloneDatabases(ctx, &result, objects.databases, existing.databases, userIDs, opts.WaitOpts)
+	api.cloneGrants(ctx, &result, objects.grants, existing.grants, userIDs, databaseIDs, opts.WaitOpts)
+	api.cloneExtensions(ctx, &result, objects.extensions, existing.extensions, databaseIDs, opts.WaitOpts)
+	api.cloneSlots(ctx, &result, objects.slots, existing.slots, databaseIDs, opts.WaitOpts)
+
+	return result, nil
+}
+
+// listCloneObjects returns the objects of the datastore that the engine supports, see EngineCapabilities.
+func (api *API) listCloneObjects(ctx context.Context, datastoreID, engine string) (cloneObjects, error) {
+	var objects cloneObjects
+	if !EngineSupports(engine, CapabilityUsers) {
+		return objects, nil
+	}
+
+	users, err := api.Users(ctx, &UserQueryParams{DatastoreID: datastoreID})
+	if err != nil {
+		return objects, err
+	}
+	for _, user := range users {
+		if user.DatastoreID == datastoreID && cloneable(user.Status) {
+			objects.users = append(objects.users, user)
+		}
+	}
+	if !EngineSupports(engine, CapabilityDatabases) {
+		return objects, nil
+	}
+
+	databases, err := api.Databases(ctx, &DatabaseQueryParams{DatastoreID: datastoreID})
+	if err != nil {
+		return objects, err
+	}
+	for _, database := range databases {
+		if cloneable(database.Status) {
+			objects.databases = append(objects.databases, database)
+		}
+	}
+	grants, err := api.Grants(ctx, &GrantQueryParams{DatastoreID: datastoreID})
+	if err != nil {
+		return objects, err
+	}
+	for _, grant := range grants {
+		if grant.DatastoreID == datastoreID && cloneable(grant.Status) {
+			objects.grants = append(objects.grants, grant)
+		}
+	}
+	if !EngineSupports(engine, CapabilityExtensions) {
+		return objects, nil
+	}
+
+	extensions, err := api.Extensions(ctx, &ExtensionQueryParams{DatastoreID: datastoreID})
+	if err != nil {
+		return objects, err
+	}
+	for _, extension := range extensions {
+		if cloneable(extension.Status) {
+			objects.extensions = append(objects.extensions, extension)
+		}
+	}
+	slots, err := api.LogicalReplicationSlots(ctx, &LogicalReplicationSlotQueryParams{DatastoreID: datastoreID})
+	if err != nil {
+		return objects, err
+	}
+	for _, slot := range slots {
+		if cloneable(slot.Status) {
+			objects.slots = append(objects.slots, slot)
+		}
+	}
+
+	return objects, nil
+}
+
+// cloneable reports whether the object with the status should be reproduced in the clone.
+func cloneable(status Status) bool {
+	return status != StatusPendingDelete && status != StatusDeleted
+}
+
+// cloneUsers creates the users and returns the IDs of the users of the clone by the IDs of the source users.
+func (api *API) cloneUsers(
+	ctx context.Context,
+	result *CloneResult,
+	users, existing []User,
+	opts CloneOpts,
+) map[string]string {
+	ids := make(map[string]string, len(users))
+	for _, user := range users {
+		if target, ok := findClone(existing, func(u User) bool { return u.Name == user.Name }); ok {
+			ids[user.ID] = target.ID
+			result.add(CloneUser, user.Name, user.ID, target.ID, CloneExisting, nil)
+			continue
+		}
+		password, err := opts.Password(user)
+		if err != nil {
+			result.add(CloneUser, user.Name, user.ID, "", CloneFailed, fmt.Errorf("get password: %w", err))
+			continue
+		}
+		created, err := api.CreateUserAndWait(ctx, UserCreateOpts{
+			Name:        user.Name,
+			Password:    password,
+			DatastoreID: result.Datastore.ID,
+		}, opts.WaitOpts)
+		if err != nil {
+			result.add(CloneUser, user.Name, user.ID, created.ID, CloneFailed, err)
+			continue
+		}
+		ids[user.ID] = created.ID
+		result.add(CloneUser, user.Name, user.ID, created.ID, CloneCreated, nil)
+	}
+
+	return ids
+}
+
+// cloneDatabases creates the databases with the owners and the locales of the source databases
+// and returns the IDs of the databases of the clone by the IDs of the source databases.
+func (api *API) cloneDatabases(
+	ctx context.Context,
+	result *CloneResult,
+	databases, existing []Database,
+	userIDs map[string]string,
+	waitOpts *WaitOpts,
+) map[string]string {
+	ids := make(map[string]string, len(databases))
+	for _, database := range databases {
+		if target, ok := findClone(existing, func(d Database) bool { return d.Name == database.Name }); ok {
+			ids[database.ID] = target.ID
+			result.add(CloneDatabase, database.Name, database.ID, target.ID, CloneExisting, nil)
+			continue
+		}
+		ownerID, ok := userIDs[database.OwnerID]
+		if database.OwnerID != "" && !ok {
+			result.add(CloneDatabase, database.Name, database.ID, "", CloneSkipped,
+				fmt.Errorf("owner %s was not cloned", database.OwnerID))
+			continue
+		}
+		created, err := api.CreateDatabaseAndWait(ctx, DatabaseCreateOpts{
+			DatastoreID: result.Datastore.ID,
+			Name:        database.Name,
+			OwnerID:     ownerID,
+			LcCollate:   database.LcCollate,
+			LcCtype:     database.LcCtype,
+		}, waitOpts)
+		if err != nil {
+			result.add(CloneDatabase, database.Name, database.ID, created.ID, CloneFailed, err)
+			continue
+		}
+		ids[database.ID] = created.ID
+		result.add(CloneDatabase, database.Name, database.ID, created.ID, CloneCreated, nil)
+	}
+
+	return ids
+}
+
+// cloneGrants grants the cloned users access to the cloned databases.
+func (api *API) cloneGrants(
+	ctx context.Context,
+	result *CloneResult,
+	grants, existing []Grant,
+	userIDs, databaseIDs map[string]string,
+	waitOpts *WaitOpts,
+) {
+	for _, grant := range grants {
+		name := grant.UserID + " on " + grant.DatabaseID
+		userID, databaseID, err := cloneDependencies(userIDs, grant.UserID, databaseIDs, grant.DatabaseID)
+		if err != nil {
+			result.add(CloneGrant, name, grant.ID, "", CloneSkipped, err)
+			continue
+		}
+		if target, ok := findClone(existing, func(g Grant) bool {
+			return g.UserID == userID && g.DatabaseID == databaseID
+		}); ok {
+			result.add(CloneGrant, name, grant.ID, target.ID, CloneExisting, nil)
+			continue
+		}
+		created, err := api.CreateGrantAndWait(ctx, GrantCreateOpts{
+			DatastoreID: result.Datastore.ID,
+			DatabaseID:  databaseID,
+			UserID:      userID,
+		}, waitOpts)
+		if err != nil {
+			result.add(CloneGrant, name, grant.ID, created.ID, CloneFailed, err)
+			continue
+		}
+		result.add(CloneGrant, name, grant.ID, created.ID, CloneCreated, nil)
+	}
+}
+
+// cloneExtensions installs the extensions into the cloned databases.
+func (api *API) cloneExtensions(
+	ctx context.Context,
+	result *CloneResult,
+	extensions, existing []Extension,
+	databaseIDs map[string]string,
+	waitOpts *WaitOpts,
+) {
+	for _, extension := range extensions {
+		name := extension.AvailableExtensionID + " in " + extension.DatabaseID
+		databaseID, ok := databaseIDs[extension.DatabaseID]
+		if !ok {
+			result.add(CloneExtension, name, extension.ID, "", CloneSkipped,
+				fmt.Errorf("database %s was not cloned", extension.DatabaseID))
+			continue
+		}
+		if target, ok := findClone(existing, func(e Extension) bool {
+			return e.AvailableExtensionID == extension.AvailableExtensionID && e.DatabaseID == databaseID
+		}); ok {
+			result.add(CloneExtension, name, extension.ID, target.ID, CloneExisting, nil)
+			continue
+		}
+		created, err := api.CreateExtensionAndWait(ctx, ExtensionCreateOpts{
+			AvailableExtensionID: extension.AvailableExtensionID,
+			DatastoreID:          result.Datastore.ID,
+			DatabaseID:           databaseID,
+		}, waitOpts)
+		if err != nil {
+			result.add(CloneExtension, name, extension.ID, created.ID, CloneFailed, err)
+			continue
+		}
+		result.add(CloneExtension, name, extension.ID, created.ID, CloneCreated, nil)
+	}
+}
+
+// cloneSlots creates the logical replication slots in the cloned databases.
+func (api *API) cloneSlots(
+	ctx context.Context,
+	result *CloneResult,
+	slots, existing []LogicalReplicationSlot,
+	databaseIDs map[string]string,
+	waitOpts *WaitOpts,
+) {
+	for _, slot := range slots {
+		databaseID, ok := databaseIDs[slot.DatabaseID]
+		if !ok {
+			result.add(CloneLogicalReplicationSlot, slot.Name, slot.ID, "", CloneSkipped,
+				fmt.Errorf("database %s was not cloned", slot.DatabaseID))
+			continue
+		}
+		if target, ok := findClone(existing, func(s LogicalReplicationSlot) bool { return s.Name == slot.Name }); ok {
+			result.add(CloneLogicalReplicationSlot, slot.Name, slot.ID, target.ID, CloneExisting, nil)
+			continue
+		}
+		created, err := api.CreateLogicalReplicationSlotAndWait(ctx, LogicalReplicationSlotCreateOpts{
+			Name:        slot.Name,
+			DatastoreID: result.Datastore.ID,
+			DatabaseID:  databaseID,
+		}, waitOpts)
+		if err != nil {
+			result.add(CloneLogicalReplicationSlot, slot.Name, slot.ID, created.ID, CloneFailed, err)
+			continue
+		}
+		result.add(CloneLogicalReplicationSlot, slot.Name, slot.ID, created.ID, CloneCreated, nil)
+	}
+}
+
+// cloneDependencies returns the IDs of the cloned user and database.
+func cloneDependencies(userIDs map[string]string, userID string, databaseIDs map[string]string, databaseID string) (
+	string, string, error,
+) {
+	clonedUserID, ok := userIDs[userID]
+	if !ok {
+		return "", "", fmt.Errorf("user %s was not cloned", userID)
+	}
+	clonedDatabaseID, ok := databaseIDs[databaseID]
+	if !ok {
+		return "", "", fmt.Errorf("database %s was not cloned", databaseID)
+	}
+
+	return clonedUserID, clonedDatabaseID, nil
+}
+
+// findClone returns the first object of the clone that matches.
+func findClone[T any](objects []T, match func(T) bool) (T, bool) {
+	for _, object := range objects {
+		if match(object) {
+			return object, true
+		}
+	}
+	var zero T
+	return zero, false
+}
@@ -0,0 +1,302 @@
+package dbaas
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// cloneTestStore keeps objects of a collection and serves them like the API.
+type cloneTestStore struct {
+	objects []map[string]any
+	created []map[string]any
+}
+
+func cloneTestID(n int) string {
+	return fmt.Sprintf("00000000-0000-4000-8000-%012d", n)
+}
+
+// registerCloneCollection serves the collection with the source objects and records created objects.
+func registerCloneCollection(
+	testClient *API,
+	uri, listKey, itemKey string,
+	objects []map[string]any,
+	nextID *int,
+) *cloneTestStore {
+	store := &cloneTestStore{objects: objects}
+	httpmock.RegisterResponder("GET", "=~^"+regexp.QuoteMeta(testClient.Endpoint+uri)+`(\?.*)?$`,
+		func(req *http.Request) (*http.Response, error) {
+			datastoreID := req.URL.Query().Get("datastore_id")
+			result := []map[string]any{}
+			for _, object := range store.objects {
+				if datastoreID == "" || object["datastore_id"] == datastoreID {
+					result = append(result, object)
+				}
+			}
+			return httpmock.NewJsonResponse(200, map[string]any{listKey: result})
+		})
+	httpmock.RegisterResponder("POST", testClient.Endpoint+uri,
+		func(req *http.Request) (*http.Response, error) {
+			var body map[string]map[string]any
+			if err := json.NewDecoder(req.Body).Decode(&body); err != nil {
+				return httpmock.NewStringResponse(400, ""), err
+			}
+			*nextID++
+			object := body[itemKey]
+			store.created = append(store.created, object)
+			created := map[string]any{"id": cloneTestID(*nextID), "status": "ACTIVE"}
+			for key, value := range object {
+				created[key] = value
+			}
+			store.objects = append(store.objects, created)
+			return httpmock.NewJsonResponse(200, map[string]any{itemKey: created})
+		})
+	httpmock.RegisterResponder("GET", "=~^"+regexp.QuoteMeta(testClient.Endpoint+uri+"/")+`([0-9a-f-]+)$`,
+		func(req *http.Request) (*http.Response, error) {
+			id := httpmock.MustGetSubmatch(req, 1)
+			for _, object := range store.objects {
+				if object["id"] == id {
+					return httpmock.NewJsonResponse(200, map[string]any{itemKey: object})
+				}
+			}
+			return httpmock.NewStringResponse(404, ""), nil
+		})
+
+	return store
+}
+
+// registerCloneDatastores serves the PostgreSQL source datastore and records the clone creation.
+func registerCloneDatastores(t *testing.T, testClient *API) *DatastoreCreateOpts {
+	t.Helper()
+
+	sourceResponse, err := httpmock.NewJsonResponder(200, map[string]Datastore{"datastore": testRestoreSource()})
+	require.NoError(t, err)
+	httpmock.RegisterResponder("GET", testClient.Endpoint+DatastoresURI+"/"+restoreSourceID, sourceResponse)
+	httpmock.RegisterResponder("GET", testClient.Endpoint+DatastoreTypesURI+"/"+datastoreTypeID,
+		httpmock.NewStringResponder(200, `{"datastore-type": {"id": "`+datastoreTypeID+`", "engine": "postgresql"}}`))
+	created := &DatastoreCreateOpts{}
+	httpmock.RegisterResponder("POST", testClient.Endpoint+DatastoresURI,
+		func(req *http.Request) (*http.Response, error) {
+			var body struct {
+				Datastore DatastoreCreateOpts `json:"datastore"`
+			}
+			if err := json.NewDecoder(req.Body).Decode(&body); err != nil {
+				return httpmock.NewStringResponse(400, ""), err
+			}
+			*created = body.Datastore
+			return httpmock.NewJsonResponse(200, map[string]Datastore{"datastore": {ID: datastoreID}})
+		})
+	httpmock.RegisterResponder("GET", testClient.Endpoint+DatastoresURI+"/"+datastoreID,
+		datastoreStatusResponder(StatusPendingCreate, StatusActive))
+
+	return created
+}
+
+func TestCloneDatastore(t *testing.T) {
+	httpmock.Activate()
+	testClient := SetupTestClient()
+	defer httpmock.DeactivateAndReset()
+
+	datastoreCreated := registerCloneDatastores(t, testClient)
+	nextID := 100
+	alice, bob, other := cloneTestID(1), cloneTestID(2), cloneTestID(3)
+	app, reports := cloneTestID(11), cloneTestID(12)
+	users := registerCloneCollection(testClient, UsersURI, "users", "user", []map[string]any{
+		{"id": alice, "name": "alice", "datastore_id": restoreSourceID, "status": "ACTIVE"},
+		{"id": bob, "name": "bob", "datastore_id": restoreSourceID, "status": "ACTIVE"},
+		{"id": other, "name": "other", "datastore_id": datastoreTypeID, "status": "ACTIVE"},
+	}, &nextID)
+	databases := registerCloneCollection(testClient, DatabasesURI, "databases", "database", []map[string]any{
+		{
+			"id": app, "name": "app", "owner_id": alice, "lc_collate": "C", "lc_ctype": "C",
+			"datastore_id": restoreSourceID, "status": "ACTIVE",
+		},
+		{"id": reports, "name": "reports", "owner_id": bob, "datastore_id": restoreSourceID, "status": "ACTIVE"},
+	}, &nextID)
+	grants := registerCloneCollection(testClient, GrantsURI, "grants", "grant", []map[string]any{
+		{"id": cloneTestID(21), "user_id": bob, "database_id": app, "datastore_id": restoreSourceID},
+	}, &nextID)
+	extensions := registerCloneCollection(testClient, ExtensionsURI, "extensions", "extension", []map[string]any{
+		{
+			"id": cloneTestID(31), "available_extension_id": cloneTestID(99), "database_id": app,
+			"datastore_id": restoreSourceID, "status": "ACTIVE",
+		},
+	}, &nextID)
+	slots := registerCloneCollection(testClient, LogicalReplicationSlotsURI, "logical-replication-slots",
+		"logical-replication-slot", []map[string]any{
+			{"id": cloneTestID(41), "name": "cdc", "database_id": app, "datastore_id": restoreSourceID},
+			{
+				"id": cloneTestID(42), "name": "gone", "database_id": app,
+				"datastore_id": restoreSourceID, "status": "PENDING_DELETE",
+			},
+		}, &nextID)
+
+	var passwordsFor []string
+	opts := CloneOpts{
+		Password: func(user User) (string, error) {
+			passwordsFor = append(passwordsFor, user.Name)
+			if user.Name == "bob" {
+				return "", errors.New("no password for bob")
+			}
+			return "secret", nil
+		},
+		WaitOpts: testWaitOpts(),
+	}
+
+	result, err := testClient.CloneDatastore(context.Background(), restoreSourceID, opts)
+
+	require.NoError(t, err)
+	assert.Equal(t, datastoreID, result.Datastore.ID)
+	assert.Equal(t, StatusActive, result.Datastore.Status)
+	assert.Equal(t, "source-clone", datastoreCreated.Name)
+	assert.Nil(t, datastoreCreated.Restore)
+	assert.Equal(t, []string{"alice", "bob"}, passwordsFor)
+
+	aliceClone := cloneTestID(101)
+	appClone := cloneTestID(102)
+	assert.Equal(t, []map[string]any{
+		{"name": "alice", "password": "secret", "datastore_id": datastoreID},
+	}, users.created)
+	assert.Equal(t, []map[string]any{
+		{"name": "app", "owner_id": aliceClone, "lc_collate": "C", "lc_ctype": "C", "datastore_id": datastoreID},
+	}, databases.created)
+	assert.Empty(t, grants.created)
+	assert.Equal(t, []map[string]any{
+		{"available_extension_id": cloneTestID(99), "database_id": appClone, "datastore_id": datastoreID},
+	}, extensions.created)
+	assert.Equal(t, []map[string]any{
+		{"name": "cdc", "database_id": appClone, "datastore_id": datastoreID},
+	}, slots.created)
+
+	actions := make([]string, 0, len(result.Objects))
+	for _, object := range result.Objects {
+		actions = append(actions, fmt.Sprintf("%s %s %s", object.Kind, object.SourceID, object.Action))
+	}
+	assert.Equal(t, []string{
+		"user " + alice + " created",
+		"user " + bob + " failed",
+		"database " + app + " created",
+		"database " + reports + " skipped",
+		"grant " + cloneTestID(21) + " skipped",
+		"extension " + cloneTestID(31) + " created",
+		"logical_replication_slot " + cloneTestID(41) + " created",
+	}, actions)
+	assert.Equal(t, aliceClone, result.Objects[0].TargetID)
+
+	err = result.Err()
+	assert.ErrorIs(t, err, ErrCloneFailed)
+	assert.Contains(t, err.Error(), "no password for bob")
+	assert.Contains(t, err.Error(), "owner "+bob+" was not cloned")
+}
+
+func TestCloneDatastoreRequiresPassword(t *testing.T) {
+	httpmock.Activate()
+	testClient := SetupTestClient()
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder("GET", testClient.Endpoint+DatastoresURI+"/"+datastoreID,
+		httpmock.NewStringResponder(200, testDatastoreResponse))
+	httpmock.RegisterResponder("GET", testClient.Endpoint+DatastoreTypesURI+"/"+datastoreTypeID,
+		httpmock.NewStringResponder(200, testDatastoreTypeResponse))
+	nextID := 0
+	registerCloneCollection(testClient, UsersURI, "users", "user", []map[string]any{
+		{"id": cloneTestID(1), "name": "alice", "datastore_id": datastoreID, "status": "ACTIVE"},
+	}, &nextID)
+	registerCloneCollection(testClient, DatabasesURI, "databases", "database", nil, &nextID)
+	registerCloneCollection(testClient, GrantsURI, "grants", "grant", nil, &nextID)
+
+	_, err := testClient.CloneDatastore(context.Background(), datastoreID, CloneOpts{})
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "password callback is required")
+	assert.Equal(t, 0, httpmock.GetCallCountInfo()["POST "+testClient.Endpoint+DatastoresURI])
+}
+
+func TestCloneDatastoreRestoreKeepsExisting(t *testing.T) {
+	httpmock.Activate()
+	testClient := SetupTestClient()
+	defer httpmock.DeactivateAndReset()
+
+	datastoreCreated := registerCloneDatastores(t, testClient)
+	nextID := 100
+	users := registerCloneCollection(testClient, UsersURI, "users", "user", []map[string]any{
+		{"id": cloneTestID(1), "name": "alice", "datastore_id": restoreSourceID, "status": "ACTIVE"},
+		{"id": cloneTestID(2), "name": "alice", "datastore_id": datastoreID, "status": "ACTIVE"},
+	}, &nextID)
+	databases := registerCloneCollection(testClient, DatabasesURI, "databases", "database", []map[string]any{
+		{"id": cloneTestID(11), "name": "app", "owner_id": cloneTestID(1), "datastore_id": restoreSourceID},
+		{"id": cloneTestID(12), "name": "app", "owner_id": cloneTestID(2), "datastore_id": datastoreID},
+	}, &nextID)
+	grants := registerCloneCollection(testClient, GrantsURI, "grants", "grant", []map[string]any{
+		{
+			"id": cloneTestID(21), "user_id": cloneTestID(1), "database_id": cloneTestID(11),
+			"datastore_id": restoreSourceID,
+		},
+	}, &nextID)
+	registerCloneCollection(testClient, ExtensionsURI, "extensions", "extension", nil, &nextID)
+	registerCloneCollection(testClient, LogicalReplicationSlotsURI, "logical-replication-slots",
+		"logical-replication-slot", nil, &nextID)
+	opts := CloneOpts{
+		Password:   func(User) (string, error) { return "secret", nil },
+		WaitOpts:   testWaitOpts(),
+		TargetTime: time.Now().Add(-time.Hour),
+	}
+
+	result, err := testClient.CloneDatastore(context.Background(), restoreSourceID, opts)
+
+	require.NoError(t, err)
+	assert.NoError(t, result.Err())
+	require.NotNil(t, datastoreCreated.Restore)
+	assert.Equal(t, restoreSourceID, datastoreCreated.Restore.DatastoreID)
+	assert.Empty(t, users.created)
+	assert.Empty(t, databases.created)
+	assert.Equal(t, []map[string]any{
+		{"user_id": cloneTestID(2), "database_id": cloneTestID(12), "datastore_id": datastoreID},
+	}, grants.created)
+	require.Len(t, result.Objects, 3)
+	assert.Equal(t, CloneExisting, result.Objects[0].Action)
+	assert.Equal(t, CloneExisting, result.Objects[1].Action)
+	assert.Equal(t, CloneCreated, result.Objects[2].Action)
+}
+
+func TestListCloneObjectsFiltersByDatastore(t *testing.T) {
+	httpmock.Activate()
+	testClient := SetupTestClient()
+	defer httpmock.DeactivateAndReset()
+
+	// The responders ignore the datastore_id filter.
+	anyQuery := func(uri string) string {
+		return "=~^" + regexp.QuoteMeta(testClient.Endpoint+uri) + `(\?.*)?$`
+	}
+	httpmock.RegisterResponder("GET", anyQuery(UsersURI), httpmock.NewJsonResponderOrPanic(200, map[string][]User{
+		"users": {
+			{ID: "user-1", DatastoreID: "source", Status: StatusActive},
+			{ID: "user-2", DatastoreID: "other", Status: StatusActive},
+		},
+	}))
+	httpmock.RegisterResponder("GET", anyQuery(DatabasesURI),
+		httpmock.NewJsonResponderOrPanic(200, map[string][]Database{"databases": {}}))
+	httpmock.RegisterResponder("GET", anyQuery(GrantsURI), httpmock.NewJsonResponderOrPanic(200, map[string][]Grant{
+		"grants": {
+			{ID: "grant-1", DatastoreID: "source", Status: StatusActive},
+			{ID: "grant-2", DatastoreID: "other", Status: StatusActive},
+		},
+	}))
+
+	objects, err := testClient.listCloneObjects(context.Background(), "source", EngineMySQL)
+
+	require.NoError(t, err)
+	require.Len(t, objects.users, 1)
+	assert.Equal(t, "user-1", objects.users[0].ID)
+	require.Len(t, objects.grants, 1)
+	assert.Equal(t, "grant-1", objects.grants[0].ID)
+}
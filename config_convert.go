@@ -0,0 +1,281 @@
+package dbaas
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Sizes of memory units in bytes and durations of time units in microseconds.
+const (
+	configUnitByte        = 1
+	configUnitKilobyte    = 1 << 10
+	configUnitMegabyte    = 1 << 20
+	configUnitGigabyte    = 1 << 30
+	configUnitTerabyte    = 1 << 40
+	configUnitMicrosecond = 1
+	configUnitMillisecond = 1000
+	configUnitSecond      = 1000 * configUnitMillisecond
+	configUnitMinute      = 60 * configUnitSecond
+	configUnitHour        = 60 * configUnitMinute
+	configUnitDay         = 24 * configUnitHour
+)
+
+// configUnitKind separates memory and time units, which can't be converted to each other.
+type configUnitKind int
+
+const (
+	configUnitMemory configUnitKind = iota + 1
+	configUnitTime
+)
+
+// Convert converts the configuration values to the types of the configuration parameters.
+// Strings with units like "256MB" or "30s" are converted to the unit of the parameter.
+// Values of parameters unknown to the validator are converted by guessing their type from strings.
+func (v *ConfigValidator) Convert(config map[string]any) (map[string]any, error) {
+	names := make([]string, 0, len(config))
+	for name := range config {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	converted := make(map[string]any, len(config))
+	for _, name := range names {
+		parameter, ok := v.parameters[name]
+		if !ok {
+			converted[name] = convertFieldToType(config[name])
+			continue
+		}
+		value, err := convertConfigValue(parameter, config[name])
+		if err != nil {
+			return nil, err
+		}
+		converted[name] = value
+	}
+
+	return converted, nil
+}
+
+// convertConfig converts the configuration values according to the configuration parameters of the datastore type.
+// The parameters are loaded only if the configuration has strings that could be read as other types.
+// If the datastore type is unknown or its parameters can't be loaded, the types are guessed from strings.
+func (api *API) convertConfig(
+	ctx context.Context,
+	datastoreTypeID string,
+	config map[string]any,
+) (map[string]any, error) {
+	if datastoreTypeID == "" || !needsConfigSchema(config) {
+		return convertConfigValues(config), nil
+	}
+
+	validator, err := api.ConfigValidator(ctx, datastoreTypeID)
+	if err != nil {
+		return convertConfigValues(config), nil //nolint:nilerr // without the schema the types are guessed as before
+	}
+
+	return validator.Convert(config)
+}
+
+// needsConfigSchema checks if the configuration has strings that look like numbers, booleans or values with units.
+func needsConfigSchema(config map[string]any) bool {
+	for _, value := range config {
+		value, ok := value.(string)
+		if !ok {
+			continue
+		}
+		if _, ok := convertFieldFromStringToType(value).(string); !ok {
+			return true
+		}
+		if _, _, ok := splitConfigUnitValue(value); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// convertConfigValue converts the value to the type of the configuration parameter.
+// Null values reset parameters to their defaults and are kept as is.
+func convertConfigValue(parameter ConfigurationParameter, value any) (any, error) {
+	if value == nil {
+		return nil, nil
+	}
+
+	switch parameter.Type {
+	case ConfigParameterTypeInt:
+		return convertConfigInt(parameter, value)
+	case ConfigParameterTypeFloat:
+		return convertConfigFloat(parameter, value)
+	case ConfigParameterTypeBoolean:
+		if converted, ok := convertConfigBoolean(value); ok {
+			return converted, nil
+		}
+	case ConfigParameterTypeString:
+		if converted, ok := convertConfigString(value); ok {
+			return converted, nil
+		}
+	default:
+		return convertFieldToType(value), nil
+	}
+
+	return nil, fmt.Errorf("%w: %s: value %v is not of type %s",
+		ErrInvalidConfig, parameter.Name, value, parameter.Type)
+}
+
+// convertConfigInt converts integers, whole numbers and strings with units to int.
+func convertConfigInt(parameter ConfigurationParameter, value any) (any, error) {
+	text, isString := value.(string)
+	if !isString {
+		if number, ok := configNumber(value); ok && number == math.Trunc(number) {
+			return value, nil
+		}
+		return nil, fmt.Errorf("%w: %s: value %v is not of type %s",
+			ErrInvalidConfig, parameter.Name, value, parameter.Type)
+	}
+	if number, err := strconv.Atoi(strings.TrimSpace(text)); err == nil {
+		return number, nil
+	}
+
+	number, err := parseConfigNumber(parameter, text)
+	if err != nil {
+		return nil, err
+	}
+	if number != math.Trunc(number) || math.Abs(number) > math.MaxInt64 {
+		if parameter.Unit == "" {
+			return nil, fmt.Errorf("%w: %s: value %s is not of type %s",
+				ErrInvalidConfig, parameter.Name, text, parameter.Type)
+		}
+		return nil, fmt.Errorf("%w: %s: value %s is not a whole number of %s",
+			ErrInvalidConfig, parameter.Name, text, parameter.Unit)
+	}
+
+	return int(number), nil
+}
+
+// convertConfigFloat converts numbers and strings with units to float64.
+func convertConfigFloat(parameter ConfigurationParameter, value any) (any, error) {
+	text, isString := value.(string)
+	if !isString {
+		if _, ok := configNumber(value); ok {
+			return value, nil
+		}
+		return nil, fmt.Errorf("%w: %s: value %v is not of type %s",
+			ErrInvalidConfig, parameter.Name, value, parameter.Type)
+	}
+
+	return parseConfigNumber(parameter, text)
+}
+
+// convertConfigBoolean converts booleans and their string forms, including "on" and "off", to bool.
+func convertConfigBoolean(value any) (bool, bool) {
+	switch value := value.(type) {
+	case bool:
+		return value, true
+	case string:
+		switch strings.ToLower(strings.TrimSpace(value)) {
+		case "on", "yes":
+			return true, true
+		case "off", "no":
+			return false, true
+		}
+		converted, err := strconv.ParseBool(strings.TrimSpace(value))
+		return converted, err == nil
+	default:
+		return false, false
+	}
+}
+
+// convertConfigString converts strings and numbers to string.
+func convertConfigString(value any) (string, bool) {
+	if value, ok := value.(string); ok {
+		return value, true
+	}
+	if _, ok := configNumber(value); ok {
+		return fmt.Sprint(value), true
+	}
+	return "", false
+}
+
+// parseConfigNumber parses the number, converting it to the unit of the configuration parameter if it has a unit.
+func parseConfigNumber(parameter ConfigurationParameter, text string) (float64, error) {
+	number, unit, ok := splitConfigUnitValue(text)
+	if !ok {
+		return 0, fmt.Errorf("%w: %s: value %s is not of type %s",
+			ErrInvalidConfig, parameter.Name, text, parameter.Type)
+	}
+	if unit == "" {
+		return number, nil
+	}
+
+	valueKind, valueSize, ok := parseConfigUnit(unit)
+	if !ok {
+		return 0, fmt.Errorf("%w: %s: unknown unit %s", ErrInvalidConfig, parameter.Name, unit)
+	}
+	if parameter.Unit == "" {
+		return 0, fmt.Errorf("%w: %s: parameter has no unit, got %s", ErrInvalidConfig, parameter.Name, text)
+	}
+	parameterKind, parameterSize, ok := parseConfigUnit(parameter.Unit)
+	if !ok || parameterKind != valueKind {
+		return 0, fmt.Errorf("%w: %s: unit %s can't be converted to %s",
+			ErrInvalidConfig, parameter.Name, unit, parameter.Unit)
+	}
+
+	return number * valueSize / parameterSize, nil
+}
+
+// splitConfigUnitValue splits strings like "256MB" or "1.5 h" into the number and the unit.
+// The unit is empty for plain numbers.
+func splitConfigUnitValue(text string) (float64, string, bool) {
+	text = strings.TrimSpace(text)
+	numberText := strings.TrimRightFunc(text, unicode.IsLetter)
+	unit := text[len(numberText):]
+	number, err := strconv.ParseFloat(strings.TrimSpace(numberText), 64)
+	if err != nil || math.IsInf(number, 0) || math.IsNaN(number) {
+		return 0, "", false
+	}
+
+	return number, unit, true
+}
+
+// parseConfigUnit returns the kind and the size of units like "kB", "8kB", "ms" or "min".
+func parseConfigUnit(unit string) (configUnitKind, float64, bool) {
+	base := strings.TrimLeftFunc(unit, unicode.IsDigit)
+	multiplier := 1.0
+	if base != unit {
+		number, err := strconv.Atoi(unit[:len(unit)-len(base)])
+		if err != nil {
+			return 0, 0, false
+		}
+		multiplier = float64(number)
+	}
+
+	switch base {
+	case "B":
+		return configUnitMemory, multiplier * configUnitByte, true
+	case "kB":
+		return configUnitMemory, multiplier * configUnitKilobyte, true
+	case "MB":
+		return configUnitMemory, multiplier * configUnitMegabyte, true
+	case "GB":
+		return configUnitMemory, multiplier * configUnitGigabyte, true
+	case "TB":
+		return configUnitMemory, multiplier * configUnitTerabyte, true
+	case "us":
+		return configUnitTime, multiplier * configUnitMicrosecond, true
+	case "ms":
+		return configUnitTime, multiplier * configUnitMillisecond, true
+	case "s":
+		return configUnitTime, multiplier * configUnitSecond, true
+	case "min":
+		return configUnitTime, multiplier * configUnitMinute, true
+	case "h":
+		return configUnitTime, multiplier * configUnitHour, true
+	case "d":
+		return configUnitTime, multiplier * configUnitDay, true
+	default:
+		return 0, 0, false
+	}
+}
@@ -0,0 +1,215 @@
+package dbaas
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testConverterParameters() []ConfigurationParameter {
+	parameter := func(name, parameterType, unit string) ConfigurationParameter {
+		return ConfigurationParameter{
+			DatastoreTypeID: testValidatorDatastoreTypeID, Name: name, Type: parameterType, Unit: unit,
+		}
+	}
+
+	return []ConfigurationParameter{
+		parameter("work_mem", ConfigParameterTypeInt, "kB"),
+		parameter("shared_buffers", ConfigParameterTypeInt, "8kB"),
+		parameter("statement_timeout", ConfigParameterTypeInt, "ms"),
+		parameter("max_connections", ConfigParameterTypeInt, ""),
+		parameter("long_query_time", ConfigParameterTypeFloat, "s"),
+		parameter("jit", ConfigParameterTypeBoolean, ""),
+		parameter("concurrent_insert", ConfigParameterTypeString, ""),
+	}
+}
+
+func TestConfigValidatorConvert(t *testing.T) {
+	validator := NewConfigValidator(testValidatorDatastoreTypeID, testConverterParameters())
+
+	actual, err := validator.Convert(map[string]any{
+		"work_mem":          "256MB",
+		"shared_buffers":    "1GB",
+		"statement_timeout": "30s",
+		"max_connections":   "200",
+		"long_query_time":   "1500ms",
+		"jit":               "off",
+		"concurrent_insert": 2,
+		"unknown":           "10",
+		"reset":             nil,
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{
+		"work_mem":          262144,
+		"shared_buffers":    131072,
+		"statement_timeout": 30000,
+		"max_connections":   200,
+		"long_query_time":   1.5,
+		"jit":               false,
+		"concurrent_insert": "2",
+		"unknown":           10,
+		"reset":             nil,
+	}, actual)
+}
+
+func TestConfigValidatorConvertErrors(t *testing.T) {
+	validator := NewConfigValidator(testValidatorDatastoreTypeID, testConverterParameters())
+
+	tests := []struct {
+		config  map[string]any
+		message string
+	}{
+		{
+			config:  map[string]any{"work_mem": "30s"},
+			message: "work_mem: unit s can't be converted to kB",
+		},
+		{
+			config:  map[string]any{"work_mem": "1000B"},
+			message: "work_mem: value 1000B is not a whole number of kB",
+		},
+		{
+			config:  map[string]any{"work_mem": "256XB"},
+			message: "work_mem: unknown unit XB",
+		},
+		{
+			config:  map[string]any{"max_connections": "10MB"},
+			message: "max_connections: parameter has no unit, got 10MB",
+		},
+		{
+			config:  map[string]any{"max_connections": 1.5},
+			message: "max_connections: value 1.5 is not of type int",
+		},
+		{
+			config:  map[string]any{"long_query_time": true},
+			message: "long_query_time: value true is not of type float",
+		},
+		{
+			config:  map[string]any{"jit": "1.5"},
+			message: "jit: value 1.5 is not of type boolean",
+		},
+		{
+			config:  map[string]any{"concurrent_insert": false},
+			message: "concurrent_insert: value false is not of type str",
+		},
+	}
+
+	for _, test := range tests {
+		_, err := validator.Convert(test.config)
+		require.Error(t, err)
+		assert.ErrorIs(t, err, ErrInvalidConfig)
+		assert.Contains(t, err.Error(), test.message)
+	}
+}
+
+func TestConfigDatastoreConvertsConfig(t *testing.T) {
+	httpmock.Activate()
+	testClient := SetupTestClient()
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder("GET", testClient.Endpoint+DatastoresURI+"/"+datastoreID,
+		httpmock.NewStringResponder(200, testDatastoreResponse))
+	httpmock.RegisterResponder("GET", testClient.Endpoint+ConfigurationParametersURI,
+		httpmock.NewStringResponder(200, testConfigurationParametersResponse))
+	httpmock.RegisterResponder("PUT", testClient.Endpoint+DatastoresURI+"/"+datastoreID+"/config",
+		func(req *http.Request) (*http.Response, error) {
+			var opts DatastoreConfigOpts
+			if err := json.NewDecoder(req.Body).Decode(&opts); err != nil {
+				return httpmock.NewStringResponse(400, ""), err
+			}
+			assert.Equal(t, map[string]any{
+				"temp_file_limit":   1048576.0,
+				"concurrent_insert": "2",
+				"work_mem":          256.0,
+			}, opts.Config)
+
+			return httpmock.NewJsonResponse(200, map[string]Datastore{"datastore": datastoreUpdateConfigResponse})
+		})
+
+	configDatastoreOpts := DatastoreConfigOpts{
+		Config: map[string]any{
+			"temp_file_limit":   "1GB",
+			"concurrent_insert": 2,
+			"work_mem":          "256",
+		},
+	}
+
+	actual, err := testClient.ConfigDatastore(context.Background(), datastoreID, configDatastoreOpts)
+
+	require.NoError(t, err)
+	assert.Equal(t, datastoreUpdateConfigExpected, actual)
+	assert.Equal(t, 3, httpmock.GetTotalCallCount())
+}
+
+func TestConfigDatastoreConvertError(t *testing.T) {
+	httpmock.Activate()
+	testClient := SetupTestClient()
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder("GET", testClient.Endpoint+DatastoresURI+"/"+datastoreID,
+		httpmock.NewStringResponder(200, testDatastoreResponse))
+	httpmock.RegisterResponder("GET", testClient.Endpoint+ConfigurationParametersURI,
+		httpmock.NewStringResponder(200, testConfigurationParametersResponse))
+
+	configDatastoreOpts := DatastoreConfigOpts{
+		Config: map[string]any{"temp_file_limit": "30s"},
+	}
+
+	_, err := testClient.ConfigDatastore(context.Background(), datastoreID, configDatastoreOpts)
+
+	require.Error(t, err)
+	assert.ErrorIs(t, err, ErrInvalidConfig)
+	assert.Equal(t, 2, httpmock.GetTotalCallCount())
+}
+
+func TestConfigDatastoreWithoutSchema(t *testing.T) {
+	tests := []struct {
+		name  string
+		calls int
+	}{
+		{"datastore", 2},
+		{"configuration parameters", 3},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			httpmock.Activate()
+			testClient := SetupTestClient()
+			defer httpmock.DeactivateAndReset()
+
+			forbidden := httpmock.NewStringResponder(403, `{"error": {"code": 403, "title": "Forbidden"}}`)
+			datastoreResponder := httpmock.NewStringResponder(200, testDatastoreResponse)
+			parametersResponder := forbidden
+			if test.name == "datastore" {
+				datastoreResponder = forbidden
+			}
+			httpmock.RegisterResponder("GET", testClient.Endpoint+DatastoresURI+"/"+datastoreID, datastoreResponder)
+			httpmock.RegisterResponder("GET", testClient.Endpoint+ConfigurationParametersURI, parametersResponder)
+			httpmock.RegisterResponder("PUT", testClient.Endpoint+DatastoresURI+"/"+datastoreID+"/config",
+				func(req *http.Request) (*http.Response, error) {
+					var opts DatastoreConfigOpts
+					if err := json.NewDecoder(req.Body).Decode(&opts); err != nil {
+						return httpmock.NewStringResponse(400, ""), err
+					}
+					assert.Equal(t, map[string]any{"work_mem": 256.0, "jit": true}, opts.Config)
+
+					return httpmock.NewJsonResponse(200,
+						map[string]Datastore{"datastore": datastoreUpdateConfigResponse})
+				})
+
+			configDatastoreOpts := DatastoreConfigOpts{
+				Config: map[string]any{"work_mem": "256", "jit": "true"},
+			}
+
+			_, err := testClient.ConfigDatastore(context.Background(), datastoreID, configDatastoreOpts)
+
+			require.NoError(t, err)
+			assert.Equal(t, test.calls, httpmock.GetTotalCallCount())
+		})
+	}
+}
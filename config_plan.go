@@ -0,0 +1,219 @@
+package dbaas
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// ConfigChangeAction is an action that a configuration plan takes on a parameter.
+type ConfigChangeAction string
+
+const (
+	ConfigAdded     ConfigChangeAction = "added"
+	ConfigChanged   ConfigChangeAction = "changed"
+	ConfigReset     ConfigChangeAction = "reset"
+	ConfigUnchanged ConfigChangeAction = "unchanged"
+)
+
+// ConfigChange describes a change of a configuration parameter.
+// OldValue is nil for added parameters, NewValue is the default value for reset parameters.
+type ConfigChange struct {
+	OldValue          any                `json:"old_value"`
+	NewValue          any                `json:"new_value"`
+	Parameter         string             `json:"parameter"`
+	Action            ConfigChangeAction `json:"action"`
+	IsRestartRequired bool               `json:"is_restart_required"`
+}
+
+// String returns a human-readable description of the change.
+func (c ConfigChange) String() string {
+	var line string
+	switch c.Action {
+	case ConfigAdded:
+		line = fmt.Sprintf("+ %s = %v", c.Parameter, c.NewValue)
+	case ConfigChanged:
+		line = fmt.Sprintf("~ %s = %v -> %v", c.Parameter, c.OldValue, c.NewValue)
+	case ConfigReset:
+		line = fmt.Sprintf("- %s = %v -> default %v", c.Parameter, c.OldValue, c.NewValue)
+	default:
+		line = fmt.Sprintf("  %s = %v", c.Parameter, c.OldValue)
+	}
+	if c.IsRestartRequired && c.Action != ConfigUnchanged {
+		line += " (restart required)"
+	}
+	return line
+}
+
+// ConfigPlan represents changes required to bring the datastore configuration to the desired one.
+type ConfigPlan struct {
+	DatastoreID string `json:"datastore_id"`
+
+	// Changes are sorted by parameter names.
+	Changes []ConfigChange `json:"changes"`
+}
+
+// NewConfigPlan compares the configuration of the datastore with the desired configuration.
+// Parameters that are missing in the desired configuration or have null values are reset to their defaults.
+// Desired values are converted to the types of the configuration parameters of the datastore type.
+func NewConfigPlan(
+	datastore Datastore,
+	desired map[string]any,
+	parameters []ConfigurationParameter,
+) (ConfigPlan, error) {
+	validator := NewConfigValidator(datastore.TypeID, parameters)
+	desired, err := validator.Convert(desired)
+	if err != nil {
+		return ConfigPlan{}, err
+	}
+
+	plan := ConfigPlan{DatastoreID: datastore.ID, Changes: []ConfigChange{}}
+	for name, oldValue := range datastore.Config {
+		parameter, _ := validator.Parameter(name)
+		change := ConfigChange{
+			OldValue:          oldValue,
+			NewValue:          desired[name],
+			Parameter:         name,
+			Action:            ConfigChanged,
+			IsRestartRequired: parameter.IsRestartRequired,
+		}
+		switch {
+		case change.NewValue == nil:
+			change.Action = ConfigReset
+			change.NewValue = parameter.DefaultValue
+		case configValuesEqual(oldValue, change.NewValue):
+			change.Action = ConfigUnchanged
+		}
+		plan.Changes = append(plan.Changes, change)
+	}
+	for name, newValue := range desired {
+		if _, ok := datastore.Config[name]; ok || newValue == nil {
+			continue
+		}
+		parameter, _ := validator.Parameter(name)
+		plan.Changes = append(plan.Changes, ConfigChange{
+			NewValue:          newValue,
+			Parameter:         name,
+			Action:            ConfigAdded,
+			IsRestartRequired: parameter.IsRestartRequired,
+		})
+	}
+
+	sort.Slice(plan.Changes, func(i, j int) bool {
+		return plan.Changes[i].Parameter < plan.Changes[j].Parameter
+	})
+
+	return plan, nil
+}
+
+// PlanConfig loads the configuration parameters of the datastore type and creates a plan
+// to bring the datastore configuration to the desired one.
+func (api *API) PlanConfig(ctx context.Context, datastore Datastore, desired map[string]any) (ConfigPlan, error) {
+	parameters, err := api.ConfigurationParameters(ctx, &ConfigurationParameterQueryParams{
+		DatastoreTypeID: datastore.TypeID,
+	})
+	if err != nil {
+		return ConfigPlan{}, err
+	}
+
+	return NewConfigPlan(datastore, desired, parameters)
+}
+
+// ByAction returns changes with the given action.
+func (p ConfigPlan) ByAction(action ConfigChangeAction) []ConfigChange {
+	var changes []ConfigChange
+	for _, change := range p.Changes {
+		if change.Action == action {
+			changes = append(changes, change)
+		}
+	}
+	return changes
+}
+
+// HasChanges reports whether the plan adds, changes or resets any parameters.
+func (p ConfigPlan) HasChanges() bool {
+	return len(p.ByAction(ConfigUnchanged)) != len(p.Changes)
+}
+
+// RestartRequired reports whether applying the plan causes a datastore restart.
+func (p ConfigPlan) RestartRequired() bool {
+	for _, change := range p.Changes {
+		if change.Action != ConfigUnchanged && change.IsRestartRequired {
+			return true
+		}
+	}
+	return false
+}
+
+// Delta returns the configuration that applies the plan: new values of added and changed parameters
+// and null values of reset parameters.
+func (p ConfigPlan) Delta() map[string]any {
+	delta := make(map[string]any)
+	for _, change := range p.Changes {
+		switch change.Action {
+		case ConfigAdded, ConfigChanged:
+			delta[change.Parameter] = change.NewValue
+		case ConfigReset:
+			delta[change.Parameter] = nil
+		}
+	}
+	return delta
+}
+
+// String returns the plan as text with a line per parameter and a summary.
+func (p ConfigPlan) String() string {
+	var builder strings.Builder
+	for _, change := range p.Changes {
+		builder.WriteString(change.String())
+		builder.WriteString("\n")
+	}
+	fmt.Fprintf(&builder, "Plan: %d to add, %d to change, %d to reset, %d unchanged.",
+		len(p.ByAction(ConfigAdded)), len(p.ByAction(ConfigChanged)),
+		len(p.ByAction(ConfigReset)), len(p.ByAction(ConfigUnchanged)))
+	if p.RestartRequired() {
+		builder.WriteString(" Datastore restart required.")
+	}
+	return builder.String()
+}
+
+// ApplyConfigPlan sends the delta of the plan to the datastore.
+// The datastore is returned without changes if the plan has nothing to apply.
+func (api *API) ApplyConfigPlan(ctx context.Context, plan ConfigPlan) (Datastore, error) {
+	if err := uuid.Validate(plan.DatastoreID); err != nil {
+		return Datastore{}, fmt.Errorf("validate datastore id: %w", err)
+	}
+	if !plan.HasChanges() {
+		return api.Datastore(ctx, plan.DatastoreID)
+	}
+
+	return api.sendDatastoreConfig(ctx, plan.DatastoreID, DatastoreConfigOpts{Config: plan.Delta()})
+}
+
+// ApplyConfigPlanAndWait sends the delta of the plan to the datastore and waits until it becomes active.
+func (api *API) ApplyConfigPlanAndWait(ctx context.Context, plan ConfigPlan, waitOpts *WaitOpts) (Datastore, error) {
+	datastore, err := api.ApplyConfigPlan(ctx, plan)
+	if err != nil {
+		return Datastore{}, err
+	}
+	if datastore.Status == StatusActive {
+		return datastore, nil
+	}
+
+	return api.WaitForDatastoreStatus(ctx, plan.DatastoreID, []Status{StatusActive}, waitOpts)
+}
+
+// configValuesEqual compares configuration values, numbers are compared regardless of their types.
+func configValuesEqual(a, b any) bool {
+	_, aIsString := a.(string)
+	_, bIsString := b.(string)
+	aNumber, aIsNumber := configNumber(a)
+	bNumber, bIsNumber := configNumber(b)
+	if aIsNumber && bIsNumber && !aIsString && !bIsString {
+		return aNumber == bNumber
+	}
+	return reflect.DeepEqual(a, b)
+}
@@ -0,0 +1,194 @@
+package dbaas
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testPlanParameters() []ConfigurationParameter {
+	return []ConfigurationParameter{
+		{
+			DatastoreTypeID: testValidatorDatastoreTypeID, Name: "work_mem", Type: ConfigParameterTypeInt,
+			Unit: "kB", DefaultValue: 4096.0,
+		},
+		{
+			DatastoreTypeID: testValidatorDatastoreTypeID, Name: "shared_buffers", Type: ConfigParameterTypeInt,
+			Unit: "8kB", DefaultValue: 16384.0, IsRestartRequired: true,
+		},
+		{
+			DatastoreTypeID: testValidatorDatastoreTypeID, Name: "jit", Type: ConfigParameterTypeBoolean,
+			DefaultValue: true,
+		},
+		{
+			DatastoreTypeID: testValidatorDatastoreTypeID, Name: "max_connections", Type: ConfigParameterTypeInt,
+			DefaultValue: 100.0, IsRestartRequired: true,
+		},
+	}
+}
+
+func testPlanDatastore() Datastore {
+	return Datastore{
+		ID:     datastoreID,
+		TypeID: testValidatorDatastoreTypeID,
+		Config: map[string]any{
+			"work_mem":        8192.0,
+			"shared_buffers":  16384.0,
+			"jit":             false,
+			"max_connections": 200.0,
+		},
+	}
+}
+
+func TestNewConfigPlan(t *testing.T) {
+	plan, err := NewConfigPlan(testPlanDatastore(), map[string]any{
+		"work_mem":         "8MB",
+		"shared_buffers":   "256MB",
+		"max_connections":  nil,
+		"random_page_cost": 1.1,
+	}, testPlanParameters())
+
+	require.NoError(t, err)
+	assert.Equal(t, ConfigPlan{
+		DatastoreID: datastoreID,
+		Changes: []ConfigChange{
+			{OldValue: false, NewValue: true, Parameter: "jit", Action: ConfigReset},
+			{
+				OldValue: 200.0, NewValue: 100.0, Parameter: "max_connections", Action: ConfigReset,
+				IsRestartRequired: true,
+			},
+			{NewValue: 1.1, Parameter: "random_page_cost", Action: ConfigAdded},
+			{
+				OldValue: 16384.0, NewValue: 32768, Parameter: "shared_buffers", Action: ConfigChanged,
+				IsRestartRequired: true,
+			},
+			{OldValue: 8192.0, NewValue: 8192, Parameter: "work_mem", Action: ConfigUnchanged},
+		},
+	}, plan)
+	assert.True(t, plan.HasChanges())
+	assert.True(t, plan.RestartRequired())
+	assert.Equal(t, map[string]any{
+		"jit":              nil,
+		"max_connections":  nil,
+		"random_page_cost": 1.1,
+		"shared_buffers":   32768,
+	}, plan.Delta())
+	assert.Len(t, plan.ByAction(ConfigReset), 2)
+}
+
+func TestNewConfigPlanWithoutChanges(t *testing.T) {
+	plan, err := NewConfigPlan(testPlanDatastore(), testPlanDatastore().Config, testPlanParameters())
+
+	require.NoError(t, err)
+	assert.Len(t, plan.Changes, 4)
+	assert.False(t, plan.HasChanges())
+	assert.False(t, plan.RestartRequired())
+	assert.Empty(t, plan.Delta())
+}
+
+func TestNewConfigPlanInvalidValue(t *testing.T) {
+	_, err := NewConfigPlan(testPlanDatastore(), map[string]any{"work_mem": "30s"}, testPlanParameters())
+
+	assert.ErrorIs(t, err, ErrInvalidConfig)
+}
+
+func TestConfigPlanRender(t *testing.T) {
+	plan, err := NewConfigPlan(testPlanDatastore(), map[string]any{
+		"work_mem":       16384,
+		"shared_buffers": 16384,
+		"jit":            false,
+		"autovacuum":     "off",
+	}, testPlanParameters())
+	require.NoError(t, err)
+
+	expectedText := `+ autovacuum = off
+  jit = false
+- max_connections = 200 -> default 100 (restart required)
+  shared_buffers = 16384
+~ work_mem = 8192 -> 16384
+Plan: 1 to add, 1 to change, 1 to reset, 2 unchanged. Datastore restart required.`
+	assert.Equal(t, expectedText, plan.String())
+
+	body, err := json.Marshal(plan)
+	require.NoError(t, err)
+	assert.Contains(t, string(body), `"datastore_id":"`+datastoreID+`"`)
+	assert.Contains(t, string(body),
+		`{"old_value":8192,"new_value":16384,"parameter":"work_mem","action":"changed","is_restart_required":false}`)
+}
+
+func TestApplyConfigPlanAndWait(t *testing.T) {
+	httpmock.Activate()
+	testClient := SetupTestClient()
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder("PUT", testClient.Endpoint+DatastoresURI+"/"+datastoreID+"/config",
+		func(req *http.Request) (*http.Response, error) {
+			var opts DatastoreConfigOpts
+			if err := json.NewDecoder(req.Body).Decode(&opts); err != nil {
+				return httpmock.NewStringResponse(400, ""), err
+			}
+			assert.Equal(t, map[string]any{"work_mem": 16384.0, "max_connections": nil}, opts.Config)
+
+			return httpmock.NewJsonResponse(200, map[string]Datastore{"datastore": datastoreUpdateConfigResponse})
+		})
+	httpmock.RegisterResponder("GET", testClient.Endpoint+DatastoresURI+"/"+datastoreID,
+		httpmock.NewStringResponder(200, testDatastoreResponse))
+
+	plan, err := NewConfigPlan(testPlanDatastore(), map[string]any{
+		"work_mem":       "16MB",
+		"shared_buffers": 16384,
+		"jit":            false,
+	}, testPlanParameters())
+	require.NoError(t, err)
+
+	actual, err := testClient.ApplyConfigPlanAndWait(context.Background(), plan, &WaitOpts{Interval: time.Millisecond})
+
+	require.NoError(t, err)
+	assert.Equal(t, StatusActive, actual.Status)
+	assert.Equal(t, 2, httpmock.GetTotalCallCount())
+}
+
+func TestApplyConfigPlanWithoutChanges(t *testing.T) {
+	httpmock.Activate()
+	testClient := SetupTestClient()
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder("GET", testClient.Endpoint+DatastoresURI+"/"+datastoreID,
+		httpmock.NewStringResponder(200, testDatastoreResponse))
+
+	plan, err := NewConfigPlan(testPlanDatastore(), testPlanDatastore().Config, testPlanParameters())
+	require.NoError(t, err)
+
+	actual, err := testClient.ApplyConfigPlan(context.Background(), plan)
+
+	require.NoError(t, err)
+	assert.Equal(t, datastoreID, actual.ID)
+	assert.Equal(t, 1, httpmock.GetTotalCallCount())
+}
+
+func TestPlanConfig(t *testing.T) {
+	httpmock.Activate()
+	testClient := SetupTestClient()
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder("GET", testClient.Endpoint+ConfigurationParametersURI,
+		httpmock.NewStringResponder(200, testConfigurationParametersResponse))
+
+	datastore := Datastore{
+		ID:     datastoreID,
+		TypeID: testValidatorDatastoreTypeID,
+		Config: map[string]any{"temp_file_limit": 1024.0},
+	}
+	plan, err := testClient.PlanConfig(context.Background(), datastore, map[string]any{"temp_file_limit": "1MB"})
+
+	require.NoError(t, err)
+	assert.Equal(t, []ConfigChange{{
+		OldValue: 1024.0, NewValue: 1024, Parameter: "temp_file_limit", Action: ConfigUnchanged,
+	}}, plan.Changes)
+}
@@ -0,0 +1,288 @@
+package dbaas
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+)
+
+// ErrUnknownPreset is returned if the configuration preset is not defined for the engine.
+var ErrUnknownPreset = errors.New("unknown config preset")
+
+// ConfigPreset is a named profile that computes configuration values from the flavor resources.
+type ConfigPreset string
+
+const (
+	// ConfigPresetOLTP tunes PostgreSQL and MySQL for many short transactions.
+	ConfigPresetOLTP ConfigPreset = "oltp"
+
+	// ConfigPresetAnalytics tunes PostgreSQL and MySQL for few connections running heavy queries.
+	ConfigPresetAnalytics ConfigPreset = "analytics"
+
+	// ConfigPresetSmallDev keeps PostgreSQL and MySQL resource usage low for development datastores.
+	ConfigPresetSmallDev ConfigPreset = "small-dev"
+
+	// ConfigPresetCache configures Redis to evict keys when the memory is full.
+	ConfigPresetCache ConfigPreset = "cache"
+
+	// ConfigPresetPersistent configures Redis to keep all keys and reject writes when the memory is full.
+	ConfigPresetPersistent ConfigPreset = "persistent"
+)
+
+// Memory limits used by the presets.
+const (
+	presetMaintenanceWorkMemLimit = 2 * configUnitGigabyte
+	presetTmpTableSizeLimit       = configUnitGigabyte
+	presetInnodbLogFileSizeLimit  = 2 * configUnitGigabyte
+)
+
+// presetMemory is a memory size in bytes, which is converted to the unit of the configuration parameter.
+type presetMemory float64
+
+// Flavor returns the resources of the flavor in the form accepted by DatastoreCreateOpts.
+func (f FlavorResponse) Flavor() Flavor {
+	return Flavor{Vcpus: f.Vcpus, RAM: f.RAM, Disk: f.Disk}
+}
+
+// ConfigPresets returns the configuration presets defined for the engine.
+func ConfigPresets(engine string) []ConfigPreset {
+	switch engine {
+	case EnginePostgreSQL, EngineMySQL:
+		return []ConfigPreset{ConfigPresetOLTP, ConfigPresetAnalytics, ConfigPresetSmallDev}
+	case EngineRedis:
+		return []ConfigPreset{ConfigPresetCache, ConfigPresetPersistent}
+	default:
+		return nil
+	}
+}
+
+// PresetConfig computes the configuration of the preset for the datastore type and the flavor.
+// Memory sizes are converted to the units of the configuration parameters, bytes if a parameter has no unit,
+// and all values are clamped to the parameters min and max. Parameters that the datastore type doesn't have
+// or can't change are skipped.
+// The result can be used in DatastoreCreateOpts and DatastoreConfigOpts.
+func PresetConfig(
+	preset ConfigPreset,
+	datastoreType DatastoreType,
+	flavor Flavor,
+	parameters []ConfigurationParameter,
+) (map[string]any, error) {
+	values, ok := presetValues(preset, datastoreType.Engine, flavor)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s for %s", ErrUnknownPreset, preset, datastoreType.Engine)
+	}
+
+	validator := NewConfigValidator(datastoreType.ID, parameters)
+	config := make(map[string]any, len(values))
+	for name, value := range values {
+		parameter, ok := validator.Parameter(name)
+		if !ok {
+			continue
+		}
+		if memory, ok := value.(presetMemory); ok {
+			unit := parameter.Unit
+			if unit == "" {
+				// Memory parameters without a unit, like MySQL buffer sizes, are set in bytes.
+				unit = "B"
+			}
+			kind, size, ok := parseConfigUnit(unit)
+			if !ok || kind != configUnitMemory {
+				continue
+			}
+			value = int(math.Floor(float64(memory) / size))
+		}
+		var err error
+		value, err = convertConfigValue(parameter, value)
+		if err != nil {
+			return nil, err
+		}
+		value = clampConfigValue(parameter, value)
+		if _, ok := validateConfigValue(parameter, value); !ok {
+			continue
+		}
+		config[name] = value
+	}
+
+	return config, nil
+}
+
+// PresetConfig loads the datastore type with its configuration parameters and computes the configuration
+// of the preset for the flavor.
+func (api *API) PresetConfig(
+	ctx context.Context,
+	preset ConfigPreset,
+	datastoreTypeID string,
+	flavor Flavor,
+) (map[string]any, error) {
+	datastoreType, err := api.DatastoreType(ctx, datastoreTypeID)
+	if err != nil {
+		return nil, err
+	}
+	parameters, err := api.ConfigurationParameters(ctx, &ConfigurationParameterQueryParams{
+		DatastoreTypeID: datastoreTypeID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return PresetConfig(preset, datastoreType, flavor, parameters)
+}
+
+// clampConfigValue limits the numeric value with the min and max of the configuration parameter.
+func clampConfigValue(parameter ConfigurationParameter, value any) any {
+	number, ok := configNumber(value)
+	if !ok {
+		return value
+	}
+	if minimum, ok := configNumber(parameter.Min); ok && number < minimum {
+		number = minimum
+	} else if maximum, ok := configNumber(parameter.Max); ok && number > maximum {
+		number = maximum
+	} else {
+		return value
+	}
+	if parameter.Type == ConfigParameterTypeInt {
+		return int(number)
+	}
+	return number
+}
+
+// presetValues returns the configuration values of the preset for the engine.
+func presetValues(preset ConfigPreset, engine string, flavor Flavor) (map[string]any, bool) {
+	ram := float64(flavor.RAM) * configUnitMegabyte
+	switch engine {
+	case EnginePostgreSQL:
+		return postgreSQLPresetValues(preset, ram, flavor.Vcpus)
+	case EngineMySQL:
+		return mySQLPresetValues(preset, ram, flavor.Vcpus)
+	case EngineRedis:
+		return redisPresetValues(preset)
+	default:
+		return nil, false
+	}
+}
+
+// postgreSQLPresetValues returns PostgreSQL configuration values for the RAM in bytes and the number of vCPUs.
+func postgreSQLPresetValues(preset ConfigPreset, ram float64, vcpus int) (map[string]any, bool) {
+	switch preset {
+	case ConfigPresetOLTP:
+		maxConnections := 100 + 50*vcpus
+		sharedBuffers := ram / 4
+		return map[string]any{
+			"max_connections":                 maxConnections,
+			"shared_buffers":                  presetMemory(sharedBuffers),
+			"effective_cache_size":            presetMemory(ram * 3 / 4),
+			"work_mem":                        presetMemory((ram - sharedBuffers) / float64(3*maxConnections)),
+			"maintenance_work_mem":            presetMemory(math.Min(ram/20, presetMaintenanceWorkMemLimit)),
+			"min_wal_size":                    presetMemory(configUnitGigabyte),
+			"max_wal_size":                    presetMemory(4 * configUnitGigabyte),
+			"max_worker_processes":            maxInt(8, vcpus),
+			"max_parallel_workers":            vcpus,
+			"max_parallel_workers_per_gather": vcpus / 4,
+			"effective_io_concurrency":        200,
+			"random_page_cost":                1.1,
+		}, true
+	case ConfigPresetAnalytics:
+		maxConnections := 20 + 10*vcpus
+		sharedBuffers := ram / 4
+		return map[string]any{
+			"max_connections":                 maxConnections,
+			"shared_buffers":                  presetMemory(sharedBuffers),
+			"effective_cache_size":            presetMemory(ram * 3 / 4),
+			"work_mem":                        presetMemory((ram - sharedBuffers) / float64(2*maxConnections)),
+			"maintenance_work_mem":            presetMemory(math.Min(ram/10, presetMaintenanceWorkMemLimit)),
+			"min_wal_size":                    presetMemory(4 * configUnitGigabyte),
+			"max_wal_size":                    presetMemory(16 * configUnitGigabyte),
+			"max_worker_processes":            maxInt(8, vcpus),
+			"max_parallel_workers":            vcpus,
+			"max_parallel_workers_per_gather": maxInt(1, vcpus/2),
+			"default_statistics_target":       500,
+			"effective_io_concurrency":        200,
+			"random_page_cost":                1.1,
+		}, true
+	case ConfigPresetSmallDev:
+		return map[string]any{
+			"max_connections":                 50,
+			"shared_buffers":                  presetMemory(ram * 15 / 100),
+			"effective_cache_size":            presetMemory(ram / 2),
+			"work_mem":                        presetMemory(4 * configUnitMegabyte),
+			"maintenance_work_mem":            presetMemory(64 * configUnitMegabyte),
+			"max_parallel_workers_per_gather": 0,
+			"jit":                             false,
+		}, true
+	default:
+		return nil, false
+	}
+}
+
+// mySQLPresetValues returns MySQL configuration values for the RAM in bytes and the number of vCPUs.
+func mySQLPresetValues(preset ConfigPreset, ram float64, vcpus int) (map[string]any, bool) {
+	switch preset {
+	case ConfigPresetOLTP:
+		maxConnections := 100 + 50*vcpus
+		bufferPoolSize := ram * 7 / 10
+		return map[string]any{
+			"max_connections":                maxConnections,
+			"innodb_buffer_pool_size":        presetMemory(bufferPoolSize),
+			"innodb_log_file_size":           presetMemory(math.Min(bufferPoolSize/4, presetInnodbLogFileSizeLimit)),
+			"innodb_flush_log_at_trx_commit": 1,
+			"thread_cache_size":              8 + maxConnections/100,
+			"table_open_cache":               4000,
+			"tmp_table_size":                 presetMemory(32 * configUnitMegabyte),
+			"max_heap_table_size":            presetMemory(32 * configUnitMegabyte),
+			"sort_buffer_size":               presetMemory(2 * configUnitMegabyte),
+			"join_buffer_size":               presetMemory(256 * configUnitKilobyte),
+		}, true
+	case ConfigPresetAnalytics:
+		maxConnections := 20 + 10*vcpus
+		bufferPoolSize := ram * 7 / 10
+		tmpTableSize := math.Min(ram/20, presetTmpTableSizeLimit)
+		return map[string]any{
+			"max_connections":         maxConnections,
+			"innodb_buffer_pool_size": presetMemory(bufferPoolSize),
+			"innodb_log_file_size":    presetMemory(math.Min(bufferPoolSize/4, presetInnodbLogFileSizeLimit)),
+			"tmp_table_size":          presetMemory(tmpTableSize),
+			"max_heap_table_size":     presetMemory(tmpTableSize),
+			"sort_buffer_size":        presetMemory(8 * configUnitMegabyte),
+			"join_buffer_size":        presetMemory(8 * configUnitMegabyte),
+			"long_query_time":         10.0,
+		}, true
+	case ConfigPresetSmallDev:
+		return map[string]any{
+			"max_connections":         50,
+			"innodb_buffer_pool_size": presetMemory(ram / 2),
+			"tmp_table_size":          presetMemory(16 * configUnitMegabyte),
+			"max_heap_table_size":     presetMemory(16 * configUnitMegabyte),
+		}, true
+	default:
+		return nil, false
+	}
+}
+
+// redisPresetValues returns Redis configuration values.
+func redisPresetValues(preset ConfigPreset) (map[string]any, bool) {
+	switch preset {
+	case ConfigPresetCache:
+		return map[string]any{
+			"maxmemory-policy":  "allkeys-lru",
+			"maxmemory-samples": 10,
+			"timeout":           300,
+		}, true
+	case ConfigPresetPersistent:
+		return map[string]any{
+			"maxmemory-policy": "noeviction",
+			"timeout":          0,
+		}, true
+	default:
+		return nil, false
+	}
+}
+
+// maxInt returns the larger of two integers.
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
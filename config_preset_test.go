@@ -0,0 +1,152 @@
+package dbaas
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testPresetParameters() []ConfigurationParameter {
+	return []ConfigurationParameter{
+		{
+			DatastoreTypeID: testValidatorDatastoreTypeID, Name: "shared_buffers", Type: ConfigParameterTypeInt,
+			Unit: "8kB", Min: 16.0, Max: 1073741823.0, IsChangeable: true,
+		},
+		{
+			DatastoreTypeID: testValidatorDatastoreTypeID, Name: "work_mem", Type: ConfigParameterTypeInt,
+			Unit: "kB", Min: 64.0, Max: 2147483647.0, IsChangeable: true,
+		},
+		{
+			DatastoreTypeID: testValidatorDatastoreTypeID, Name: "max_connections", Type: ConfigParameterTypeInt,
+			Min: 1.0, Max: 262143.0, IsChangeable: true,
+		},
+		{
+			DatastoreTypeID: testValidatorDatastoreTypeID, Name: "max_parallel_workers", Type: ConfigParameterTypeInt,
+			Min: 0.0, Max: 2.0, IsChangeable: true,
+		},
+		{
+			DatastoreTypeID: testValidatorDatastoreTypeID, Name: "random_page_cost", Type: ConfigParameterTypeFloat,
+			Min: 0.0, IsChangeable: true,
+		},
+		{
+			DatastoreTypeID: testValidatorDatastoreTypeID, Name: "effective_cache_size", Type: ConfigParameterTypeInt,
+			Unit: "ms", IsChangeable: true,
+		},
+		{
+			DatastoreTypeID: testValidatorDatastoreTypeID, Name: "jit", Type: ConfigParameterTypeBoolean,
+		},
+	}
+}
+
+func TestPresetConfigOLTP(t *testing.T) {
+	datastoreType := DatastoreType{ID: testValidatorDatastoreTypeID, Engine: "postgresql", Version: "16"}
+	flavor := FlavorResponse{Vcpus: 4, RAM: 16384, Disk: 64}
+
+	config, err := PresetConfig(ConfigPresetOLTP, datastoreType, flavor.Flavor(), testPresetParameters())
+
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{
+		"max_connections":      300,
+		"shared_buffers":       524288,
+		"work_mem":             13981,
+		"max_parallel_workers": 2,
+		"random_page_cost":     1.1,
+	}, config)
+}
+
+func TestPresetConfigMemoryWithoutUnit(t *testing.T) {
+	datastoreType := DatastoreType{ID: testValidatorDatastoreTypeID, Engine: "mysql", Version: "8"}
+	parameters := []ConfigurationParameter{
+		{
+			DatastoreTypeID: testValidatorDatastoreTypeID, Name: "innodb_buffer_pool_size",
+			Type: ConfigParameterTypeInt, IsChangeable: true,
+		},
+		{
+			DatastoreTypeID: testValidatorDatastoreTypeID, Name: "sort_buffer_size", Type: ConfigParameterTypeInt,
+			IsChangeable: true,
+		},
+	}
+
+	config, err := PresetConfig(ConfigPresetOLTP, datastoreType, Flavor{Vcpus: 2, RAM: 4096}, parameters)
+
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{
+		"innodb_buffer_pool_size": 3006477107,
+		"sort_buffer_size":        2097152,
+	}, config)
+}
+
+func TestPresetConfigClampsToMinimum(t *testing.T) {
+	datastoreType := DatastoreType{ID: testValidatorDatastoreTypeID, Engine: "postgresql", Version: "16"}
+
+	config, err := PresetConfig(ConfigPresetOLTP, datastoreType, Flavor{Vcpus: 64, RAM: 64}, testPresetParameters())
+
+	require.NoError(t, err)
+	assert.Equal(t, 64, config["work_mem"])
+	assert.Equal(t, 2, config["max_parallel_workers"])
+}
+
+func TestPresetConfigSmallDevSkipsUnchangeable(t *testing.T) {
+	datastoreType := DatastoreType{ID: testValidatorDatastoreTypeID, Engine: "postgresql", Version: "16"}
+	flavor := Flavor{Vcpus: 1, RAM: 1024}
+
+	config, err := PresetConfig(ConfigPresetSmallDev, datastoreType, flavor, testPresetParameters())
+
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{
+		"max_connections": 50,
+		"shared_buffers":  19660,
+		"work_mem":        4096,
+	}, config)
+}
+
+func TestPresetConfigUnknownPreset(t *testing.T) {
+	tests := []struct {
+		preset ConfigPreset
+		engine string
+	}{
+		{preset: ConfigPresetCache, engine: "postgresql"},
+		{preset: ConfigPresetOLTP, engine: "redis"},
+		{preset: ConfigPresetOLTP, engine: "kafka"},
+	}
+
+	for _, test := range tests {
+		_, err := PresetConfig(test.preset, DatastoreType{Engine: test.engine}, Flavor{Vcpus: 2, RAM: 4096}, nil)
+		assert.ErrorIs(t, err, ErrUnknownPreset)
+	}
+}
+
+func TestConfigPresets(t *testing.T) {
+	assert.Equal(t, []ConfigPreset{ConfigPresetOLTP, ConfigPresetAnalytics, ConfigPresetSmallDev},
+		ConfigPresets("mysql"))
+	assert.Equal(t, []ConfigPreset{ConfigPresetCache, ConfigPresetPersistent}, ConfigPresets("redis"))
+	assert.Empty(t, ConfigPresets("kafka"))
+}
+
+func TestAPIPresetConfig(t *testing.T) {
+	httpmock.Activate()
+	testClient := SetupTestClient()
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder("GET", testClient.Endpoint+DatastoreTypesURI+"/"+datastoreTypeID,
+		httpmock.NewStringResponder(200, testDatastoreTypeResponse))
+	httpmock.RegisterResponder("GET", testClient.Endpoint+ConfigurationParametersURI,
+		httpmock.NewStringResponder(200, `{"configuration-parameters": [{
+			"datastore_type_id": "`+datastoreTypeID+`",
+			"name": "innodb_buffer_pool_size",
+			"type": "int",
+			"unit": "B",
+			"min": 5242880,
+			"max": 9223372036854775807,
+			"is_changeable": true
+		}]}`))
+
+	config, err := testClient.PresetConfig(context.Background(), ConfigPresetOLTP, datastoreTypeID,
+		Flavor{Vcpus: 2, RAM: 4096})
+
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"innodb_buffer_pool_size": 3006477107}, config)
+}
@@ -0,0 +1,265 @@
+package dbaas
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ErrInvalidConfig is returned if the configuration doesn't match the configuration parameters.
+var ErrInvalidConfig = errors.New("invalid config")
+
+// Configuration parameter types.
+const (
+	ConfigParameterTypeInt     = "int"
+	ConfigParameterTypeFloat   = "float"
+	ConfigParameterTypeString  = "str"
+	ConfigParameterTypeBoolean = "boolean"
+)
+
+// ConfigViolationReason is a reason why a configuration parameter value is rejected.
+type ConfigViolationReason string
+
+const (
+	ConfigUnknownParameter ConfigViolationReason = "unknown parameter"
+	ConfigWrongType        ConfigViolationReason = "wrong type"
+	ConfigOutOfRange       ConfigViolationReason = "out of range"
+	ConfigNotInChoices     ConfigViolationReason = "not in choices"
+	ConfigInvalidValue     ConfigViolationReason = "invalid value"
+	ConfigNotChangeable    ConfigViolationReason = "not changeable"
+)
+
+// ConfigViolation describes a rejected configuration parameter value.
+type ConfigViolation struct {
+	Value     any
+	Parameter string
+	Reason    ConfigViolationReason
+	Message   string
+}
+
+// String returns a human-readable description of the violation.
+func (v ConfigViolation) String() string {
+	return fmt.Sprintf("%s: %s", v.Parameter, v.Message)
+}
+
+// ConfigValidationError is returned if the configuration has violations.
+type ConfigValidationError struct {
+	Violations []ConfigViolation
+}
+
+// Error returns all violations in a single line.
+func (e *ConfigValidationError) Error() string {
+	messages := make([]string, 0, len(e.Violations))
+	for _, violation := range e.Violations {
+		messages = append(messages, violation.String())
+	}
+	return fmt.Sprintf("%s: %s", ErrInvalidConfig, strings.Join(messages, "; "))
+}
+
+// Unwrap allows to match the error with ErrInvalidConfig.
+func (e *ConfigValidationError) Unwrap() error {
+	return ErrInvalidConfig
+}
+
+// ConfigValidationResult is a result of the configuration validation.
+type ConfigValidationResult struct {
+	// Violations are sorted by parameter names.
+	Violations []ConfigViolation
+
+	// RestartRequired lists sorted names of the parameters that cause a datastore restart.
+	RestartRequired []string
+}
+
+// Valid reports whether the configuration has no violations.
+func (r ConfigValidationResult) Valid() bool {
+	return len(r.Violations) == 0
+}
+
+// Err returns *ConfigValidationError if the configuration has violations.
+func (r ConfigValidationResult) Err() error {
+	if r.Valid() {
+		return nil
+	}
+	return &ConfigValidationError{Violations: r.Violations}
+}
+
+// ConfigValidator checks configurations against the configuration parameters of a datastore type.
+type ConfigValidator struct {
+	parameters map[string]ConfigurationParameter
+}
+
+// NewConfigValidator creates a validator from the configuration parameters of the datastore type.
+// Parameters of other datastore types are ignored.
+func NewConfigValidator(datastoreTypeID string, parameters []ConfigurationParameter) *ConfigValidator {
+	validator := &ConfigValidator{parameters: make(map[string]ConfigurationParameter)}
+	for _, parameter := range parameters {
+		if parameter.DatastoreTypeID == datastoreTypeID {
+			validator.parameters[parameter.Name] = parameter
+		}
+	}
+	return validator
+}
+
+// ConfigValidator loads the configuration parameters of the datastore type and creates a validator.
+func (api *API) ConfigValidator(ctx context.Context, datastoreTypeID string) (*ConfigValidator, error) {
+	parameters, err := api.ConfigurationParameters(ctx, &ConfigurationParameterQueryParams{
+		DatastoreTypeID: datastoreTypeID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return NewConfigValidator(datastoreTypeID, parameters), nil
+}
+
+// ValidateConfig checks the configuration against the configuration parameters of the datastore type.
+func (api *API) ValidateConfig(
+	ctx context.Context,
+	datastoreTypeID string,
+	config map[string]any,
+) (ConfigValidationResult, error) {
+	validator, err := api.ConfigValidator(ctx, datastoreTypeID)
+	if err != nil {
+		return ConfigValidationResult{}, err
+	}
+
+	return validator.Validate(config), nil
+}
+
+// Parameter returns the configuration parameter with the given name.
+func (v *ConfigValidator) Parameter(name string) (ConfigurationParameter, bool) {
+	parameter, ok := v.parameters[name]
+	return parameter, ok
+}
+
+// Validate checks the configuration. Null values reset parameters to their defaults,
+// so only the possibility to change them is checked.
+// Numeric strings are accepted for numeric parameters.
+func (v *ConfigValidator) Validate(config map[string]any) ConfigValidationResult {
+	var result ConfigValidationResult
+	for name, value := range config {
+		parameter, ok := v.parameters[name]
+		if !ok {
+			result.Violations = append(result.Violations, ConfigViolation{
+				Value:     value,
+				Parameter: name,
+				Reason:    ConfigUnknownParameter,
+				Message:   "unknown configuration parameter",
+			})
+			continue
+		}
+		if violation, ok := validateConfigValue(parameter, value); !ok {
+			result.Violations = append(result.Violations, violation)
+			continue
+		}
+		if parameter.IsRestartRequired {
+			result.RestartRequired = append(result.RestartRequired, name)
+		}
+	}
+
+	sort.Slice(result.Violations, func(i, j int) bool {
+		return result.Violations[i].Parameter < result.Violations[j].Parameter
+	})
+	sort.Strings(result.RestartRequired)
+
+	return result
+}
+
+// validateConfigValue checks the value of the configuration parameter.
+func validateConfigValue(parameter ConfigurationParameter, value any) (ConfigViolation, bool) {
+	violation := ConfigViolation{Value: value, Parameter: parameter.Name}
+	switch {
+	case !parameter.IsChangeable:
+		violation.Reason = ConfigNotChangeable
+		violation.Message = "parameter can't be changed"
+		return violation, false
+	case value == nil:
+		return violation, true
+	case !hasConfigType(parameter.Type, value):
+		violation.Reason = ConfigWrongType
+		violation.Message = fmt.Sprintf("value %v is not of type %s", value, parameter.Type)
+		return violation, false
+	case containsConfigValue(parameter.InvalidValues, value):
+		violation.Reason = ConfigInvalidValue
+		violation.Message = fmt.Sprintf("value %v is not allowed", value)
+		return violation, false
+	case len(parameter.Choices) > 0 && !containsConfigValue(parameter.Choices, value):
+		violation.Reason = ConfigNotInChoices
+		violation.Message = fmt.Sprintf("value %v is not one of %v", value, parameter.Choices)
+		return violation, false
+	}
+
+	number, isNumber := configNumber(value)
+	if minimum, ok := configNumber(parameter.Min); ok && isNumber && number < minimum {
+		violation.Reason = ConfigOutOfRange
+		violation.Message = fmt.Sprintf("value %v is less than the minimum of %v", value, parameter.Min)
+		return violation, false
+	}
+	if maximum, ok := configNumber(parameter.Max); ok && isNumber && number > maximum {
+		violation.Reason = ConfigOutOfRange
+		violation.Message = fmt.Sprintf("value %v is greater than the maximum of %v", value, parameter.Max)
+		return violation, false
+	}
+
+	return violation, true
+}
+
+// hasConfigType checks if the value matches the configuration parameter type.
+// Unknown types match any value.
+func hasConfigType(parameterType string, value any) bool {
+	switch parameterType {
+	case ConfigParameterTypeInt:
+		number, ok := configNumber(value)
+		return ok && number == math.Trunc(number)
+	case ConfigParameterTypeFloat:
+		_, ok := configNumber(value)
+		return ok
+	case ConfigParameterTypeString:
+		_, ok := value.(string)
+		return ok
+	case ConfigParameterTypeBoolean:
+		_, ok := value.(bool)
+		return ok
+	default:
+		return true
+	}
+}
+
+// configNumber converts numbers and numeric strings to float64.
+func configNumber(value any) (float64, bool) {
+	switch v := value.(type) {
+	case string:
+		number, err := strconv.ParseFloat(v, 64)
+		return number, err == nil
+	case nil, bool:
+		return 0, false
+	}
+
+	reflected := reflect.ValueOf(value)
+	switch reflected.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(reflected.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(reflected.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return reflected.Float(), true
+	default:
+		return 0, false
+	}
+}
+
+// containsConfigValue checks if the values contain the value.
+// Values are compared by their string representation, since choices may be strings for numeric parameters.
+func containsConfigValue(values []any, value any) bool {
+	for _, v := range values {
+		if fmt.Sprint(v) == fmt.Sprint(value) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,127 @@
+package dbaas
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testValidatorDatastoreTypeID = "20d7bcf4-f8d6-4bf6-b8f6-46cb440a87f4"
+
+func testValidatorParameters() []ConfigurationParameter {
+	return []ConfigurationParameter{
+		{
+			DatastoreTypeID: testValidatorDatastoreTypeID, Name: "work_mem", Type: ConfigParameterTypeInt,
+			Min: 64.0, Max: 2147483647.0, IsChangeable: true,
+		},
+		{
+			DatastoreTypeID: testValidatorDatastoreTypeID, Name: "shared_buffers", Type: ConfigParameterTypeInt,
+			Min: 16.0, Max: 1073741823.0, IsChangeable: true, IsRestartRequired: true,
+		},
+		{
+			DatastoreTypeID: testValidatorDatastoreTypeID, Name: "random_page_cost", Type: ConfigParameterTypeFloat,
+			Min: 0.0, IsChangeable: true,
+		},
+		{
+			DatastoreTypeID: testValidatorDatastoreTypeID, Name: "jit", Type: ConfigParameterTypeBoolean,
+			IsChangeable: true,
+		},
+		{
+			DatastoreTypeID: testValidatorDatastoreTypeID, Name: "concurrent_insert", Type: ConfigParameterTypeString,
+			Choices: []any{"NEVER", "AUTO", "ALWAYS", "0", "1", "2"}, InvalidValues: []any{"0"}, IsChangeable: true,
+		},
+		{
+			DatastoreTypeID: testValidatorDatastoreTypeID, Name: "data_directory", Type: ConfigParameterTypeString,
+		},
+		{
+			DatastoreTypeID: "other", Name: "maxmemory-policy", Type: ConfigParameterTypeString,
+			IsChangeable: true,
+		},
+	}
+}
+
+func TestConfigValidatorValid(t *testing.T) {
+	validator := NewConfigValidator(testValidatorDatastoreTypeID, testValidatorParameters())
+
+	result := validator.Validate(map[string]any{
+		"work_mem":          8192,
+		"shared_buffers":    "32768",
+		"random_page_cost":  1.1,
+		"jit":               false,
+		"concurrent_insert": "ALWAYS",
+	})
+
+	assert.True(t, result.Valid())
+	assert.NoError(t, result.Err())
+	assert.Equal(t, []string{"shared_buffers"}, result.RestartRequired)
+}
+
+func TestConfigValidatorViolations(t *testing.T) {
+	validator := NewConfigValidator(testValidatorDatastoreTypeID, testValidatorParameters())
+
+	result := validator.Validate(map[string]any{
+		"work_mem":          32,
+		"shared_buffers":    1.5,
+		"random_page_cost":  "cheap",
+		"jit":               "yes",
+		"concurrent_insert": "SOMETIMES",
+		"data_directory":    "/tmp",
+		"maxmemory-policy":  "allkeys-lru",
+	})
+
+	require.False(t, result.Valid())
+	reasons := make(map[string]ConfigViolationReason)
+	for _, violation := range result.Violations {
+		reasons[violation.Parameter] = violation.Reason
+	}
+	assert.Equal(t, map[string]ConfigViolationReason{
+		"work_mem":          ConfigOutOfRange,
+		"shared_buffers":    ConfigWrongType,
+		"random_page_cost":  ConfigWrongType,
+		"jit":               ConfigWrongType,
+		"concurrent_insert": ConfigNotInChoices,
+		"data_directory":    ConfigNotChangeable,
+		"maxmemory-policy":  ConfigUnknownParameter,
+	}, reasons)
+	assert.Equal(t, "concurrent_insert", result.Violations[0].Parameter)
+	assert.Empty(t, result.RestartRequired)
+
+	err := result.Err()
+	assert.ErrorIs(t, err, ErrInvalidConfig)
+	assert.Contains(t, err.Error(), "work_mem: value 32 is less than the minimum of 64")
+}
+
+func TestConfigValidatorInvalidValueAndReset(t *testing.T) {
+	validator := NewConfigValidator(testValidatorDatastoreTypeID, testValidatorParameters())
+
+	result := validator.Validate(map[string]any{
+		"concurrent_insert": "0",
+		"shared_buffers":    nil,
+		"data_directory":    nil,
+	})
+
+	require.Len(t, result.Violations, 2)
+	assert.Equal(t, ConfigInvalidValue, result.Violations[0].Reason)
+	assert.Equal(t, ConfigNotChangeable, result.Violations[1].Reason)
+	assert.Equal(t, []string{"shared_buffers"}, result.RestartRequired)
+}
+
+func TestValidateConfig(t *testing.T) {
+	httpmock.Activate()
+	testClient := SetupTestClient()
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder("GET", testClient.Endpoint+ConfigurationParametersURI,
+		httpmock.NewStringResponder(200, testConfigurationParametersResponse))
+
+	result, err := testClient.ValidateConfig(context.Background(), testValidatorDatastoreTypeID,
+		map[string]any{"temp_file_limit": -2, "concurrent_insert": "AUTO"})
+
+	require.NoError(t, err)
+	require.Len(t, result.Violations, 1)
+	assert.Equal(t, "temp_file_limit", result.Violations[0].Parameter)
+	assert.Equal(t, ConfigOutOfRange, result.Violations[0].Reason)
+}
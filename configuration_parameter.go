@@ -23,11 +23,25 @@ type ConfigurationParameter struct {
 	IsChangeable      bool   `json:"is_changeable"`
 }
 
+// ConfigurationParameterQueryParams represents available query parameters for configuration parameter.
+type ConfigurationParameterQueryParams struct {
+	DatastoreTypeID string `json:"datastore_type_id,omitempty"`
+	Name            string `json:"name,omitempty"`
+}
+
 const ConfigurationParametersURI = "/configuration-parameters"
 
-// ConfigurationParameters returns all configuration parameters.
-func (api *API) ConfigurationParameters(ctx context.Context) ([]ConfigurationParameter, error) {
-	resp, err := api.makeRequest(ctx, http.MethodGet, ConfigurationParametersURI, nil)
+// ConfigurationParameters returns all configuration parameters or the parameters that match the optional params.
+func (api *API) ConfigurationParameters(
+	ctx context.Context,
+	params ...*ConfigurationParameterQueryParams,
+) ([]ConfigurationParameter, error) {
+	uri, err := setQueryParams(ConfigurationParametersURI, optionalParams(params))
+	if err != nil {
+		return []ConfigurationParameter{}, err
+	}
+
+	resp, err := api.makeRequest(ctx, http.MethodGet, uri, nil)
 	if err != nil {
 		return []ConfigurationParameter{}, err
 	}
@@ -123,6 +123,24 @@ func TestConfigurationParameters(t *testing.T) {
 	assert.Equal(t, expected, actual)
 }
 
+func TestConfigurationParametersWithParams(t *testing.T) {
+	httpmock.Activate()
+	testClient := SetupTestClient()
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponderWithQuery("GET", testClient.Endpoint+ConfigurationParametersURI,
+		"datastore_type_id=20d7bcf4-f8d6-4bf6-b8f6-46cb440a87f4&name=work_mem",
+		httpmock.NewStringResponder(200, testConfigurationParametersResponse))
+
+	actual, err := testClient.ConfigurationParameters(context.Background(), &ConfigurationParameterQueryParams{
+		DatastoreTypeID: datastoreTypeID,
+		Name:            "work_mem",
+	})
+
+	require.NoError(t, err)
+	assert.Len(t, actual, 2)
+}
+
 func TestConfigurationParameter(t *testing.T) {
 	httpmock.Activate()
 	testClient := SetupTestClient()
@@ -0,0 +1,401 @@
+package dbaas
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ErrNoAddress is returned if the datastore doesn't have the requested address.
+var ErrNoAddress = errors.New("address is not available")
+
+// ErrUnsupportedSSLMode is returned if the connection string can't express the requested SSL mode.
+var ErrUnsupportedSSLMode = errors.New("SSL mode is not supported")
+
+// Default ports of the datastores.
+const (
+	PostgreSQLPort       = 5432
+	PostgreSQLPoolerPort = 6432
+	MySQLPort            = 6033
+	RedisPort            = 6380
+	KafkaPort            = 9093
+)
+
+// Roles of the datastore instances.
+const (
+	InstanceRoleMaster  = "MASTER"
+	InstanceRoleReplica = "REPLICA"
+)
+
+// noFloatingIP is the floating IP value of instances without a floating IP.
+const noFloatingIP = "None"
+
+// AddressKind specifies which address is used to connect to the datastore.
+type AddressKind string
+
+const (
+	// AddressHostname uses DNS names from Datastore.Connection.
+	AddressHostname AddressKind = "hostname"
+
+	// AddressPrivateIP uses IP addresses of the instances in the private network.
+	AddressPrivateIP AddressKind = "private"
+
+	// AddressFloatingIP uses public floating IP addresses of the instances.
+	AddressFloatingIP AddressKind = "floating"
+)
+
+// SSLMode specifies how the TLS connection is verified.
+type SSLMode string
+
+const (
+	SSLModeDisable    SSLMode = "disable"
+	SSLModeRequire    SSLMode = "require"
+	SSLModeVerifyCA   SSLMode = "verify-ca"
+	SSLModeVerifyFull SSLMode = "verify-full"
+)
+
+// Endpoint represents a host and a port of the datastore.
+type Endpoint struct {
+	Host string `json:"host"`
+	Port int    `json:"port"`
+}
+
+// String returns the endpoint in the host:port form.
+func (e Endpoint) String() string {
+	return net.JoinHostPort(e.Host, strconv.Itoa(e.Port))
+}
+
+// InstanceAddress represents addresses of the datastore instance.
+// FloatingIP is empty if the instance doesn't have a floating IP.
+type InstanceAddress struct {
+	ID         string `json:"id"`
+	Role       string `json:"role"`
+	Hostname   string `json:"hostname"`
+	PrivateIP  string `json:"private_ip"`
+	FloatingIP string `json:"floating_ip"`
+}
+
+// ConnectionInfo is a typed view over Datastore.Connection and Datastore.Instances.
+type ConnectionInfo struct {
+	// Pooler is set for PostgreSQL datastores with a connection pooler.
+	Pooler *Endpoint `json:"pooler,omitempty"`
+
+	Engine    string            `json:"engine"`
+	Master    Endpoint          `json:"master"`
+	Replicas  []Endpoint        `json:"replicas"`
+	Instances []InstanceAddress `json:"instances"`
+}
+
+// NewConnectionInfo builds the connection info of the datastore with the engine of its datastore type.
+func NewConnectionInfo(datastore Datastore, engine string) ConnectionInfo {
+	port := enginePort(engine)
+	info := ConnectionInfo{Engine: engine, Replicas: []Endpoint{}, Instances: []InstanceAddress{}}
+
+	names := make([]string, 0, len(datastore.Connection))
+	for name := range datastore.Connection {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		host := datastore.Connection[name]
+		switch {
+		case strings.EqualFold(name, InstanceRoleMaster):
+			info.Master = Endpoint{Host: host, Port: port}
+		case strings.HasPrefix(strings.ToUpper(name), InstanceRoleReplica) && !containsEndpoint(info.Replicas, host):
+			info.Replicas = append(info.Replicas, Endpoint{Host: host, Port: port})
+		}
+	}
+
+	for _, instance := range datastore.Instances {
+		address := InstanceAddress{
+			ID:         instance.ID,
+			Role:       strings.ToUpper(instance.Role),
+			Hostname:   instance.Hostname,
+			PrivateIP:  instance.IP,
+			FloatingIP: instance.FloatingIP,
+		}
+		if address.FloatingIP == noFloatingIP {
+			address.FloatingIP = ""
+		}
+		info.Instances = append(info.Instances, address)
+	}
+
+	if engine == EnginePostgreSQL && datastore.Pooler.Mode != "" && info.Master.Host != "" {
+		info.Pooler = &Endpoint{Host: info.Master.Host, Port: PostgreSQLPoolerPort}
+	}
+
+	return info
+}
+
+// ConnectionInfo loads the datastore with its datastore type and builds the connection info.
+func (api *API) ConnectionInfo(ctx context.Context, datastoreID string) (ConnectionInfo, error) {
+	datastore, err := api.Datastore(ctx, datastoreID)
+	if err != nil {
+		return ConnectionInfo{}, err
+	}
+	datastoreType, err := api.DatastoreType(ctx, datastore.TypeID)
+	if err != nil {
+		return ConnectionInfo{}, err
+	}
+
+	return NewConnectionInfo(datastore, datastoreType.Engine), nil
+}
+
+// ConnectionOpts represents credentials and TLS settings used to build connection strings.
+type ConnectionOpts struct {
+	// User, Password and Database are the credentials. Name of the User is used as the user name.
+	User     User
+	Database Database
+	Password string
+
+	// RootCertPath is a path to the CA certificate that is used to verify the server certificate.
+	RootCertPath string
+
+	// TLSConfigName is a name of the TLS config registered in the MySQL driver. It overrides SSLMode for MySQL.
+	TLSConfigName string
+
+	// SSLMode defaults to verify-full.
+	SSLMode SSLMode
+
+	// Address defaults to hostnames.
+	Address AddressKind
+
+	// Replica connects to the replicas instead of the master.
+	Replica bool
+
+	// UsePooler connects to the PostgreSQL connection pooler.
+	UsePooler bool
+}
+
+// sslMode returns the SSL mode with the default applied.
+func (opts ConnectionOpts) sslMode() SSLMode {
+	if opts.SSLMode == "" {
+		return SSLModeVerifyFull
+	}
+	return opts.SSLMode
+}
+
+// Endpoints returns the endpoints of the master or the replicas using the requested address kind.
+func (c ConnectionInfo) Endpoints(role string, address AddressKind) ([]Endpoint, error) {
+	role = strings.ToUpper(role)
+	if address == "" || address == AddressHostname {
+		if role == InstanceRoleMaster && c.Master.Host != "" {
+			return []Endpoint{c.Master}, nil
+		}
+		if role == InstanceRoleReplica && len(c.Replicas) > 0 {
+			return c.Replicas, nil
+		}
+		return nil, fmt.Errorf("%w: no %s hostname", ErrNoAddress, strings.ToLower(role))
+	}
+
+	port := enginePort(c.Engine)
+	var endpoints []Endpoint
+	for _, instance := range c.Instances {
+		if instance.Role != role {
+			continue
+		}
+		ip := instance.PrivateIP
+		if address == AddressFloatingIP {
+			ip = instance.FloatingIP
+		}
+		if ip == "" {
+			return nil, fmt.Errorf("%w: instance %s has no %s IP", ErrNoAddress, instance.ID, address)
+		}
+		endpoints = append(endpoints, Endpoint{Host: ip, Port: port})
+	}
+	if len(endpoints) == 0 {
+		return nil, fmt.Errorf("%w: no %s instances", ErrNoAddress, strings.ToLower(role))
+	}
+
+	return endpoints, nil
+}
+
+// endpoint returns the first endpoint requested by the options.
+func (c ConnectionInfo) endpoint(opts ConnectionOpts) (Endpoint, error) {
+	if opts.UsePooler {
+		if c.Pooler == nil {
+			return Endpoint{}, fmt.Errorf("%w: datastore has no pooler", ErrNoAddress)
+		}
+		if opts.Address != "" && opts.Address != AddressHostname {
+			return Endpoint{}, fmt.Errorf("%w: pooler is available by hostname only", ErrNoAddress)
+		}
+		return *c.Pooler, nil
+	}
+
+	role := InstanceRoleMaster
+	if opts.Replica {
+		role = InstanceRoleReplica
+	}
+	endpoints, err := c.Endpoints(role, opts.Address)
+	if err != nil {
+		return Endpoint{}, err
+	}
+
+	return endpoints[0], nil
+}
+
+// PostgreSQLDSN returns a connection URL accepted by libpq and pgx.
+func (c ConnectionInfo) PostgreSQLDSN(opts ConnectionOpts) (string, error) {
+	endpoint, err := c.endpoint(opts)
+	if err != nil {
+		return "", err
+	}
+
+	query := url.Values{}
+	query.Set("sslmode", string(opts.sslMode()))
+	if opts.RootCertPath != "" && opts.sslMode() != SSLModeDisable {
+		query.Set("sslrootcert", opts.RootCertPath)
+	}
+	dsn := url.URL{
+		Scheme:   "postgres",
+		User:     url.UserPassword(opts.User.Name, opts.Password),
+		Host:     endpoint.String(),
+		Path:     "/" + opts.Database.Name,
+		RawQuery: query.Encode(),
+	}
+
+	return dsn.String(), nil
+}
+
+// MySQLDSN returns a data source name in the format of the go-sql-driver/mysql driver.
+// The driver can't verify certificates against a custom CA or without the hostname by itself,
+// so verify-ca and RootCertPath require a TLS config built with NewTLSConfig, registered in the driver
+// and passed as TLSConfigName.
+func (c ConnectionInfo) MySQLDSN(opts ConnectionOpts) (string, error) {
+	endpoint, err := c.endpoint(opts)
+	if err != nil {
+		return "", err
+	}
+
+	tls := opts.TLSConfigName
+	if tls == "" {
+		switch opts.sslMode() {
+		case SSLModeDisable:
+			tls = "false"
+		case SSLModeRequire:
+			tls = "skip-verify"
+		case SSLModeVerifyCA:
+			return "", fmt.Errorf("%w: %s requires TLSConfigName for MySQL", ErrUnsupportedSSLMode, SSLModeVerifyCA)
+		default:
+			if opts.RootCertPath != "" {
+				return "", fmt.Errorf("%w: RootCertPath requires TLSConfigName for MySQL", ErrUnsupportedSSLMode)
+			}
+			tls = "true"
+		}
+	}
+
+	return fmt.Sprintf("%s:%s@tcp(%s)/%s?tls=%s",
+		opts.User.Name, opts.Password, endpoint, opts.Database.Name, url.QueryEscape(tls)), nil
+}
+
+// RedisURL returns a Redis connection URL. The rediss scheme is used unless TLS is disabled.
+// Database name is used as the database number, 0 by default.
+func (c ConnectionInfo) RedisURL(opts ConnectionOpts) (string, error) {
+	endpoint, err := c.endpoint(opts)
+	if err != nil {
+		return "", err
+	}
+
+	scheme := "rediss"
+	if opts.sslMode() == SSLModeDisable {
+		scheme = "redis"
+	}
+	database := opts.Database.Name
+	if database == "" {
+		database = "0"
+	}
+	redisURL := url.URL{
+		Scheme: scheme,
+		User:   url.UserPassword(opts.User.Name, opts.Password),
+		Host:   endpoint.String(),
+		Path:   "/" + database,
+	}
+
+	return redisURL.String(), nil
+}
+
+// KafkaClientConfig represents settings of a Kafka client.
+type KafkaClientConfig struct {
+	SecurityProtocol string
+	SASLMechanism    string
+	Username         string
+	Password         string
+	RootCertPath     string
+	BootstrapServers []string
+}
+
+// Properties returns the settings as librdkafka configuration properties.
+func (c KafkaClientConfig) Properties() map[string]string {
+	properties := map[string]string{
+		"bootstrap.servers": strings.Join(c.BootstrapServers, ","),
+		"security.protocol": c.SecurityProtocol,
+		"sasl.mechanisms":   c.SASLMechanism,
+		"sasl.username":     c.Username,
+		"sasl.password":     c.Password,
+	}
+	if c.RootCertPath != "" {
+		properties["ssl.ca.location"] = c.RootCertPath
+	}
+	return properties
+}
+
+// KafkaConfig returns settings of a Kafka client that authenticates with SCRAM-SHA-512.
+// All brokers are used as bootstrap servers.
+func (c ConnectionInfo) KafkaConfig(opts ConnectionOpts) (KafkaClientConfig, error) {
+	masters, err := c.Endpoints(InstanceRoleMaster, opts.Address)
+	if err != nil {
+		return KafkaClientConfig{}, err
+	}
+	replicas, err := c.Endpoints(InstanceRoleReplica, opts.Address)
+	if err != nil && !errors.Is(err, ErrNoAddress) {
+		return KafkaClientConfig{}, err
+	}
+
+	config := KafkaClientConfig{
+		SecurityProtocol: "SASL_SSL",
+		SASLMechanism:    "SCRAM-SHA-512",
+		Username:         opts.User.Name,
+		Password:         opts.Password,
+	}
+	if opts.sslMode() == SSLModeDisable {
+		config.SecurityProtocol = "SASL_PLAINTEXT"
+	} else {
+		config.RootCertPath = opts.RootCertPath
+	}
+	for _, endpoint := range append(masters, replicas...) {
+		config.BootstrapServers = append(config.BootstrapServers, endpoint.String())
+	}
+
+	return config, nil
+}
+
+// enginePort returns the default port of the engine.
+func enginePort(engine string) int {
+	switch engine {
+	case EnginePostgreSQL:
+		return PostgreSQLPort
+	case EngineMySQL:
+		return MySQLPort
+	case EngineRedis:
+		return RedisPort
+	case EngineKafka:
+		return KafkaPort
+	default:
+		return 0
+	}
+}
+
+// containsEndpoint checks if the endpoints contain the host.
+func containsEndpoint(endpoints []Endpoint, host string) bool {
+	for _, endpoint := range endpoints {
+		if endpoint.Host == host {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,200 @@
+package dbaas
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testMasterHost  = "master.20d7bcf4-f8d6-4bf6-b8f6-46cb440a87f4.c.dbaas.selcloud.org"
+	testReplicaHost = "replica-1.20d7bcf4-f8d6-4bf6-b8f6-46cb440a87f4.c.dbaas.selcloud.org"
+)
+
+func testConnectionDatastore() Datastore {
+	return Datastore{
+		ID: datastoreID,
+		Connection: map[string]string{
+			"MASTER":    testMasterHost,
+			"master":    testMasterHost,
+			"replica-1": testReplicaHost,
+		},
+		Instances: []Instances{
+			{ID: "master-id", IP: "10.0.0.10", FloatingIP: "203.0.113.10", Role: "MASTER", Hostname: "master-host"},
+			{ID: "replica-id", IP: "10.0.0.11", FloatingIP: "None", Role: "REPLICA", Hostname: "replica-host"},
+		},
+		Pooler: Pooler{Mode: "transaction", Size: 30},
+	}
+}
+
+func TestNewConnectionInfo(t *testing.T) {
+	info := NewConnectionInfo(testConnectionDatastore(), "postgresql")
+
+	assert.Equal(t, ConnectionInfo{
+		Pooler:   &Endpoint{Host: testMasterHost, Port: PostgreSQLPoolerPort},
+		Engine:   "postgresql",
+		Master:   Endpoint{Host: testMasterHost, Port: PostgreSQLPort},
+		Replicas: []Endpoint{{Host: testReplicaHost, Port: PostgreSQLPort}},
+		Instances: []InstanceAddress{
+			{
+				ID: "master-id", Role: "MASTER", Hostname: "master-host",
+				PrivateIP: "10.0.0.10", FloatingIP: "203.0.113.10",
+			},
+			{ID: "replica-id", Role: "REPLICA", Hostname: "replica-host", PrivateIP: "10.0.0.11"},
+		},
+	}, info)
+
+	info = NewConnectionInfo(testConnectionDatastore(), "mysql")
+	assert.Nil(t, info.Pooler)
+	assert.Equal(t, "master.20d7bcf4-f8d6-4bf6-b8f6-46cb440a87f4.c.dbaas.selcloud.org:6033", info.Master.String())
+}
+
+func TestConnectionInfoEndpoints(t *testing.T) {
+	info := NewConnectionInfo(testConnectionDatastore(), "postgresql")
+
+	endpoints, err := info.Endpoints(InstanceRoleMaster, AddressFloatingIP)
+	require.NoError(t, err)
+	assert.Equal(t, []Endpoint{{Host: "203.0.113.10", Port: PostgreSQLPort}}, endpoints)
+
+	endpoints, err = info.Endpoints(InstanceRoleReplica, AddressPrivateIP)
+	require.NoError(t, err)
+	assert.Equal(t, []Endpoint{{Host: "10.0.0.11", Port: PostgreSQLPort}}, endpoints)
+
+	_, err = info.Endpoints(InstanceRoleReplica, AddressFloatingIP)
+	assert.ErrorIs(t, err, ErrNoAddress)
+}
+
+func TestPostgreSQLDSN(t *testing.T) {
+	info := NewConnectionInfo(testConnectionDatastore(), "postgresql")
+	opts := ConnectionOpts{
+		User:         User{Name: "user"},
+		Database:     Database{Name: "db"},
+		Password:     "p@ss word",
+		RootCertPath: "/etc/ssl/root.crt",
+	}
+
+	dsn, err := info.PostgreSQLDSN(opts)
+	require.NoError(t, err)
+	assert.Equal(t, "postgres://user:p%40ss%20word@"+testMasterHost+
+		":5432/db?sslmode=verify-full&sslrootcert=%2Fetc%2Fssl%2Froot.crt", dsn)
+
+	opts.UsePooler = true
+	opts.SSLMode = SSLModeRequire
+	opts.RootCertPath = ""
+	dsn, err = info.PostgreSQLDSN(opts)
+	require.NoError(t, err)
+	assert.Equal(t, "postgres://user:p%40ss%20word@"+testMasterHost+":6432/db?sslmode=require", dsn)
+
+	opts.UsePooler = false
+	opts.Replica = true
+	opts.Address = AddressFloatingIP
+	_, err = info.PostgreSQLDSN(opts)
+	assert.ErrorIs(t, err, ErrNoAddress)
+}
+
+func TestMySQLDSN(t *testing.T) {
+	info := NewConnectionInfo(testConnectionDatastore(), "mysql")
+	opts := ConnectionOpts{
+		User:     User{Name: "user"},
+		Database: Database{Name: "db"},
+		Password: "secret",
+		Address:  AddressFloatingIP,
+	}
+
+	dsn, err := info.MySQLDSN(opts)
+	require.NoError(t, err)
+	assert.Equal(t, "user:secret@tcp(203.0.113.10:6033)/db?tls=true", dsn)
+
+	opts.SSLMode = SSLModeRequire
+	dsn, err = info.MySQLDSN(opts)
+	require.NoError(t, err)
+	assert.Equal(t, "user:secret@tcp(203.0.113.10:6033)/db?tls=skip-verify", dsn)
+
+	opts.SSLMode = SSLModeVerifyCA
+	dsn, err = info.MySQLDSN(opts)
+	assert.ErrorIs(t, err, ErrUnsupportedSSLMode)
+	assert.NotContains(t, dsn, "skip-verify")
+
+	opts.SSLMode = SSLModeVerifyFull
+	opts.RootCertPath = "/etc/ssl/selectel.crt"
+	_, err = info.MySQLDSN(opts)
+	assert.ErrorIs(t, err, ErrUnsupportedSSLMode)
+
+	opts.SSLMode = SSLModeVerifyCA
+	opts.TLSConfigName = "selectel"
+	dsn, err = info.MySQLDSN(opts)
+	require.NoError(t, err)
+	assert.Equal(t, "user:secret@tcp(203.0.113.10:6033)/db?tls=selectel", dsn)
+
+	opts.UsePooler = true
+	_, err = info.MySQLDSN(opts)
+	assert.ErrorIs(t, err, ErrNoAddress)
+}
+
+func TestRedisURL(t *testing.T) {
+	info := NewConnectionInfo(testConnectionDatastore(), "redis")
+
+	redisURL, err := info.RedisURL(ConnectionOpts{Password: "secret"})
+	require.NoError(t, err)
+	assert.Equal(t, "rediss://:secret@"+testMasterHost+":6380/0", redisURL)
+
+	redisURL, err = info.RedisURL(ConnectionOpts{
+		Password: "secret",
+		Database: Database{Name: "2"},
+		SSLMode:  SSLModeDisable,
+		Address:  AddressPrivateIP,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "redis://:secret@10.0.0.10:6380/2", redisURL)
+}
+
+func TestKafkaConfig(t *testing.T) {
+	info := NewConnectionInfo(testConnectionDatastore(), "kafka")
+
+	config, err := info.KafkaConfig(ConnectionOpts{
+		User:         User{Name: "user"},
+		Password:     "secret",
+		RootCertPath: "/etc/ssl/root.crt",
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"bootstrap.servers": testMasterHost + ":9093," + testReplicaHost + ":9093",
+		"security.protocol": "SASL_SSL",
+		"sasl.mechanisms":   "SCRAM-SHA-512",
+		"sasl.username":     "user",
+		"sasl.password":     "secret",
+		"ssl.ca.location":   "/etc/ssl/root.crt",
+	}, config.Properties())
+
+	datastore := testConnectionDatastore()
+	datastore.Connection = map[string]string{"master": testMasterHost}
+	config, err = NewConnectionInfo(datastore, "kafka").KafkaConfig(ConnectionOpts{SSLMode: SSLModeDisable})
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{testMasterHost + ":9093"}, config.BootstrapServers)
+	assert.Equal(t, "SASL_PLAINTEXT", config.SecurityProtocol)
+	assert.Empty(t, config.RootCertPath)
+}
+
+func TestAPIConnectionInfo(t *testing.T) {
+	httpmock.Activate()
+	testClient := SetupTestClient()
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder("GET", testClient.Endpoint+DatastoresURI+"/"+datastoreID,
+		httpmock.NewStringResponder(200, testDatastoreResponse))
+	httpmock.RegisterResponder("GET", testClient.Endpoint+DatastoreTypesURI+"/"+datastoreTypeID,
+		httpmock.NewStringResponder(200, testDatastoreTypeResponse))
+
+	info, err := testClient.ConnectionInfo(context.Background(), datastoreID)
+
+	require.NoError(t, err)
+	assert.Equal(t, "mysql", info.Engine)
+	assert.Equal(t, MySQLPort, info.Master.Port)
+	require.Len(t, info.Instances, 1)
+	assert.Equal(t, "192.168.1.1", info.Instances[0].FloatingIP)
+}
@@ -21,6 +21,11 @@ type Database struct {
 	Status      Status `json:"status"`
 }
 
+// ResourceStatus returns the database status.
+func (d Database) ResourceStatus() Status {
+	return d.Status
+}
+
 // DatabaseCreateOpts represents options for the database Create request.
 type DatabaseCreateOpts struct {
 	DatastoreID string `json:"datastore_id"`
@@ -42,6 +47,7 @@ type DatabaseQueryParams struct {
 	Name        string `json:"name,omitempty"`
 	DatastoreID string `json:"datastore_id,omitempty"`
 	Status      Status `json:"status,omitempty"`
+	Page
 }
 
 const DatabasesURI = "/databases"
@@ -91,6 +97,9 @@ func (api *API) Database(ctx context.Context, databaseID string) (Database, erro
 
 // CreateDatabase creates a new database.
 func (api *API) CreateDatabase(ctx context.Context, opts DatabaseCreateOpts) (Database, error) {
+	if err := api.guardEngine(ctx, opts.DatastoreID, CapabilityDatabases); err != nil {
+		return Database{}, err
+	}
 	createDatabaseOpts := struct {
 		Database DatabaseCreateOpts `json:"database"`
 	}{
@@ -157,3 +166,41 @@ func (api *API) DeleteDatabase(ctx context.Context, databaseID string) error {
 
 	return nil
 }
+
+// CreateDatabaseAndWait creates a new database and waits until it becomes active.
+func (api *API) CreateDatabaseAndWait(
+	ctx context.Context,
+	opts DatabaseCreateOpts,
+	waitOpts *WaitOpts,
+) (Database, error) {
+	database, err := api.CreateDatabase(ctx, opts)
+	if err != nil {
+		return Database{}, err
+	}
+
+	return WaitFor(ctx, api.Database, database.ID, []Status{StatusActive}, waitOpts)
+}
+
+// UpdateDatabaseAndWait updates an existing database and waits until it becomes active.
+func (api *API) UpdateDatabaseAndWait(
+	ctx context.Context,
+	databaseID string,
+	opts DatabaseUpdateOpts,
+	waitOpts *WaitOpts,
+) (Database, error) {
+	database, err := api.UpdateDatabase(ctx, databaseID, opts)
+	if err != nil {
+		return Database{}, err
+	}
+
+	return waitForUpdate(ctx, api.Database, databaseID, database, waitOpts)
+}
+
+// DeleteDatabaseAndWait deletes an existing database and waits until it disappears.
+func (api *API) DeleteDatabaseAndWait(ctx context.Context, databaseID string, waitOpts *WaitOpts) error {
+	if err := api.DeleteDatabase(ctx, databaseID); err != nil {
+		return err
+	}
+
+	return WaitForDeleted(ctx, api.Database, databaseID, waitOpts)
+}
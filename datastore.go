@@ -88,6 +88,11 @@ type Datastore struct {
 	Enabled             bool              `json:"enabled"`
 }
 
+// ResourceStatus returns the datastore status.
+func (d Datastore) ResourceStatus() Status {
+	return d.Status
+}
+
 // Disk represents disk parameters for a get/create datastore ops.
 type Disk struct {
 	Type string `json:"type"`
@@ -155,18 +160,19 @@ type DatastorePasswordOpts struct {
 
 // DatastoreQueryParams represents available query parameters for datastore.
 type DatastoreQueryParams struct {
-	ID            string `json:"id,omitempty"`
-	ProjectID     string `json:"project_id,omitempty"`
-	Name          string `json:"name,omitempty"`
-	Status        Status `json:"status,omitempty"`
-	Enabled       string `json:"enabled,omitempty"`
-	TypeID        string `json:"type_id,omitempty"`
-	FlavorID      string `json:"flavor_id,omitempty"`
-	SubnetID      string `json:"subnet_id,omitempty"`
-	AllowRestore  bool   `json:"allow_restore,omitempty"`
-	IsMaintenance bool   `json:"is_maintenance,omitempty"`
-	IsProtected   bool   `json:"is_protected,omitempty"`
-	Deleted       bool   `json:"deleted,omitempty"`
+	ID        string `json:"id,omitempty"`
+	ProjectID string `json:"project_id,omitempty"`
+	Name      string `json:"name,omitempty"`
+	Status    Status `json:"status,omitempty"`
+	Enabled   string `json:"enabled,omitempty"`
+	TypeID    string `json:"type_id,omitempty"`
+	FlavorID  string `json:"flavor_id,omitempty"`
+	SubnetID  string `json:"subnet_id,omitempty"`
+	Page
+	AllowRestore  bool `json:"allow_restore,omitempty"`
+	IsMaintenance bool `json:"is_maintenance,omitempty"`
+	IsProtected   bool `json:"is_protected,omitempty"`
+	Deleted       bool `json:"deleted,omitempty"`
 }
 
 // DatastoreBackupsOpts represents update options for the Datastore backups.
@@ -237,8 +243,14 @@ func (api *API) Datastore(ctx context.Context, datastoreID string) (Datastore, e
 }
 
 // CreateDatastore creates a new datastore.
+// String config values are converted to the types of the configuration parameters of the datastore type.
+// If the config has strings that could be read as other types, it makes an extra GET request to load
+// the configuration parameters. If it fails, the types are guessed from strings.
 func (api *API) CreateDatastore(ctx context.Context, opts DatastoreCreateOpts) (Datastore, error) {
-	config := convertConfigValues(opts.Config)
+	config, err := api.convertConfig(ctx, opts.TypeID, opts.Config)
+	if err != nil {
+		return Datastore{}, err
+	}
 	createDatastoreOpts := struct {
 		Datastore DatastoreCreateOpts `json:"datastore"`
 	}{
@@ -382,6 +394,9 @@ func (api *API) PoolerDatastore(ctx context.Context, datastoreID string, opts Da
 	if err := uuid.Validate(datastoreID); err != nil {
 		return Datastore{}, fmt.Errorf("validate datastore id: %w", err)
 	}
+	if err := api.guardEngine(ctx, datastoreID, CapabilityPooler); err != nil {
+		return Datastore{}, err
+	}
 
 	uri := fmt.Sprintf("%s/%s/pooler", DatastoresURI, datastoreID)
 	poolerDatastoreOpts := struct {
@@ -444,13 +459,36 @@ func (api *API) FirewallDatastore(ctx context.Context, datastoreID string, opts
 }
 
 // ConfigDatastore updates configuration parameters rules of an existing datastore.
+// String config values are converted to the types of the configuration parameters of the datastore type.
+// If the config has strings that could be read as other types, it makes two extra GET requests to load
+// the datastore and the configuration parameters. If they fail, the types are guessed from strings.
 func (api *API) ConfigDatastore(ctx context.Context, datastoreID string, opts DatastoreConfigOpts) (Datastore, error) { //nolint
 	if err := uuid.Validate(datastoreID); err != nil {
 		return Datastore{}, fmt.Errorf("validate datastore id: %w", err)
 	}
 
+	datastoreTypeID := ""
+	if needsConfigSchema(opts.Config) {
+		if datastore, err := api.Datastore(ctx, datastoreID); err == nil {
+			datastoreTypeID = datastore.TypeID
+		}
+	}
+	config, err := api.convertConfig(ctx, datastoreTypeID, opts.Config)
+	if err != nil {
+		return Datastore{}, err
+	}
+	opts.Config = config
+
+	return api.sendDatastoreConfig(ctx, datastoreID, opts)
+}
+
+// sendDatastoreConfig sends configuration parameters of an existing datastore without conversion.
+func (api *API) sendDatastoreConfig(
+	ctx context.Context,
+	datastoreID string,
+	opts DatastoreConfigOpts,
+) (Datastore, error) {
 	uri := fmt.Sprintf("%s/%s/config", DatastoresURI, datastoreID)
-	opts.Config = convertConfigValues(opts.Config)
 	requestBody, err := json.Marshal(opts)
 	if err != nil {
 		return Datastore{}, fmt.Errorf("Error marshalling params to JSON, %w", err)
@@ -477,6 +515,9 @@ func (api *API) PasswordDatastore(ctx context.Context, datastoreID string, opts
 	if err := uuid.Validate(datastoreID); err != nil {
 		return Datastore{}, fmt.Errorf("validate datastore id: %w", err)
 	}
+	if err := api.guardEngine(ctx, datastoreID, CapabilityPassword); err != nil {
+		return Datastore{}, err
+	}
 
 	uri := fmt.Sprintf("%s/%s/password", DatastoresURI, datastoreID)
 	passwordDatastoreOpts := struct {
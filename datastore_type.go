@@ -16,6 +16,14 @@ type DatastoreType struct {
 
 const DatastoreTypesURI = "/datastore-types"
 
+// Engines of the datastore types, see DatastoreType.Engine.
+const (
+	EnginePostgreSQL = "postgresql"
+	EngineMySQL      = "mysql"
+	EngineRedis      = "redis"
+	EngineKafka      = "kafka"
+)
+
 // DatastoreTypes returns all datastore types.
 func (api *API) DatastoreTypes(ctx context.Context) ([]DatastoreType, error) {
 	resp, err := api.makeRequest(ctx, http.MethodGet, DatastoreTypesURI, nil)
@@ -0,0 +1,235 @@
+package dbaas
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+var (
+	// ErrNoDatastoreType is returned if no datastore type matches the engine and the version constraint.
+	ErrNoDatastoreType = errors.New("no datastore type matches")
+
+	// ErrInvalidVersionConstraint is returned if the version constraint can't be parsed.
+	ErrInvalidVersionConstraint = errors.New("invalid version constraint")
+)
+
+// VersionLatest is the version constraint that matches the latest version of the engine.
+const VersionLatest = "latest"
+
+// String returns the engine and the version of the datastore type, e.g. "postgresql 16".
+func (t DatastoreType) String() string {
+	return t.Engine + " " + t.Version
+}
+
+// versionConstraint is a set of conditions that a version must meet.
+type versionConstraint []func(version []int) bool
+
+// matches reports whether the version meets all conditions.
+func (c versionConstraint) matches(version []int) bool {
+	for _, condition := range c {
+		if !condition(version) {
+			return false
+		}
+	}
+	return true
+}
+
+// ResolveDatastoreType returns the latest datastore type of the engine that matches the version constraint.
+// The constraint is one of:
+//   - empty or "latest" for the latest version;
+//   - a version, e.g. "16" or "3.5", that matches it and every version with this prefix,
+//     "16.x" is the same as "16";
+//   - "=" followed by a version that matches only this exact version;
+//   - "~3.5" for patch versions (>=3.5 <3.6) and "^3.5" for minor versions (>=3.5 <4);
+//   - comparisons with ">", ">=", "<" and "<=", e.g. ">=14 <16" or ">=14, <16".
+func ResolveDatastoreType(datastoreTypes []DatastoreType, engine, constraint string) (DatastoreType, error) {
+	condition, err := parseVersionConstraint(constraint)
+	if err != nil {
+		return DatastoreType{}, err
+	}
+
+	var engineTypes []DatastoreType
+	engines := make(map[string]bool)
+	for _, datastoreType := range datastoreTypes {
+		engines[datastoreType.Engine] = true
+		if strings.EqualFold(datastoreType.Engine, engine) {
+			engineTypes = append(engineTypes, datastoreType)
+		}
+	}
+	if len(engineTypes) == 0 {
+		available := make([]string, 0, len(engines))
+		for name := range engines {
+			available = append(available, name)
+		}
+		sort.Strings(available)
+		return DatastoreType{}, fmt.Errorf("%w: unknown engine %q, available engines: %s",
+			ErrNoDatastoreType, engine, strings.Join(available, ", "))
+	}
+
+	var found DatastoreType
+	var foundVersion []int
+	versions := make([]string, 0, len(engineTypes))
+	for _, datastoreType := range engineTypes {
+		versions = append(versions, datastoreType.Version)
+		version, err := parseVersion(datastoreType.Version)
+		if err != nil || !condition.matches(version) {
+			continue
+		}
+		if foundVersion == nil || compareVersions(version, foundVersion) > 0 {
+			found, foundVersion = datastoreType, version
+		}
+	}
+	if foundVersion == nil {
+		return DatastoreType{}, fmt.Errorf("%w: %s %s, available versions: %s",
+			ErrNoDatastoreType, engine, constraint, strings.Join(versions, ", "))
+	}
+
+	return found, nil
+}
+
+// ResolveDatastoreType lists datastore types and returns the latest one of the engine
+// that matches the version constraint, see ResolveDatastoreType.
+func (api *API) ResolveDatastoreType(ctx context.Context, engine, constraint string) (DatastoreType, error) {
+	datastoreTypes, err := api.DatastoreTypes(ctx)
+	if err != nil {
+		return DatastoreType{}, err
+	}
+
+	return ResolveDatastoreType(datastoreTypes, engine, constraint)
+}
+
+// DatastoreTypeByID returns the datastore type with the ID, e.g. to find the engine of Datastore.TypeID
+// in the result of DatastoreTypes.
+func DatastoreTypeByID(datastoreTypes []DatastoreType, datastoreTypeID string) (DatastoreType, bool) {
+	for _, datastoreType := range datastoreTypes {
+		if datastoreType.ID == datastoreTypeID {
+			return datastoreType, true
+		}
+	}
+	return DatastoreType{}, false
+}
+
+// DatastoreTypeOf returns the datastore type of the datastore with its engine and version.
+func (api *API) DatastoreTypeOf(ctx context.Context, datastore Datastore) (DatastoreType, error) {
+	return api.DatastoreType(ctx, datastore.TypeID)
+}
+
+// parseVersionConstraint parses conditions separated by spaces or commas.
+func parseVersionConstraint(constraint string) (versionConstraint, error) {
+	terms := strings.FieldsFunc(constraint, func(r rune) bool { return r == ',' || r == ' ' })
+	result := make(versionConstraint, 0, len(terms))
+	for _, term := range terms {
+		condition, err := parseVersionCondition(term)
+		if err != nil {
+			return nil, fmt.Errorf("%w %q: %w", ErrInvalidVersionConstraint, constraint, err)
+		}
+		if condition != nil {
+			result = append(result, condition)
+		}
+	}
+
+	return result, nil
+}
+
+// parseVersionCondition parses a single condition, it returns nil if the condition matches any version.
+func parseVersionCondition(term string) (func(version []int) bool, error) {
+	if term == VersionLatest || term == "*" {
+		return nil, nil
+	}
+
+	operators := []string{">=", "<=", "==", ">", "<", "=", "~", "^"}
+	operator := ""
+	for _, op := range operators {
+		if strings.HasPrefix(term, op) {
+			operator = op
+			break
+		}
+	}
+	bound, err := parseVersion(strings.TrimSuffix(strings.TrimSuffix(term[len(operator):], ".x"), ".*"))
+	if err != nil {
+		return nil, err
+	}
+
+	switch operator {
+	case ">=":
+		return func(version []int) bool { return compareVersions(version, bound) >= 0 }, nil
+	case "<=":
+		return func(version []int) bool { return compareVersions(version, bound) <= 0 }, nil
+	case ">":
+		return func(version []int) bool { return compareVersions(version, bound) > 0 }, nil
+	case "<":
+		return func(version []int) bool { return compareVersions(version, bound) < 0 }, nil
+	case "=", "==":
+		return func(version []int) bool { return compareVersions(version, bound) == 0 }, nil
+	case "~":
+		prefix := bound
+		if len(prefix) > 2 {
+			prefix = prefix[:2]
+		}
+		return func(version []int) bool {
+			return compareVersions(version, bound) >= 0 && versionHasPrefix(version, prefix)
+		}, nil
+	case "^":
+		return func(version []int) bool {
+			return compareVersions(version, bound) >= 0 && versionHasPrefix(version, bound[:1])
+		}, nil
+	default:
+		return func(version []int) bool { return versionHasPrefix(version, bound) }, nil
+	}
+}
+
+// parseVersion parses dot-separated numeric components, e.g. "3.5" or "v16".
+func parseVersion(version string) ([]int, error) {
+	version = strings.TrimPrefix(version, "v")
+	if version == "" {
+		return nil, errors.New("empty version")
+	}
+	parts := strings.Split(version, ".")
+	result := make([]int, 0, len(parts))
+	for _, part := range parts {
+		number, err := strconv.Atoi(part)
+		if err != nil || number < 0 {
+			return nil, fmt.Errorf("version %q is not numeric", version)
+		}
+		result = append(result, number)
+	}
+
+	return result, nil
+}
+
+// compareVersions compares versions by components, missing components are zero.
+func compareVersions(a, b []int) int {
+	for i := 0; i < len(a) || i < len(b); i++ {
+		var x, y int
+		if i < len(a) {
+			x = a[i]
+		}
+		if i < len(b) {
+			y = b[i]
+		}
+		if x != y {
+			if x < y {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
+
+// versionHasPrefix reports whether the version starts with the components of the prefix.
+func versionHasPrefix(version, prefix []int) bool {
+	if len(version) < len(prefix) {
+		return false
+	}
+	for i := range prefix {
+		if version[i] != prefix[i] {
+			return false
+		}
+	}
+	return true
+}
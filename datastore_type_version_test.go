@@ -0,0 +1,108 @@
+package dbaas
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testResolveDatastoreTypes() []DatastoreType {
+	return []DatastoreType{
+		{ID: "pg-14", Engine: EnginePostgreSQL, Version: "14"},
+		{ID: "pg-16", Engine: EnginePostgreSQL, Version: "16"},
+		{ID: "pg-15", Engine: EnginePostgreSQL, Version: "15"},
+		{ID: "kafka-3.5", Engine: EngineKafka, Version: "3.5"},
+		{ID: "kafka-3.5.1", Engine: EngineKafka, Version: "3.5.1"},
+		{ID: "kafka-3.6", Engine: EngineKafka, Version: "3.6"},
+		{ID: "redis-7", Engine: EngineRedis, Version: "7"},
+	}
+}
+
+func TestResolveDatastoreType(t *testing.T) {
+	tests := []struct {
+		engine     string
+		constraint string
+		expected   string
+	}{
+		{EnginePostgreSQL, "", "pg-16"},
+		{EnginePostgreSQL, "latest", "pg-16"},
+		{"PostgreSQL", "15", "pg-15"},
+		{EnginePostgreSQL, "=14", "pg-14"},
+		{EnginePostgreSQL, ">=14 <16", "pg-15"},
+		{EnginePostgreSQL, ">=14, <=15", "pg-15"},
+		{EnginePostgreSQL, "<15", "pg-14"},
+		{EngineKafka, "3", "kafka-3.6"},
+		{EngineKafka, "3.5", "kafka-3.5.1"},
+		{EngineKafka, "3.5.x", "kafka-3.5.1"},
+		{EngineKafka, "=3.5", "kafka-3.5"},
+		{EngineKafka, "~3.5", "kafka-3.5.1"},
+		{EngineKafka, "^3.5", "kafka-3.6"},
+		{EngineKafka, ">3.5 <3.6", "kafka-3.5.1"},
+		{EngineRedis, "v7", "redis-7"},
+	}
+
+	for _, test := range tests {
+		actual, err := ResolveDatastoreType(testResolveDatastoreTypes(), test.engine, test.constraint)
+
+		require.NoError(t, err, test.constraint)
+		assert.Equal(t, test.expected, actual.ID, "%s %s", test.engine, test.constraint)
+	}
+}
+
+func TestResolveDatastoreTypeErrors(t *testing.T) {
+	_, err := ResolveDatastoreType(testResolveDatastoreTypes(), EnginePostgreSQL, ">=17")
+
+	assert.ErrorIs(t, err, ErrNoDatastoreType)
+	assert.Equal(t, "no datastore type matches: postgresql >=17, available versions: 14, 16, 15", err.Error())
+
+	_, err = ResolveDatastoreType(testResolveDatastoreTypes(), EngineMySQL, "8")
+
+	assert.ErrorIs(t, err, ErrNoDatastoreType)
+	assert.Contains(t, err.Error(), `unknown engine "mysql", available engines: kafka, postgresql, redis`)
+
+	_, err = ResolveDatastoreType(testResolveDatastoreTypes(), EnginePostgreSQL, ">=sixteen")
+
+	assert.ErrorIs(t, err, ErrInvalidVersionConstraint)
+}
+
+func TestDatastoreTypeByID(t *testing.T) {
+	actual, ok := DatastoreTypeByID(testResolveDatastoreTypes(), "redis-7")
+
+	assert.True(t, ok)
+	assert.Equal(t, "redis 7", actual.String())
+
+	_, ok = DatastoreTypeByID(testResolveDatastoreTypes(), "unknown")
+
+	assert.False(t, ok)
+}
+
+func TestAPIResolveDatastoreType(t *testing.T) {
+	httpmock.Activate()
+	testClient := SetupTestClient()
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder("GET", testClient.Endpoint+DatastoreTypesURI,
+		httpmock.NewStringResponder(200, testDatastoreTypesResponse))
+
+	actual, err := testClient.ResolveDatastoreType(context.Background(), EngineMySQL, VersionLatest)
+
+	require.NoError(t, err)
+	assert.Equal(t, EngineMySQL, actual.Engine)
+}
+
+func TestDatastoreTypeOf(t *testing.T) {
+	httpmock.Activate()
+	testClient := SetupTestClient()
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder("GET", testClient.Endpoint+DatastoreTypesURI+"/"+datastoreTypeID,
+		httpmock.NewStringResponder(200, testDatastoreTypeResponse))
+
+	actual, err := testClient.DatastoreTypeOf(context.Background(), Datastore{TypeID: datastoreTypeID})
+
+	require.NoError(t, err)
+	assert.Equal(t, "mysql 8", actual.String())
+}
@@ -10,9 +10,7 @@ import (
 	"net/http"
 	"net/url"
 	"strconv"
-
-	"github.com/gophercloud/gophercloud"
-	"github.com/gophercloud/gophercloud/openstack"
+	"time"
 )
 
 const (
@@ -53,105 +51,125 @@ const (
 // API stores details that are needed to work with Selectel DBaaS API.
 type API struct {
 	HTTPClient *http.Client
-	Token      string
-	Endpoint   string
-	UserAgent  string
+
+	// RetryPolicy specifies how failed requests are retried.
+	// If it is nil - every request is made only once.
+	RetryPolicy *RetryPolicy
+
+	// Logger receives details of every request attempt.
+	// If it is nil - nothing is logged.
+	Logger Logger
+
+	// EngineGuard checks that the datastore engine supports the operation before the request is made.
+	// If it is nil - operations are not checked and the server rejects unsupported ones.
+	EngineGuard *EngineGuard
+
+	// TokenSource provides authentication tokens for every request.
+	// If it is nil - Token is used.
+	TokenSource TokenSource
+
+	// Headers are added to every request.
+	Headers http.Header
+
+	Token     string
+	Endpoint  string
+	UserAgent string
 }
 
 // NewDBAASClient initializes a new DBaaS client for the V1 API.
 func NewDBAASClient(token, endpoint string) (*API, error) {
-	return &API{
-		HTTPClient: http.DefaultClient,
-		Token:      token,
-		Endpoint:   endpoint,
-		UserAgent:  userAgent,
-	}, nil
+	return New(endpoint, WithToken(token))
 }
 
 // NewDBAASClientV1WithCustomHTTP initializes a new DBaaS client for the V1 API using custom HTTP client.
 // If custom HTTP client is nil - default HTTP client will be used.
 func NewDBAASClientV1WithCustomHTTP(customHTTPClient *http.Client, token, endpoint string) (*API, error) {
-	if customHTTPClient == nil {
-		customHTTPClient = http.DefaultClient
-	}
-	return &API{
-		HTTPClient: customHTTPClient,
-		Token:      token,
-		Endpoint:   endpoint,
-		UserAgent:  userAgent,
-	}, nil
+	return New(endpoint, WithHTTPClient(customHTTPClient), WithToken(token))
 }
 
 // NewDBAASClientV1WithOpenstackCredentials initializes a new DBaaS client for the V1 API using openstack credentials.
 // You need to provide identityEndpoint, region and serviceType to get correct service endpoint.
 func NewDBAASClientV1WithOpenstackCredentials(token, identityEndpoint, region, serviceType string) (*API, error) {
-	opts := gophercloud.AuthOptions{
-		IdentityEndpoint: identityEndpoint,
-		TokenID:          token,
-	}
-
-	provider, err := openstack.AuthenticatedClient(opts)
-	if err != nil {
-		return nil, fmt.Errorf("could not authenticate to openstack, %w", err)
-	}
-	endpointOpts := gophercloud.EndpointOpts{Region: region}
-	endpointOpts.ApplyDefaults(serviceType)
-	endpoint, err := provider.EndpointLocator(endpointOpts)
-	if err != nil {
-		return nil, fmt.Errorf("could not locate an endpoint, %w", err)
-	}
-
-	return &API{
-		HTTPClient: http.DefaultClient,
-		Token:      token,
-		Endpoint:   endpoint,
-		UserAgent:  userAgent,
-	}, nil
+	return New("", WithToken(token), WithOpenstackEndpoint(identityEndpoint, region, serviceType))
 }
 
 // makeRequest makes a HTTP request and returns the body as a byte slice.
 // Params will be serialized to JSON.
+// Failed requests are repeated according to the API retry policy.
+// Request rejected with 401 is repeated once if the token source can renew its token.
 func (api *API) makeRequest(ctx context.Context, method, uri string, params interface{}) ([]byte, error) {
 	jsonBody, err := handleParams(params)
 	if err != nil {
 		return nil, err
 	}
 
-	var resp *http.Response
-	var respErr error
+	maxAttempts := api.RetryPolicy.maxAttempts()
+	reauthenticated := false
+	for attempt := 1; ; attempt++ {
+		resp, respBody, err := api.attempt(ctx, method, uri, jsonBody, attempt)
+		if err == nil && resp.StatusCode < http.StatusBadRequest {
+			return respBody, nil
+		}
+		if err == nil && resp.StatusCode == http.StatusUnauthorized && !reauthenticated {
+			if tokenSource, ok := api.TokenSource.(ExpirableTokenSource); ok {
+				tokenSource.Expire(resp.Request.Header.Get("X-Auth-Token"))
+				reauthenticated = true
+				maxAttempts++
+				continue
+			}
+		}
+		if err == nil {
+			err = handleStatusCode(resp, respBody, method, uri)
+		}
+
+		if attempt >= maxAttempts || !api.RetryPolicy.retryable(method, resp, err) {
+			return nil, err
+		}
+		if waitErr := api.RetryPolicy.wait(ctx, attempt, resp); waitErr != nil {
+			return nil, err
+		}
+	}
+}
+
+// attempt makes a single HTTP request and reads the whole response body.
+// The returned response body is already closed.
+func (api *API) attempt(
+	ctx context.Context,
+	method, uri string,
+	jsonBody []byte,
+	attempt int,
+) (*http.Response, []byte, error) {
 	var reqBody io.Reader
-	var respBody []byte
 	if jsonBody != nil {
 		reqBody = bytes.NewReader(jsonBody)
 	}
 
-	resp, respErr = api.request(ctx, method, uri, reqBody)
-	if respErr != nil || resp.StatusCode >= http.StatusInternalServerError {
-		if respErr == nil {
-			respBody, err = io.ReadAll(resp.Body)
-			resp.Body.Close()
-
-			respErr = fmt.Errorf("could not read response body, %w", err)
-			fmt.Printf("Request: %s %s got an error response %d", method, uri, resp.StatusCode)
-		} else {
-			fmt.Printf("Error performing request: %s %s : %s \n", method, uri, respErr.Error())
-		}
-	} else {
-		respBody, err = io.ReadAll(resp.Body)
-		defer resp.Body.Close()
-		if err != nil {
-			return nil, fmt.Errorf("could not read response body, %w", err)
-		}
+	info := RequestInfo{
+		Method:  method,
+		URI:     uri,
+		Attempt: attempt,
 	}
-	if respErr != nil {
-		return nil, respErr
+	start := time.Now()
+	defer func() {
+		info.Latency = time.Since(start)
+		api.logger().LogRequest(ctx, info)
+	}()
+
+	resp, err := api.request(ctx, method, uri, reqBody)
+	if err != nil {
+		info.Err = err
+		return nil, nil, err
 	}
+	defer resp.Body.Close()
+	info.StatusCode = resp.StatusCode
 
-	if resp.StatusCode >= http.StatusBadRequest {
-		return nil, handleStatusCode(resp.StatusCode, respBody, uri)
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		info.Err = err
+		return nil, nil, fmt.Errorf("could not read response body, %w", err)
 	}
 
-	return respBody, nil
+	return resp, respBody, nil
 }
 
 // request makes a HTTP request to the given API endpoint, returning the raw
@@ -163,8 +181,18 @@ func (api *API) request(ctx context.Context, method, uri string, body io.Reader)
 		return nil, fmt.Errorf("HTTP request creation failed, %w", err)
 	}
 
+	token, err := api.token(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("could not get auth token, %w", err)
+	}
+
+	for key, values := range api.Headers {
+		for _, value := range values {
+			req.Header.Add(key, value)
+		}
+	}
 	req.Header.Set("User-Agent", api.UserAgent)
-	req.Header.Set("X-Auth-Token", api.Token)
+	req.Header.Set("X-Auth-Token", token)
 	if body != nil {
 		req.Header.Set("Content-Type", "application/json")
 	}
@@ -173,6 +201,10 @@ func (api *API) request(ctx context.Context, method, uri string, body io.Reader)
 	if err != nil {
 		return nil, fmt.Errorf("HTTP request failed, %w", err)
 	}
+	if resp.Request == nil {
+		// Custom transports are not obliged to set the request, but it is needed to renew the token.
+		resp.Request = req
+	}
 
 	return resp, nil
 }
@@ -198,18 +230,25 @@ func handleParams(params any) ([]byte, error) {
 	return jsonBody, nil
 }
 
-// handleStatusCode checks status code and returns corresponding error.
-func handleStatusCode(statusCode int, body []byte, uri string) error {
-	if statusCode >= http.StatusInternalServerError {
-		return fmt.Errorf("http status %d: service failed.\n%v\n%v", statusCode, body, uri) //nolint
+// handleStatusCode builds an error from the failed response.
+// Responses with a body that doesn't follow the API error schema get the title from the HTTP status text.
+func handleStatusCode(resp *http.Response, body []byte, method, uri string) error {
+	apiErr := &DBaaSAPIError{}
+	if err := json.Unmarshal(body, apiErr); err != nil || apiErr.APIError.Title == "" {
+		apiErr.APIError.Title = http.StatusText(resp.StatusCode)
+		apiErr.APIError.Message = string(body)
+		apiErr.APIError.Code = resp.StatusCode
 	}
-
-	errBody := &DBaaSAPIError{}
-	err := json.Unmarshal(body, &errBody)
-	if err != nil {
-		return fmt.Errorf("can't unmarshal response:\n%s, %w", body, err)
+	apiErr.Method = method
+	apiErr.URI = uri
+	apiErr.RequestID = resp.Header.Get("X-Request-Id")
+	if apiErr.RequestID == "" {
+		apiErr.RequestID = resp.Header.Get("X-Openstack-Request-Id")
 	}
-	return errBody
+	apiErr.Body = body
+	apiErr.HTTPStatus = resp.StatusCode
+
+	return apiErr
 }
 
 // setQueryParams updates uri string with query parameters.
@@ -222,7 +261,9 @@ func setQueryParams(uri string, params any) (string, error) {
 		return "", fmt.Errorf("Error marshalling params to JSON, %w", err)
 	}
 
-	err = json.Unmarshal(jsonParams, &queryParams)
+	decoder := json.NewDecoder(bytes.NewReader(jsonParams))
+	decoder.UseNumber()
+	err = decoder.Decode(&queryParams)
 	if err != nil {
 		return "", fmt.Errorf("Error during Unmarshal, %w", err)
 	}
@@ -237,6 +278,15 @@ func setQueryParams(uri string, params any) (string, error) {
 	return uri, nil
 }
 
+// optionalParams returns the optional query parameters of a list request or nil if they are not passed.
+func optionalParams[T any](params []*T) *T {
+	if len(params) == 0 {
+		return nil
+	}
+
+	return params[0]
+}
+
 // convertFieldToType converts interface to the corresponding type.
 func convertFieldToType(fieldValue any) any {
 	switch fieldValue := fieldValue.(type) {
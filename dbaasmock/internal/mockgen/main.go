@@ -0,0 +1,199 @@
+// Command mockgen generates mocks of the dbaas service interfaces.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"log"
+	"os"
+	"strings"
+	"unicode"
+)
+
+// sourcePackage is the name of the package that declares the interfaces.
+const sourcePackage = "dbaas"
+
+func main() {
+	source := flag.String("source", "../services.go", "file with the service interfaces")
+	output := flag.String("output", "services_gen.go", "file to write the mocks to")
+	flag.Parse()
+
+	code, err := generate(*source)
+	if err != nil {
+		log.Fatalf("mockgen: %v", err)
+	}
+	if err := os.WriteFile(*output, code, 0o600); err != nil {
+		log.Fatalf("mockgen: %v", err)
+	}
+}
+
+// generate returns the formatted source of mocks for all interfaces declared in the file.
+func generate(source string) ([]byte, error) {
+	file, err := parser.ParseFile(token.NewFileSet(), source, nil, parser.ParseComments)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("// Code generated by internal/mockgen. DO NOT EDIT.\n\n")
+	buf.WriteString("package dbaasmock\n\n")
+	buf.WriteString("import (\n\t\"context\"\n\n\t\"github.com/selectel/dbaas-go\"\n)\n")
+
+	var names []string
+	for _, decl := range file.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.TYPE {
+			continue
+		}
+		for _, spec := range gen.Specs {
+			typeSpec, ok := spec.(*ast.TypeSpec)
+			if !ok {
+				continue
+			}
+			iface, ok := typeSpec.Type.(*ast.InterfaceType)
+			if !ok {
+				continue
+			}
+			if err := writeMock(&buf, typeSpec.Name.Name, iface); err != nil {
+				return nil, fmt.Errorf("%s: %w", typeSpec.Name.Name, err)
+			}
+			names = append(names, typeSpec.Name.Name)
+		}
+	}
+
+	buf.WriteString("\n// Compile-time checks that mocks implement the service interfaces.\nvar (\n")
+	for _, name := range names {
+		fmt.Fprintf(&buf, "\t_ %s.%s = (*%s)(nil)\n", sourcePackage, name, name)
+	}
+	buf.WriteString(")\n")
+
+	return format.Source(buf.Bytes())
+}
+
+// writeMock writes the mock type and its methods.
+func writeMock(buf *bytes.Buffer, name string, iface *ast.InterfaceType) error {
+	fmt.Fprintf(buf, "\n// %s is a mock of %s.%s.\n", name, sourcePackage, name)
+	fmt.Fprintf(buf, "type %s struct {\n\tMock\n}\n", name)
+
+	for _, method := range iface.Methods.List {
+		funcType, ok := method.Type.(*ast.FuncType)
+		if !ok || len(method.Names) != 1 {
+			return fmt.Errorf("embedded interfaces are not supported")
+		}
+		if err := writeMethod(buf, name, method.Names[0].Name, funcType); err != nil {
+			return fmt.Errorf("%s: %w", method.Names[0].Name, err)
+		}
+	}
+	return nil
+}
+
+// writeMethod writes the mock method that records the call and returns the expected results.
+func writeMethod(buf *bytes.Buffer, mockName, name string, funcType *ast.FuncType) error {
+	var params, args []string
+	variadic := ""
+	for i, field := range funcType.Params.List {
+		typ, err := typeString(field.Type)
+		if err != nil {
+			return err
+		}
+		fieldNames := field.Names
+		if len(fieldNames) == 0 {
+			fieldNames = []*ast.Ident{ast.NewIdent(fmt.Sprintf("arg%d", i))}
+		}
+		for _, fieldName := range fieldNames {
+			params = append(params, fieldName.Name+" "+typ)
+			switch {
+			case strings.HasPrefix(typ, "..."):
+				variadic = fieldName.Name
+			case typ != "context.Context":
+				args = append(args, fieldName.Name)
+			}
+		}
+	}
+
+	var results []string
+	if funcType.Results != nil {
+		for _, field := range funcType.Results.List {
+			typ, err := typeString(field.Type)
+			if err != nil {
+				return err
+			}
+			results = append(results, typ)
+		}
+	}
+
+	fmt.Fprintf(buf, "\n// %s mocks %s.%s.%s.\n", name, sourcePackage, mockName, name)
+	fmt.Fprintf(buf, "func (m *%s) %s(%s) (%s) {\n", mockName, name, strings.Join(params, ", "),
+		strings.Join(results, ", "))
+
+	called := fmt.Sprintf("m.Called(%q)", name)
+	switch {
+	case variadic != "":
+		// Variadic arguments are recorded one by one, so they are matched like other arguments.
+		fmt.Fprintf(buf, "\targs := []any{%s}\n", strings.Join(args, ", "))
+		fmt.Fprintf(buf, "\tfor _, arg := range %s {\n\t\targs = append(args, arg)\n\t}\n", variadic)
+		called = fmt.Sprintf("m.Called(%q, args...)", name)
+	case len(args) > 0:
+		called = fmt.Sprintf("m.Called(%q, %s)", name, strings.Join(args, ", "))
+	}
+	if len(results) == 1 && results[0] == "error" {
+		fmt.Fprintf(buf, "\treturn %s.Error(0)\n}\n", called)
+		return nil
+	}
+
+	fmt.Fprintf(buf, "\tresults := %s\n", called)
+	returns := make([]string, 0, len(results))
+	for i, typ := range results {
+		if typ == "error" {
+			returns = append(returns, fmt.Sprintf("results.Error(%d)", i))
+			continue
+		}
+		fmt.Fprintf(buf, "\tr%d, _ := results.Get(%d).(%s)\n", i, i, typ)
+		returns = append(returns, fmt.Sprintf("r%d", i))
+	}
+	fmt.Fprintf(buf, "\treturn %s\n}\n", strings.Join(returns, ", "))
+	return nil
+}
+
+// typeString returns the type expression qualified for use outside the source package.
+func typeString(expr ast.Expr) (string, error) {
+	switch e := expr.(type) {
+	case *ast.Ident:
+		if unicode.IsUpper(rune(e.Name[0])) {
+			return sourcePackage + "." + e.Name, nil
+		}
+		return e.Name, nil
+	case *ast.SelectorExpr:
+		pkg, ok := e.X.(*ast.Ident)
+		if !ok {
+			return "", fmt.Errorf("unsupported selector %T", e.X)
+		}
+		return pkg.Name + "." + e.Sel.Name, nil
+	case *ast.StarExpr:
+		elem, err := typeString(e.X)
+		return "*" + elem, err
+	case *ast.ArrayType:
+		if e.Len != nil {
+			return "", fmt.Errorf("arrays are not supported")
+		}
+		elem, err := typeString(e.Elt)
+		return "[]" + elem, err
+	case *ast.Ellipsis:
+		elem, err := typeString(e.Elt)
+		return "..." + elem, err
+	case *ast.MapType:
+		key, err := typeString(e.Key)
+		if err != nil {
+			return "", err
+		}
+		value, err := typeString(e.Value)
+		return "map[" + key + "]" + value, err
+	default:
+		return "", fmt.Errorf("unsupported type %T", expr)
+	}
+}
@@ -0,0 +1,19 @@
+package main
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGeneratedMocksAreUpToDate(t *testing.T) {
+	expected, err := generate("../../../services.go")
+	require.NoError(t, err)
+
+	actual, err := os.ReadFile("../../services_gen.go")
+	require.NoError(t, err)
+
+	assert.Equal(t, string(expected), string(actual), "run go generate ./dbaasmock/...")
+}
@@ -0,0 +1,237 @@
+// Package dbaasmock provides mock implementations of the dbaas service interfaces.
+//
+// Mocks record every call and return the values of the first matching expectation:
+//
+//	datastores := &dbaasmock.DatastoreService{}
+//	datastores.On("Datastore", "datastore-id").Return(dbaas.Datastore{ID: "datastore-id"}, nil)
+//	...
+//	datastores.AssertExpectations(t)
+//
+// Context arguments are neither recorded nor matched. Variadic arguments are recorded one by one,
+// e.g. On("Users", params) matches Users(ctx, params).
+package dbaasmock
+
+//go:generate go run ./internal/mockgen -source ../services.go -output services_gen.go
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// ErrUnexpectedCall is returned by mocks for calls that don't match any expectation.
+var ErrUnexpectedCall = errors.New("unexpected call")
+
+// Call is a recorded call of a mocked method.
+type Call struct {
+	Method string
+	Args   []any
+}
+
+// Matcher matches an argument of a call.
+// Expected arguments that are not matchers are compared with reflect.DeepEqual.
+type Matcher interface {
+	Match(arg any) bool
+}
+
+// MatcherFunc is an adapter to allow the use of ordinary functions as matchers.
+type MatcherFunc func(arg any) bool
+
+// Match calls f(arg).
+func (f MatcherFunc) Match(arg any) bool {
+	return f(arg)
+}
+
+// Any returns a matcher that matches any argument.
+func Any() Matcher {
+	return MatcherFunc(func(any) bool { return true })
+}
+
+// Expectation is an expected call of a mocked method.
+type Expectation struct {
+	run     func(args []any)
+	method  string
+	args    []any
+	returns []any
+	times   int
+	calls   int
+}
+
+// Return sets values returned by the call, in the order of the method results.
+func (e *Expectation) Return(values ...any) *Expectation {
+	e.returns = values
+	return e
+}
+
+// Times limits the number of calls matched by the expectation and makes them all required.
+func (e *Expectation) Times(n int) *Expectation {
+	e.times = n
+	return e
+}
+
+// Once limits the expectation to a single call.
+func (e *Expectation) Once() *Expectation {
+	return e.Times(1)
+}
+
+// Run sets a function called with the call arguments before returning the values.
+func (e *Expectation) Run(fn func(args []any)) *Expectation {
+	e.run = fn
+	return e
+}
+
+// matches checks if the expectation accepts the call.
+func (e *Expectation) matches(method string, args []any) bool {
+	if e.method != method || e.times > 0 && e.calls >= e.times {
+		return false
+	}
+	if e.args == nil {
+		return true
+	}
+	if len(e.args) != len(args) {
+		return false
+	}
+	for i, expected := range e.args {
+		if matcher, ok := expected.(Matcher); ok {
+			if !matcher.Match(args[i]) {
+				return false
+			}
+			continue
+		}
+		if !reflect.DeepEqual(expected, args[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// Results holds values returned by a mocked call.
+type Results struct {
+	err    error
+	values []any
+}
+
+// Get returns the result with the given index or nil.
+func (r Results) Get(i int) any {
+	if i >= len(r.values) {
+		return nil
+	}
+	return r.values[i]
+}
+
+// Error returns the error result with the given index.
+// It returns ErrUnexpectedCall if the call didn't match any expectation.
+func (r Results) Error(i int) error {
+	if r.err != nil {
+		return r.err
+	}
+	err, _ := r.Get(i).(error)
+	return err
+}
+
+// TestingT is a subset of testing.TB used to report unmet expectations.
+type TestingT interface {
+	Helper()
+	Errorf(format string, args ...any)
+}
+
+// Mock records calls and matches them against expectations.
+// The zero value is ready to use.
+type Mock struct {
+	expectations []*Expectation
+	calls        []Call
+	unexpected   []Call
+	mu           sync.Mutex
+}
+
+// On adds an expectation of the method call with the given arguments.
+// Without arguments the expectation matches any arguments.
+func (m *Mock) On(method string, args ...any) *Expectation {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	expectation := &Expectation{method: method}
+	if len(args) > 0 {
+		expectation.args = args
+	}
+	m.expectations = append(m.expectations, expectation)
+
+	return expectation
+}
+
+// Called records the call and returns the values of the first matching expectation.
+// It's used by the generated mocks.
+func (m *Mock) Called(method string, args ...any) Results {
+	m.mu.Lock()
+	call := Call{Method: method, Args: args}
+	m.calls = append(m.calls, call)
+
+	var expectation *Expectation
+	for _, e := range m.expectations {
+		if e.matches(method, args) {
+			expectation = e
+			break
+		}
+	}
+	if expectation == nil {
+		m.unexpected = append(m.unexpected, call)
+		m.mu.Unlock()
+		return Results{err: fmt.Errorf("%w: %s%v", ErrUnexpectedCall, method, args)}
+	}
+	expectation.calls++
+	m.mu.Unlock()
+
+	if expectation.run != nil {
+		expectation.run(args)
+	}
+
+	return Results{values: expectation.returns}
+}
+
+// Calls returns all recorded calls.
+func (m *Mock) Calls() []Call {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return append([]Call(nil), m.calls...)
+}
+
+// CallsTo returns recorded calls of the method.
+func (m *Mock) CallsTo(method string) []Call {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var calls []Call
+	for _, call := range m.calls {
+		if call.Method == method {
+			calls = append(calls, call)
+		}
+	}
+	return calls
+}
+
+// AssertExpectations reports expectations that were not met and unexpected calls.
+// An expectation is met if it was called at least once, or exactly Times times if set.
+func (m *Mock) AssertExpectations(t TestingT) bool {
+	t.Helper()
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	ok := true
+	for _, e := range m.expectations {
+		switch {
+		case e.times > 0 && e.calls < e.times:
+			t.Errorf("dbaasmock: %s%v was called %d times, expected %d", e.method, e.args, e.calls, e.times)
+			ok = false
+		case e.calls == 0:
+			t.Errorf("dbaasmock: %s%v was not called", e.method, e.args)
+			ok = false
+		}
+	}
+	for _, call := range m.unexpected {
+		t.Errorf("dbaasmock: unexpected call %s%v", call.Method, call.Args)
+		ok = false
+	}
+	return ok
+}
@@ -0,0 +1,116 @@
+package dbaasmock
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/selectel/dbaas-go"
+)
+
+// recordingT records reported errors instead of failing the test.
+type recordingT struct {
+	errors []string
+}
+
+func (t *recordingT) Helper() {}
+
+func (t *recordingT) Errorf(format string, args ...any) {
+	t.errors = append(t.errors, fmt.Sprintf(format, args...))
+}
+
+func datastoreName(ctx context.Context, service dbaas.DatastoreService, datastoreID string) (string, error) {
+	datastore, err := service.Datastore(ctx, datastoreID)
+	if err != nil {
+		return "", err
+	}
+	return datastore.Name, nil
+}
+
+func TestMockReturn(t *testing.T) {
+	datastores := &DatastoreService{}
+	datastores.On("Datastore", "first").Return(dbaas.Datastore{Name: "first"}, nil)
+	datastores.On("Datastore", Any()).Return(dbaas.Datastore{}, dbaas.ErrNotFound)
+
+	name, err := datastoreName(context.Background(), datastores, "first")
+	require.NoError(t, err)
+	assert.Equal(t, "first", name)
+
+	_, err = datastoreName(context.Background(), datastores, "second")
+	assert.ErrorIs(t, err, dbaas.ErrNotFound)
+
+	assert.Equal(t, []Call{
+		{Method: "Datastore", Args: []any{"first"}},
+		{Method: "Datastore", Args: []any{"second"}},
+	}, datastores.Calls())
+	assert.True(t, datastores.AssertExpectations(t))
+}
+
+func TestMockVariadic(t *testing.T) {
+	params := &dbaas.UserQueryParams{DatastoreID: "datastore-id"}
+	users := &UserService{}
+	users.On("Users", params).Return([]dbaas.User{{Name: "filtered"}}, nil).Once()
+	users.On("Users").Return([]dbaas.User{{Name: "first"}, {Name: "second"}}, nil)
+
+	actual, err := users.Users(context.Background(), &dbaas.UserQueryParams{DatastoreID: "datastore-id"})
+	require.NoError(t, err)
+	assert.Equal(t, []dbaas.User{{Name: "filtered"}}, actual)
+
+	actual, err = users.Users(context.Background())
+	require.NoError(t, err)
+	assert.Len(t, actual, 2)
+
+	users.AssertExpectations(t)
+}
+
+func TestMockTimes(t *testing.T) {
+	users := &UserService{}
+	users.On("DeleteUser", "user").Once()
+	users.On("CreateUser").Times(2).Return(dbaas.User{ID: "user"}, nil)
+
+	require.NoError(t, users.DeleteUser(context.Background(), "user"))
+	err := users.DeleteUser(context.Background(), "user")
+	assert.ErrorIs(t, err, ErrUnexpectedCall)
+
+	_, err = users.CreateUser(context.Background(), dbaas.UserCreateOpts{Name: "user"})
+	require.NoError(t, err)
+
+	recorder := &recordingT{}
+	assert.False(t, users.AssertExpectations(recorder))
+	assert.Equal(t, []string{
+		"dbaasmock: CreateUser[] was called 1 times, expected 2",
+		"dbaasmock: unexpected call DeleteUser[user]",
+	}, recorder.errors)
+	assert.Len(t, users.CallsTo("DeleteUser"), 2)
+}
+
+func TestMockRun(t *testing.T) {
+	topics := &TopicService{}
+	var partitions uint16
+	topics.On("CreateTopic", MatcherFunc(func(arg any) bool {
+		opts, ok := arg.(dbaas.TopicCreateOpts)
+		return ok && opts.Partitions > 0
+	})).Run(func(args []any) {
+		partitions = args[0].(dbaas.TopicCreateOpts).Partitions
+	}).Return(dbaas.Topic{ID: "topic"}, nil)
+
+	topic, err := topics.CreateTopic(context.Background(), dbaas.TopicCreateOpts{Partitions: 3})
+	require.NoError(t, err)
+	assert.Equal(t, "topic", topic.ID)
+	assert.Equal(t, uint16(3), partitions)
+
+	_, err = topics.CreateTopic(context.Background(), dbaas.TopicCreateOpts{})
+	assert.ErrorIs(t, err, ErrUnexpectedCall)
+}
+
+func TestMockNotCalled(t *testing.T) {
+	catalog := &CatalogService{}
+	catalog.On("Flavors").Return([]dbaas.FlavorResponse{}, nil)
+
+	recorder := &recordingT{}
+	assert.False(t, catalog.AssertExpectations(recorder))
+	assert.Equal(t, []string{"dbaasmock: Flavors[] was not called"}, recorder.errors)
+}
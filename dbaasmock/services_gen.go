@@ -0,0 +1,624 @@
+// Code generated by internal/mockgen. DO NOT EDIT.
+
+package dbaasmock
+
+import (
+	"context"
+
+	"github.com/selectel/dbaas-go"
+)
+
+// DatastoreService is a mock of dbaas.DatastoreService.
+type DatastoreService struct {
+	Mock
+}
+
+// Datastores mocks dbaas.DatastoreService.Datastores.
+func (m *DatastoreService) Datastores(ctx context.Context, params *dbaas.DatastoreQueryParams) ([]dbaas.Datastore, error) {
+	results := m.Called("Datastores", params)
+	r0, _ := results.Get(0).([]dbaas.Datastore)
+	return r0, results.Error(1)
+}
+
+// Datastore mocks dbaas.DatastoreService.Datastore.
+func (m *DatastoreService) Datastore(ctx context.Context, datastoreID string) (dbaas.Datastore, error) {
+	results := m.Called("Datastore", datastoreID)
+	r0, _ := results.Get(0).(dbaas.Datastore)
+	return r0, results.Error(1)
+}
+
+// CreateDatastore mocks dbaas.DatastoreService.CreateDatastore.
+func (m *DatastoreService) CreateDatastore(ctx context.Context, opts dbaas.DatastoreCreateOpts) (dbaas.Datastore, error) {
+	results := m.Called("CreateDatastore", opts)
+	r0, _ := results.Get(0).(dbaas.Datastore)
+	return r0, results.Error(1)
+}
+
+// UpdateDatastore mocks dbaas.DatastoreService.UpdateDatastore.
+func (m *DatastoreService) UpdateDatastore(ctx context.Context, datastoreID string, opts dbaas.DatastoreUpdateOpts) (dbaas.Datastore, error) {
+	results := m.Called("UpdateDatastore", datastoreID, opts)
+	r0, _ := results.Get(0).(dbaas.Datastore)
+	return r0, results.Error(1)
+}
+
+// UpdateSecurityGroup mocks dbaas.DatastoreService.UpdateSecurityGroup.
+func (m *DatastoreService) UpdateSecurityGroup(ctx context.Context, datastoreID string, opts dbaas.DatastoreSecurityGroupOpts) (dbaas.Datastore, error) {
+	results := m.Called("UpdateSecurityGroup", datastoreID, opts)
+	r0, _ := results.Get(0).(dbaas.Datastore)
+	return r0, results.Error(1)
+}
+
+// DeleteDatastore mocks dbaas.DatastoreService.DeleteDatastore.
+func (m *DatastoreService) DeleteDatastore(ctx context.Context, datastoreID string) error {
+	return m.Called("DeleteDatastore", datastoreID).Error(0)
+}
+
+// ResizeDatastore mocks dbaas.DatastoreService.ResizeDatastore.
+func (m *DatastoreService) ResizeDatastore(ctx context.Context, datastoreID string, opts dbaas.DatastoreResizeOpts) (dbaas.Datastore, error) {
+	results := m.Called("ResizeDatastore", datastoreID, opts)
+	r0, _ := results.Get(0).(dbaas.Datastore)
+	return r0, results.Error(1)
+}
+
+// PoolerDatastore mocks dbaas.DatastoreService.PoolerDatastore.
+func (m *DatastoreService) PoolerDatastore(ctx context.Context, datastoreID string, opts dbaas.DatastorePoolerOpts) (dbaas.Datastore, error) {
+	results := m.Called("PoolerDatastore", datastoreID, opts)
+	r0, _ := results.Get(0).(dbaas.Datastore)
+	return r0, results.Error(1)
+}
+
+// FirewallDatastore mocks dbaas.DatastoreService.FirewallDatastore.
+func (m *DatastoreService) FirewallDatastore(ctx context.Context, datastoreID string, opts dbaas.DatastoreFirewallOpts) (dbaas.Datastore, error) {
+	results := m.Called("FirewallDatastore", datastoreID, opts)
+	r0, _ := results.Get(0).(dbaas.Datastore)
+	return r0, results.Error(1)
+}
+
+// ConfigDatastore mocks dbaas.DatastoreService.ConfigDatastore.
+func (m *DatastoreService) ConfigDatastore(ctx context.Context, datastoreID string, opts dbaas.DatastoreConfigOpts) (dbaas.Datastore, error) {
+	results := m.Called("ConfigDatastore", datastoreID, opts)
+	r0, _ := results.Get(0).(dbaas.Datastore)
+	return r0, results.Error(1)
+}
+
+// PasswordDatastore mocks dbaas.DatastoreService.PasswordDatastore.
+func (m *DatastoreService) PasswordDatastore(ctx context.Context, datastoreID string, opts dbaas.DatastorePasswordOpts) (dbaas.Datastore, error) {
+	results := m.Called("PasswordDatastore", datastoreID, opts)
+	r0, _ := results.Get(0).(dbaas.Datastore)
+	return r0, results.Error(1)
+}
+
+// BackupsDatastore mocks dbaas.DatastoreService.BackupsDatastore.
+func (m *DatastoreService) BackupsDatastore(ctx context.Context, datastoreID string, opts dbaas.DatastoreBackupsOpts) (dbaas.Datastore, error) {
+	results := m.Called("BackupsDatastore", datastoreID, opts)
+	r0, _ := results.Get(0).(dbaas.Datastore)
+	return r0, results.Error(1)
+}
+
+// EnableLogPlatform mocks dbaas.DatastoreService.EnableLogPlatform.
+func (m *DatastoreService) EnableLogPlatform(ctx context.Context, datastoreID string, opts dbaas.LogPlatformOpts) (dbaas.Datastore, error) {
+	results := m.Called("EnableLogPlatform", datastoreID, opts)
+	r0, _ := results.Get(0).(dbaas.Datastore)
+	return r0, results.Error(1)
+}
+
+// DisableLogPlatform mocks dbaas.DatastoreService.DisableLogPlatform.
+func (m *DatastoreService) DisableLogPlatform(ctx context.Context, datastoreID string) error {
+	return m.Called("DisableLogPlatform", datastoreID).Error(0)
+}
+
+// CreateFloatingIP mocks dbaas.DatastoreService.CreateFloatingIP.
+func (m *DatastoreService) CreateFloatingIP(ctx context.Context, opts dbaas.FloatingIPsOpts) error {
+	return m.Called("CreateFloatingIP", opts).Error(0)
+}
+
+// DeleteFloatingIP mocks dbaas.DatastoreService.DeleteFloatingIP.
+func (m *DatastoreService) DeleteFloatingIP(ctx context.Context, opts dbaas.FloatingIPsOpts) error {
+	return m.Called("DeleteFloatingIP", opts).Error(0)
+}
+
+// WaitForDatastoreStatus mocks dbaas.DatastoreService.WaitForDatastoreStatus.
+func (m *DatastoreService) WaitForDatastoreStatus(ctx context.Context, datastoreID string, targets []dbaas.Status, opts *dbaas.WaitOpts) (dbaas.Datastore, error) {
+	results := m.Called("WaitForDatastoreStatus", datastoreID, targets, opts)
+	r0, _ := results.Get(0).(dbaas.Datastore)
+	return r0, results.Error(1)
+}
+
+// WaitForDatastoreDeleted mocks dbaas.DatastoreService.WaitForDatastoreDeleted.
+func (m *DatastoreService) WaitForDatastoreDeleted(ctx context.Context, datastoreID string, opts *dbaas.WaitOpts) error {
+	return m.Called("WaitForDatastoreDeleted", datastoreID, opts).Error(0)
+}
+
+// UserService is a mock of dbaas.UserService.
+type UserService struct {
+	Mock
+}
+
+// Users mocks dbaas.UserService.Users.
+func (m *UserService) Users(ctx context.Context, params ...*dbaas.UserQueryParams) ([]dbaas.User, error) {
+	args := []any{}
+	for _, arg := range params {
+		args = append(args, arg)
+	}
+	results := m.Called("Users", args...)
+	r0, _ := results.Get(0).([]dbaas.User)
+	return r0, results.Error(1)
+}
+
+// User mocks dbaas.UserService.User.
+func (m *UserService) User(ctx context.Context, userID string) (dbaas.User, error) {
+	results := m.Called("User", userID)
+	r0, _ := results.Get(0).(dbaas.User)
+	return r0, results.Error(1)
+}
+
+// CreateUser mocks dbaas.UserService.CreateUser.
+func (m *UserService) CreateUser(ctx context.Context, opts dbaas.UserCreateOpts) (dbaas.User, error) {
+	results := m.Called("CreateUser", opts)
+	r0, _ := results.Get(0).(dbaas.User)
+	return r0, results.Error(1)
+}
+
+// UpdateUser mocks dbaas.UserService.UpdateUser.
+func (m *UserService) UpdateUser(ctx context.Context, userID string, opts dbaas.UserUpdateOpts) (dbaas.User, error) {
+	results := m.Called("UpdateUser", userID, opts)
+	r0, _ := results.Get(0).(dbaas.User)
+	return r0, results.Error(1)
+}
+
+// DeleteUser mocks dbaas.UserService.DeleteUser.
+func (m *UserService) DeleteUser(ctx context.Context, userID string) error {
+	return m.Called("DeleteUser", userID).Error(0)
+}
+
+// CreateUserAndWait mocks dbaas.UserService.CreateUserAndWait.
+func (m *UserService) CreateUserAndWait(ctx context.Context, opts dbaas.UserCreateOpts, waitOpts *dbaas.WaitOpts) (dbaas.User, error) {
+	results := m.Called("CreateUserAndWait", opts, waitOpts)
+	r0, _ := results.Get(0).(dbaas.User)
+	return r0, results.Error(1)
+}
+
+// UpdateUserAndWait mocks dbaas.UserService.UpdateUserAndWait.
+func (m *UserService) UpdateUserAndWait(ctx context.Context, userID string, opts dbaas.UserUpdateOpts, waitOpts *dbaas.WaitOpts) (dbaas.User, error) {
+	results := m.Called("UpdateUserAndWait", userID, opts, waitOpts)
+	r0, _ := results.Get(0).(dbaas.User)
+	return r0, results.Error(1)
+}
+
+// DeleteUserAndWait mocks dbaas.UserService.DeleteUserAndWait.
+func (m *UserService) DeleteUserAndWait(ctx context.Context, userID string, waitOpts *dbaas.WaitOpts) error {
+	return m.Called("DeleteUserAndWait", userID, waitOpts).Error(0)
+}
+
+// DatabaseService is a mock of dbaas.DatabaseService.
+type DatabaseService struct {
+	Mock
+}
+
+// Databases mocks dbaas.DatabaseService.Databases.
+func (m *DatabaseService) Databases(ctx context.Context, params *dbaas.DatabaseQueryParams) ([]dbaas.Database, error) {
+	results := m.Called("Databases", params)
+	r0, _ := results.Get(0).([]dbaas.Database)
+	return r0, results.Error(1)
+}
+
+// Database mocks dbaas.DatabaseService.Database.
+func (m *DatabaseService) Database(ctx context.Context, databaseID string) (dbaas.Database, error) {
+	results := m.Called("Database", databaseID)
+	r0, _ := results.Get(0).(dbaas.Database)
+	return r0, results.Error(1)
+}
+
+// CreateDatabase mocks dbaas.DatabaseService.CreateDatabase.
+func (m *DatabaseService) CreateDatabase(ctx context.Context, opts dbaas.DatabaseCreateOpts) (dbaas.Database, error) {
+	results := m.Called("CreateDatabase", opts)
+	r0, _ := results.Get(0).(dbaas.Database)
+	return r0, results.Error(1)
+}
+
+// UpdateDatabase mocks dbaas.DatabaseService.UpdateDatabase.
+func (m *DatabaseService) UpdateDatabase(ctx context.Context, databaseID string, opts dbaas.DatabaseUpdateOpts) (dbaas.Database, error) {
+	results := m.Called("UpdateDatabase", databaseID, opts)
+	r0, _ := results.Get(0).(dbaas.Database)
+	return r0, results.Error(1)
+}
+
+// DeleteDatabase mocks dbaas.DatabaseService.DeleteDatabase.
+func (m *DatabaseService) DeleteDatabase(ctx context.Context, databaseID string) error {
+	return m.Called("DeleteDatabase", databaseID).Error(0)
+}
+
+// CreateDatabaseAndWait mocks dbaas.DatabaseService.CreateDatabaseAndWait.
+func (m *DatabaseService) CreateDatabaseAndWait(ctx context.Context, opts dbaas.DatabaseCreateOpts, waitOpts *dbaas.WaitOpts) (dbaas.Database, error) {
+	results := m.Called("CreateDatabaseAndWait", opts, waitOpts)
+	r0, _ := results.Get(0).(dbaas.Database)
+	return r0, results.Error(1)
+}
+
+// UpdateDatabaseAndWait mocks dbaas.DatabaseService.UpdateDatabaseAndWait.
+func (m *DatabaseService) UpdateDatabaseAndWait(ctx context.Context, databaseID string, opts dbaas.DatabaseUpdateOpts, waitOpts *dbaas.WaitOpts) (dbaas.Database, error) {
+	results := m.Called("UpdateDatabaseAndWait", databaseID, opts, waitOpts)
+	r0, _ := results.Get(0).(dbaas.Database)
+	return r0, results.Error(1)
+}
+
+// DeleteDatabaseAndWait mocks dbaas.DatabaseService.DeleteDatabaseAndWait.
+func (m *DatabaseService) DeleteDatabaseAndWait(ctx context.Context, databaseID string, waitOpts *dbaas.WaitOpts) error {
+	return m.Called("DeleteDatabaseAndWait", databaseID, waitOpts).Error(0)
+}
+
+// GrantService is a mock of dbaas.GrantService.
+type GrantService struct {
+	Mock
+}
+
+// Grants mocks dbaas.GrantService.Grants.
+func (m *GrantService) Grants(ctx context.Context, params ...*dbaas.GrantQueryParams) ([]dbaas.Grant, error) {
+	args := []any{}
+	for _, arg := range params {
+		args = append(args, arg)
+	}
+	results := m.Called("Grants", args...)
+	r0, _ := results.Get(0).([]dbaas.Grant)
+	return r0, results.Error(1)
+}
+
+// Grant mocks dbaas.GrantService.Grant.
+func (m *GrantService) Grant(ctx context.Context, grantID string) (dbaas.Grant, error) {
+	results := m.Called("Grant", grantID)
+	r0, _ := results.Get(0).(dbaas.Grant)
+	return r0, results.Error(1)
+}
+
+// CreateGrant mocks dbaas.GrantService.CreateGrant.
+func (m *GrantService) CreateGrant(ctx context.Context, opts dbaas.GrantCreateOpts) (dbaas.Grant, error) {
+	results := m.Called("CreateGrant", opts)
+	r0, _ := results.Get(0).(dbaas.Grant)
+	return r0, results.Error(1)
+}
+
+// DeleteGrant mocks dbaas.GrantService.DeleteGrant.
+func (m *GrantService) DeleteGrant(ctx context.Context, grantID string) error {
+	return m.Called("DeleteGrant", grantID).Error(0)
+}
+
+// CreateGrantAndWait mocks dbaas.GrantService.CreateGrantAndWait.
+func (m *GrantService) CreateGrantAndWait(ctx context.Context, opts dbaas.GrantCreateOpts, waitOpts *dbaas.WaitOpts) (dbaas.Grant, error) {
+	results := m.Called("CreateGrantAndWait", opts, waitOpts)
+	r0, _ := results.Get(0).(dbaas.Grant)
+	return r0, results.Error(1)
+}
+
+// DeleteGrantAndWait mocks dbaas.GrantService.DeleteGrantAndWait.
+func (m *GrantService) DeleteGrantAndWait(ctx context.Context, grantID string, waitOpts *dbaas.WaitOpts) error {
+	return m.Called("DeleteGrantAndWait", grantID, waitOpts).Error(0)
+}
+
+// ACLService is a mock of dbaas.ACLService.
+type ACLService struct {
+	Mock
+}
+
+// ACLs mocks dbaas.ACLService.ACLs.
+func (m *ACLService) ACLs(ctx context.Context, params *dbaas.ACLQueryParams) ([]dbaas.ACL, error) {
+	results := m.Called("ACLs", params)
+	r0, _ := results.Get(0).([]dbaas.ACL)
+	return r0, results.Error(1)
+}
+
+// ACL mocks dbaas.ACLService.ACL.
+func (m *ACLService) ACL(ctx context.Context, aclID string) (dbaas.ACL, error) {
+	results := m.Called("ACL", aclID)
+	r0, _ := results.Get(0).(dbaas.ACL)
+	return r0, results.Error(1)
+}
+
+// CreateACL mocks dbaas.ACLService.CreateACL.
+func (m *ACLService) CreateACL(ctx context.Context, opts dbaas.ACLCreateOpts) (dbaas.ACL, error) {
+	results := m.Called("CreateACL", opts)
+	r0, _ := results.Get(0).(dbaas.ACL)
+	return r0, results.Error(1)
+}
+
+// UpdateACL mocks dbaas.ACLService.UpdateACL.
+func (m *ACLService) UpdateACL(ctx context.Context, aclID string, opts dbaas.ACLUpdateOpts) (dbaas.ACL, error) {
+	results := m.Called("UpdateACL", aclID, opts)
+	r0, _ := results.Get(0).(dbaas.ACL)
+	return r0, results.Error(1)
+}
+
+// DeleteACL mocks dbaas.ACLService.DeleteACL.
+func (m *ACLService) DeleteACL(ctx context.Context, aclID string) error {
+	return m.Called("DeleteACL", aclID).Error(0)
+}
+
+// CreateACLAndWait mocks dbaas.ACLService.CreateACLAndWait.
+func (m *ACLService) CreateACLAndWait(ctx context.Context, opts dbaas.ACLCreateOpts, waitOpts *dbaas.WaitOpts) (dbaas.ACL, error) {
+	results := m.Called("CreateACLAndWait", opts, waitOpts)
+	r0, _ := results.Get(0).(dbaas.ACL)
+	return r0, results.Error(1)
+}
+
+// UpdateACLAndWait mocks dbaas.ACLService.UpdateACLAndWait.
+func (m *ACLService) UpdateACLAndWait(ctx context.Context, aclID string, opts dbaas.ACLUpdateOpts, waitOpts *dbaas.WaitOpts) (dbaas.ACL, error) {
+	results := m.Called("UpdateACLAndWait", aclID, opts, waitOpts)
+	r0, _ := results.Get(0).(dbaas.ACL)
+	return r0, results.Error(1)
+}
+
+// DeleteACLAndWait mocks dbaas.ACLService.DeleteACLAndWait.
+func (m *ACLService) DeleteACLAndWait(ctx context.Context, aclID string, waitOpts *dbaas.WaitOpts) error {
+	return m.Called("DeleteACLAndWait", aclID, waitOpts).Error(0)
+}
+
+// TopicService is a mock of dbaas.TopicService.
+type TopicService struct {
+	Mock
+}
+
+// Topics mocks dbaas.TopicService.Topics.
+func (m *TopicService) Topics(ctx context.Context, params *dbaas.TopicQueryParams) ([]dbaas.Topic, error) {
+	results := m.Called("Topics", params)
+	r0, _ := results.Get(0).([]dbaas.Topic)
+	return r0, results.Error(1)
+}
+
+// Topic mocks dbaas.TopicService.Topic.
+func (m *TopicService) Topic(ctx context.Context, topicID string) (dbaas.Topic, error) {
+	results := m.Called("Topic", topicID)
+	r0, _ := results.Get(0).(dbaas.Topic)
+	return r0, results.Error(1)
+}
+
+// CreateTopic mocks dbaas.TopicService.CreateTopic.
+func (m *TopicService) CreateTopic(ctx context.Context, opts dbaas.TopicCreateOpts) (dbaas.Topic, error) {
+	results := m.Called("CreateTopic", opts)
+	r0, _ := results.Get(0).(dbaas.Topic)
+	return r0, results.Error(1)
+}
+
+// UpdateTopic mocks dbaas.TopicService.UpdateTopic.
+func (m *TopicService) UpdateTopic(ctx context.Context, topicID string, opts dbaas.TopicUpdateOpts) (dbaas.Topic, error) {
+	results := m.Called("UpdateTopic", topicID, opts)
+	r0, _ := results.Get(0).(dbaas.Topic)
+	return r0, results.Error(1)
+}
+
+// DeleteTopic mocks dbaas.TopicService.DeleteTopic.
+func (m *TopicService) DeleteTopic(ctx context.Context, topicID string) error {
+	return m.Called("DeleteTopic", topicID).Error(0)
+}
+
+// CreateTopicAndWait mocks dbaas.TopicService.CreateTopicAndWait.
+func (m *TopicService) CreateTopicAndWait(ctx context.Context, opts dbaas.TopicCreateOpts, waitOpts *dbaas.WaitOpts) (dbaas.Topic, error) {
+	results := m.Called("CreateTopicAndWait", opts, waitOpts)
+	r0, _ := results.Get(0).(dbaas.Topic)
+	return r0, results.Error(1)
+}
+
+// UpdateTopicAndWait mocks dbaas.TopicService.UpdateTopicAndWait.
+func (m *TopicService) UpdateTopicAndWait(ctx context.Context, topicID string, opts dbaas.TopicUpdateOpts, waitOpts *dbaas.WaitOpts) (dbaas.Topic, error) {
+	results := m.Called("UpdateTopicAndWait", topicID, opts, waitOpts)
+	r0, _ := results.Get(0).(dbaas.Topic)
+	return r0, results.Error(1)
+}
+
+// DeleteTopicAndWait mocks dbaas.TopicService.DeleteTopicAndWait.
+func (m *TopicService) DeleteTopicAndWait(ctx context.Context, topicID string, waitOpts *dbaas.WaitOpts) error {
+	return m.Called("DeleteTopicAndWait", topicID, waitOpts).Error(0)
+}
+
+// ExtensionService is a mock of dbaas.ExtensionService.
+type ExtensionService struct {
+	Mock
+}
+
+// Extensions mocks dbaas.ExtensionService.Extensions.
+func (m *ExtensionService) Extensions(ctx context.Context, params *dbaas.ExtensionQueryParams) ([]dbaas.Extension, error) {
+	results := m.Called("Extensions", params)
+	r0, _ := results.Get(0).([]dbaas.Extension)
+	return r0, results.Error(1)
+}
+
+// Extension mocks dbaas.ExtensionService.Extension.
+func (m *ExtensionService) Extension(ctx context.Context, extensionID string) (dbaas.Extension, error) {
+	results := m.Called("Extension", extensionID)
+	r0, _ := results.Get(0).(dbaas.Extension)
+	return r0, results.Error(1)
+}
+
+// CreateExtension mocks dbaas.ExtensionService.CreateExtension.
+func (m *ExtensionService) CreateExtension(ctx context.Context, opts dbaas.ExtensionCreateOpts) (dbaas.Extension, error) {
+	results := m.Called("CreateExtension", opts)
+	r0, _ := results.Get(0).(dbaas.Extension)
+	return r0, results.Error(1)
+}
+
+// DeleteExtension mocks dbaas.ExtensionService.DeleteExtension.
+func (m *ExtensionService) DeleteExtension(ctx context.Context, extensionID string) error {
+	return m.Called("DeleteExtension", extensionID).Error(0)
+}
+
+// CreateExtensionAndWait mocks dbaas.ExtensionService.CreateExtensionAndWait.
+func (m *ExtensionService) CreateExtensionAndWait(ctx context.Context, opts dbaas.ExtensionCreateOpts, waitOpts *dbaas.WaitOpts) (dbaas.Extension, error) {
+	results := m.Called("CreateExtensionAndWait", opts, waitOpts)
+	r0, _ := results.Get(0).(dbaas.Extension)
+	return r0, results.Error(1)
+}
+
+// DeleteExtensionAndWait mocks dbaas.ExtensionService.DeleteExtensionAndWait.
+func (m *ExtensionService) DeleteExtensionAndWait(ctx context.Context, extensionID string, waitOpts *dbaas.WaitOpts) error {
+	return m.Called("DeleteExtensionAndWait", extensionID, waitOpts).Error(0)
+}
+
+// ReplicationSlotService is a mock of dbaas.ReplicationSlotService.
+type ReplicationSlotService struct {
+	Mock
+}
+
+// LogicalReplicationSlots mocks dbaas.ReplicationSlotService.LogicalReplicationSlots.
+func (m *ReplicationSlotService) LogicalReplicationSlots(ctx context.Context, params *dbaas.LogicalReplicationSlotQueryParams) ([]dbaas.LogicalReplicationSlot, error) {
+	results := m.Called("LogicalReplicationSlots", params)
+	r0, _ := results.Get(0).([]dbaas.LogicalReplicationSlot)
+	return r0, results.Error(1)
+}
+
+// LogicalReplicationSlot mocks dbaas.ReplicationSlotService.LogicalReplicationSlot.
+func (m *ReplicationSlotService) LogicalReplicationSlot(ctx context.Context, slotID string) (dbaas.LogicalReplicationSlot, error) {
+	results := m.Called("LogicalReplicationSlot", slotID)
+	r0, _ := results.Get(0).(dbaas.LogicalReplicationSlot)
+	return r0, results.Error(1)
+}
+
+// CreateLogicalReplicationSlot mocks dbaas.ReplicationSlotService.CreateLogicalReplicationSlot.
+func (m *ReplicationSlotService) CreateLogicalReplicationSlot(ctx context.Context, opts dbaas.LogicalReplicationSlotCreateOpts) (dbaas.LogicalReplicationSlot, error) {
+	results := m.Called("CreateLogicalReplicationSlot", opts)
+	r0, _ := results.Get(0).(dbaas.LogicalReplicationSlot)
+	return r0, results.Error(1)
+}
+
+// DeleteLogicalReplicationSlot mocks dbaas.ReplicationSlotService.DeleteLogicalReplicationSlot.
+func (m *ReplicationSlotService) DeleteLogicalReplicationSlot(ctx context.Context, slotID string) error {
+	return m.Called("DeleteLogicalReplicationSlot", slotID).Error(0)
+}
+
+// CreateLogicalReplicationSlotAndWait mocks dbaas.ReplicationSlotService.CreateLogicalReplicationSlotAndWait.
+func (m *ReplicationSlotService) CreateLogicalReplicationSlotAndWait(ctx context.Context, opts dbaas.LogicalReplicationSlotCreateOpts, waitOpts *dbaas.WaitOpts) (dbaas.LogicalReplicationSlot, error) {
+	results := m.Called("CreateLogicalReplicationSlotAndWait", opts, waitOpts)
+	r0, _ := results.Get(0).(dbaas.LogicalReplicationSlot)
+	return r0, results.Error(1)
+}
+
+// DeleteLogicalReplicationSlotAndWait mocks dbaas.ReplicationSlotService.DeleteLogicalReplicationSlotAndWait.
+func (m *ReplicationSlotService) DeleteLogicalReplicationSlotAndWait(ctx context.Context, slotID string, waitOpts *dbaas.WaitOpts) error {
+	return m.Called("DeleteLogicalReplicationSlotAndWait", slotID, waitOpts).Error(0)
+}
+
+// MetricsTokenService is a mock of dbaas.MetricsTokenService.
+type MetricsTokenService struct {
+	Mock
+}
+
+// PrometheusMetricTokens mocks dbaas.MetricsTokenService.PrometheusMetricTokens.
+func (m *MetricsTokenService) PrometheusMetricTokens(ctx context.Context, params ...*dbaas.PrometheusMetricTokenQueryParams) ([]dbaas.PrometheusMetricToken, error) {
+	args := []any{}
+	for _, arg := range params {
+		args = append(args, arg)
+	}
+	results := m.Called("PrometheusMetricTokens", args...)
+	r0, _ := results.Get(0).([]dbaas.PrometheusMetricToken)
+	return r0, results.Error(1)
+}
+
+// PrometheusMetricToken mocks dbaas.MetricsTokenService.PrometheusMetricToken.
+func (m *MetricsTokenService) PrometheusMetricToken(ctx context.Context, prometheusMetricTokenID string) (dbaas.PrometheusMetricToken, error) {
+	results := m.Called("PrometheusMetricToken", prometheusMetricTokenID)
+	r0, _ := results.Get(0).(dbaas.PrometheusMetricToken)
+	return r0, results.Error(1)
+}
+
+// CreatePrometheusMetricToken mocks dbaas.MetricsTokenService.CreatePrometheusMetricToken.
+func (m *MetricsTokenService) CreatePrometheusMetricToken(ctx context.Context, opts dbaas.PrometheusMetricTokenCreateOpts) (dbaas.PrometheusMetricToken, error) {
+	results := m.Called("CreatePrometheusMetricToken", opts)
+	r0, _ := results.Get(0).(dbaas.PrometheusMetricToken)
+	return r0, results.Error(1)
+}
+
+// UpdatePrometheusMetricToken mocks dbaas.MetricsTokenService.UpdatePrometheusMetricToken.
+func (m *MetricsTokenService) UpdatePrometheusMetricToken(ctx context.Context, prometheusMetricTokenID string, opts dbaas.PrometheusMetricTokenUpdateOpts) (dbaas.PrometheusMetricToken, error) {
+	results := m.Called("UpdatePrometheusMetricToken", prometheusMetricTokenID, opts)
+	r0, _ := results.Get(0).(dbaas.PrometheusMetricToken)
+	return r0, results.Error(1)
+}
+
+// DeletePrometheusMetricToken mocks dbaas.MetricsTokenService.DeletePrometheusMetricToken.
+func (m *MetricsTokenService) DeletePrometheusMetricToken(ctx context.Context, prometheusMetricTokenID string) error {
+	return m.Called("DeletePrometheusMetricToken", prometheusMetricTokenID).Error(0)
+}
+
+// CatalogService is a mock of dbaas.CatalogService.
+type CatalogService struct {
+	Mock
+}
+
+// DatastoreTypes mocks dbaas.CatalogService.DatastoreTypes.
+func (m *CatalogService) DatastoreTypes(ctx context.Context) ([]dbaas.DatastoreType, error) {
+	results := m.Called("DatastoreTypes")
+	r0, _ := results.Get(0).([]dbaas.DatastoreType)
+	return r0, results.Error(1)
+}
+
+// DatastoreType mocks dbaas.CatalogService.DatastoreType.
+func (m *CatalogService) DatastoreType(ctx context.Context, datastoreTypeID string) (dbaas.DatastoreType, error) {
+	results := m.Called("DatastoreType", datastoreTypeID)
+	r0, _ := results.Get(0).(dbaas.DatastoreType)
+	return r0, results.Error(1)
+}
+
+// Flavors mocks dbaas.CatalogService.Flavors.
+func (m *CatalogService) Flavors(ctx context.Context, params ...*dbaas.FlavorQueryParams) ([]dbaas.FlavorResponse, error) {
+	args := []any{}
+	for _, arg := range params {
+		args = append(args, arg)
+	}
+	results := m.Called("Flavors", args...)
+	r0, _ := results.Get(0).([]dbaas.FlavorResponse)
+	return r0, results.Error(1)
+}
+
+// Flavor mocks dbaas.CatalogService.Flavor.
+func (m *CatalogService) Flavor(ctx context.Context, flavorID string) (dbaas.FlavorResponse, error) {
+	results := m.Called("Flavor", flavorID)
+	r0, _ := results.Get(0).(dbaas.FlavorResponse)
+	return r0, results.Error(1)
+}
+
+// ConfigurationParameters mocks dbaas.CatalogService.ConfigurationParameters.
+func (m *CatalogService) ConfigurationParameters(ctx context.Context, params ...*dbaas.ConfigurationParameterQueryParams) ([]dbaas.ConfigurationParameter, error) {
+	args := []any{}
+	for _, arg := range params {
+		args = append(args, arg)
+	}
+	results := m.Called("ConfigurationParameters", args...)
+	r0, _ := results.Get(0).([]dbaas.ConfigurationParameter)
+	return r0, results.Error(1)
+}
+
+// ConfigurationParameter mocks dbaas.CatalogService.ConfigurationParameter.
+func (m *CatalogService) ConfigurationParameter(ctx context.Context, configurationParameterID string) (dbaas.ConfigurationParameter, error) {
+	results := m.Called("ConfigurationParameter", configurationParameterID)
+	r0, _ := results.Get(0).(dbaas.ConfigurationParameter)
+	return r0, results.Error(1)
+}
+
+// AvailableExtensions mocks dbaas.CatalogService.AvailableExtensions.
+func (m *CatalogService) AvailableExtensions(ctx context.Context, params ...*dbaas.AvailableExtensionQueryParams) ([]dbaas.AvailableExtension, error) {
+	args := []any{}
+	for _, arg := range params {
+		args = append(args, arg)
+	}
+	results := m.Called("AvailableExtensions", args...)
+	r0, _ := results.Get(0).([]dbaas.AvailableExtension)
+	return r0, results.Error(1)
+}
+
+// AvailableExtension mocks dbaas.CatalogService.AvailableExtension.
+func (m *CatalogService) AvailableExtension(ctx context.Context, availableExtensionID string) (dbaas.AvailableExtension, error) {
+	results := m.Called("AvailableExtension", availableExtensionID)
+	r0, _ := results.Get(0).(dbaas.AvailableExtension)
+	return r0, results.Error(1)
+}
+
+// Compile-time checks that mocks implement the service interfaces.
+var (
+	_ dbaas.DatastoreService       = (*DatastoreService)(nil)
+	_ dbaas.UserService            = (*UserService)(nil)
+	_ dbaas.DatabaseService        = (*DatabaseService)(nil)
+	_ dbaas.GrantService           = (*GrantService)(nil)
+	_ dbaas.ACLService             = (*ACLService)(nil)
+	_ dbaas.TopicService           = (*TopicService)(nil)
+	_ dbaas.ExtensionService       = (*ExtensionService)(nil)
+	_ dbaas.ReplicationSlotService = (*ReplicationSlotService)(nil)
+	_ dbaas.MetricsTokenService    = (*MetricsTokenService)(nil)
+	_ dbaas.CatalogService         = (*CatalogService)(nil)
+)
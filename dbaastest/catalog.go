@@ -0,0 +1,200 @@
+package dbaastest
+
+import (
+	"github.com/google/uuid"
+
+	"github.com/selectel/dbaas-go"
+)
+
+// Engines of the default datastore types.
+const (
+	EnginePostgreSQL = dbaas.EnginePostgreSQL
+	EngineMySQL      = dbaas.EngineMySQL
+	EngineRedis      = dbaas.EngineRedis
+	EngineKafka      = dbaas.EngineKafka
+)
+
+// CatalogID returns a deterministic ID of a default catalog entry.
+// It allows tests to refer to the default datastore types, flavors and extensions without listing them,
+// e.g. CatalogID("datastore-type", "postgresql", "16") or CatalogID("flavor", "2-4096-32").
+func CatalogID(kind string, names ...string) string {
+	name := kind
+	for _, n := range names {
+		name += "/" + n
+	}
+	return uuid.NewSHA1(uuid.NameSpaceURL, []byte("dbaastest:"+name)).String()
+}
+
+// DatastoreTypeID returns an ID of the default datastore type with the given engine and version.
+func DatastoreTypeID(engine, version string) string {
+	return CatalogID("datastore-type", engine, version)
+}
+
+// defaultDatastoreTypes returns datastore types served by default.
+func defaultDatastoreTypes() []dbaas.DatastoreType {
+	versions := []struct {
+		engine  string
+		version string
+	}{
+		{EnginePostgreSQL, "14"},
+		{EnginePostgreSQL, "15"},
+		{EnginePostgreSQL, "16"},
+		{EngineMySQL, "8"},
+		{EngineRedis, "7"},
+		{EngineKafka, "3.5"},
+	}
+
+	datastoreTypes := make([]dbaas.DatastoreType, 0, len(versions))
+	for _, v := range versions {
+		datastoreTypes = append(datastoreTypes, dbaas.DatastoreType{
+			ID:      DatastoreTypeID(v.engine, v.version),
+			Engine:  v.engine,
+			Version: v.version,
+		})
+	}
+	return datastoreTypes
+}
+
+// defaultFlavors returns flavors served by default, they are available for all default datastore types.
+func defaultFlavors() []dbaas.FlavorResponse {
+	datastoreTypeIDs := make([]string, 0)
+	for _, datastoreType := range defaultDatastoreTypes() {
+		datastoreTypeIDs = append(datastoreTypeIDs, datastoreType.ID)
+	}
+
+	sizes := []struct {
+		name   string
+		flSize string
+		vcpus  int
+		ram    int
+		disk   int
+	}{
+		{"1-2048-16", "small", 1, 2048, 16},
+		{"2-4096-32", "standard", 2, 4096, 32},
+		{"4-8192-64", "standard", 4, 8192, 64},
+		{"8-16384-128", "large", 8, 16384, 128},
+	}
+
+	flavors := make([]dbaas.FlavorResponse, 0, len(sizes))
+	for _, size := range sizes {
+		flavors = append(flavors, dbaas.FlavorResponse{
+			ID:               CatalogID("flavor", size.name),
+			Name:             size.name,
+			FlSize:           size.flSize,
+			DatastoreTypeIDs: datastoreTypeIDs,
+			Vcpus:            size.vcpus,
+			RAM:              size.ram,
+			Disk:             size.disk,
+		})
+	}
+	return flavors
+}
+
+// defaultConfigurationParameters returns configuration parameters served by default.
+func defaultConfigurationParameters() []dbaas.ConfigurationParameter {
+	var parameters []dbaas.ConfigurationParameter
+	for _, datastoreType := range defaultDatastoreTypes() {
+		for _, parameter := range engineConfigurationParameters(datastoreType.Engine) {
+			parameter.ID = CatalogID("configuration-parameter",
+				datastoreType.Engine, datastoreType.Version, parameter.Name)
+			parameter.DatastoreTypeID = datastoreType.ID
+			parameters = append(parameters, parameter)
+		}
+	}
+	return parameters
+}
+
+// engineConfigurationParameters returns a set of configuration parameters for the engine.
+func engineConfigurationParameters(engine string) []dbaas.ConfigurationParameter {
+	switch engine {
+	case EnginePostgreSQL:
+		return []dbaas.ConfigurationParameter{
+			{
+				Name: "work_mem", Type: "int", Unit: "kB", Min: 64, Max: 2147483647, DefaultValue: 4096,
+				IsChangeable: true,
+			},
+			{
+				Name: "shared_buffers", Type: "int", Unit: "8kB", Min: 16, Max: 1073741823, DefaultValue: 16384,
+				IsChangeable: true, IsRestartRequired: true,
+			},
+			{
+				Name: "max_connections", Type: "int", Min: 1, Max: 262143, DefaultValue: 100,
+				IsChangeable: true, IsRestartRequired: true,
+			},
+			{
+				Name: "session_replication_role", Type: "str", DefaultValue: "origin",
+				Choices: []any{"origin", "replica", "local"}, IsChangeable: true,
+			},
+			{Name: "jit", Type: "boolean", DefaultValue: true, IsChangeable: true},
+			{Name: "random_page_cost", Type: "float", Min: 0, Max: 1.79769e+308, DefaultValue: 4, IsChangeable: true},
+			{Name: "data_directory", Type: "str", DefaultValue: "/var/lib/postgresql/data"},
+		}
+	case EngineMySQL:
+		return []dbaas.ConfigurationParameter{
+			{
+				Name: "innodb_buffer_pool_size", Type: "int", Unit: "B", Min: 5242880, Max: 9223372036854775807,
+				DefaultValue: 134217728, IsChangeable: true, IsRestartRequired: true,
+			},
+			{Name: "max_connections", Type: "int", Min: 1, Max: 100000, DefaultValue: 151, IsChangeable: true},
+			{
+				Name: "concurrent_insert", Type: "str", DefaultValue: "AUTO",
+				Choices: []any{"NEVER", "AUTO", "ALWAYS", "0", "1", "2"}, InvalidValues: []any{"0"}, IsChangeable: true,
+			},
+		}
+	case EngineRedis:
+		return []dbaas.ConfigurationParameter{
+			{
+				Name: "maxmemory-policy", Type: "str", DefaultValue: "noeviction", IsChangeable: true,
+				Choices: []any{"noeviction", "allkeys-lru", "allkeys-lfu", "volatile-lru", "volatile-lfu"},
+			},
+			{Name: "timeout", Type: "int", Unit: "s", Min: 0, Max: 2147483647, DefaultValue: 0, IsChangeable: true},
+		}
+	case EngineKafka:
+		return []dbaas.ConfigurationParameter{
+			{
+				Name: "log.retention.hours", Type: "int", Unit: "h", Min: 1, Max: 2147483647, DefaultValue: 168,
+				IsChangeable: true,
+			},
+		}
+	default:
+		return nil
+	}
+}
+
+// defaultAvailableExtensions returns PostgreSQL extensions served by default.
+func defaultAvailableExtensions() []dbaas.AvailableExtension {
+	var datastoreTypeIDs []string
+	for _, datastoreType := range defaultDatastoreTypes() {
+		if datastoreType.Engine == EnginePostgreSQL {
+			datastoreTypeIDs = append(datastoreTypeIDs, datastoreType.ID)
+		}
+	}
+
+	postgis := CatalogID("available-extension", "postgis")
+	return []dbaas.AvailableExtension{
+		{
+			ID:               CatalogID("available-extension", "pg_stat_statements"),
+			Name:             "pg_stat_statements",
+			DatastoreTypeIDs: datastoreTypeIDs,
+			DependencyIDs:    []string{},
+		},
+		{
+			ID:               CatalogID("available-extension", "hstore"),
+			Name:             "hstore",
+			DatastoreTypeIDs: datastoreTypeIDs,
+			DependencyIDs:    []string{},
+		},
+		{
+			ID:               postgis,
+			Name:             "postgis",
+			DatastoreTypeIDs: datastoreTypeIDs,
+			DependencyIDs:    []string{},
+		},
+		{
+			ID:               CatalogID("available-extension", "postgis_topology"),
+			Name:             "postgis_topology",
+			DatastoreTypeIDs: datastoreTypeIDs,
+			DependencyIDs:    []string{postgis},
+		},
+	}
+}
@@ -0,0 +1,641 @@
+package dbaastest
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/google/uuid"
+
+	"github.com/selectel/dbaas-go"
+)
+
+const (
+	// defaultBackupRetentionDays is a backup retention period of a new datastore.
+	defaultBackupRetentionDays = 7
+
+	// defaultDiskType is a disk type of a new datastore flavor.
+	defaultDiskType = dbaas.DiskLocal
+
+	// availabilityZone is an availability zone of the datastore instances.
+	availabilityZone = "ru-1a"
+
+	// connectionDomain is a domain of the datastore connection hosts.
+	connectionDomain = "c.dbaas.selcloud.org"
+)
+
+// Instance roles.
+const (
+	roleMaster  = "MASTER"
+	roleReplica = "REPLICA"
+)
+
+// handleDatastores serves the /datastores endpoints.
+func (s *Server) handleDatastores(w http.ResponseWriter, r *http.Request, path []string) {
+	if len(path) == 0 || path[0] == "" {
+		switch r.Method {
+		case http.MethodGet:
+			writeJSON(w, http.StatusOK, "datastores", filter(s.datastores.list(), r))
+		case http.MethodPost:
+			s.createDatastore(w, r)
+		default:
+			writeMethodNotAllowed(w, r)
+		}
+		return
+	}
+
+	datastore, ok := s.datastores.get(path[0])
+	if !ok {
+		writeNotFound(w, "datastore", path[0])
+		return
+	}
+
+	if len(path) == 1 {
+		switch r.Method {
+		case http.MethodGet:
+			writeJSON(w, http.StatusOK, "datastore", datastore)
+		case http.MethodPut:
+			s.updateDatastore(w, r, datastore)
+		case http.MethodDelete:
+			s.deleteDatastore(w, datastore)
+		default:
+			writeMethodNotAllowed(w, r)
+		}
+		return
+	}
+
+	s.handleDatastoreAction(w, r, datastore, path[1])
+}
+
+// handleDatastoreAction serves the /datastores/{id}/{action} endpoints.
+func (s *Server) handleDatastoreAction(
+	w http.ResponseWriter,
+	r *http.Request,
+	datastore *dbaas.Datastore,
+	action string,
+) {
+	type route struct {
+		handler func(w http.ResponseWriter, r *http.Request, datastore *dbaas.Datastore)
+		method  string
+	}
+	routes := map[string][]route{
+		"resize":          {{s.resizeDatastore, http.MethodPost}},
+		"pooler":          {{s.poolerDatastore, http.MethodPut}},
+		"firewall":        {{s.firewallDatastore, http.MethodPut}},
+		"config":          {{s.configDatastore, http.MethodPut}},
+		"password":        {{s.passwordDatastore, http.MethodPut}},
+		"backups":         {{s.backupsDatastore, http.MethodPut}},
+		"security-groups": {{s.securityGroupsDatastore, http.MethodPut}},
+		dbaas.LogPlatformPostfix: {
+			{s.enableLogPlatform, http.MethodPut},
+			{s.disableLogPlatform, http.MethodDelete},
+		},
+	}
+
+	actionRoutes, ok := routes[action]
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("path %s not found", r.URL.Path))
+		return
+	}
+	for _, route := range actionRoutes {
+		if route.method == r.Method {
+			if !requireActive(w, "datastore", datastore.ID, datastore.Status) {
+				return
+			}
+			route.handler(w, r, datastore)
+			return
+		}
+	}
+	writeMethodNotAllowed(w, r)
+}
+
+// createDatastore handles POST /datastores.
+func (s *Server) createDatastore(w http.ResponseWriter, r *http.Request) {
+	var opts dbaas.DatastoreCreateOpts
+	if err := decodeBody(r, "datastore", &opts); err != nil {
+		writeValidationError(w, "%s", err)
+		return
+	}
+
+	datastoreType, ok := s.datastoreType(opts.TypeID)
+	switch {
+	case opts.Name == "":
+		writeValidationError(w, "{'datastore.name': \"'' is too short\"}")
+		return
+	case !ok:
+		writeValidationError(w, "{'datastore.type_id': \"datastore type %s not found\"}", opts.TypeID)
+		return
+	case opts.SubnetID == "":
+		writeValidationError(w, "{'datastore.subnet_id': \"'' is not a 'UUID'\"}")
+		return
+	case opts.NodeCount < 1:
+		writeValidationError(w, "{'datastore.node_count': '%d is less than the minimum of 1'}", opts.NodeCount)
+		return
+	case opts.RedisPassword != "" && datastoreType.Engine != EngineRedis:
+		writeValidationError(w, "redis_password is allowed only for redis datastores")
+		return
+	}
+
+	flavor, ok := s.resolveFlavor(w, opts.FlavorID, opts.Flavor, opts.TypeID)
+	if !ok {
+		return
+	}
+	if opts.Disk != nil && opts.Disk.Type != "" {
+		flavor.DiskType = dbaas.DiskType(opts.Disk.Type)
+	}
+	if opts.Restore != nil {
+		source, ok := s.datastores.get(opts.Restore.DatastoreID)
+		if !ok {
+			writeValidationError(w, "restore source datastore %s not found", opts.Restore.DatastoreID)
+			return
+		}
+		if !source.AllowRestore || source.TypeID != opts.TypeID {
+			writeValidationError(w, "datastore %s can't be restored", source.ID)
+			return
+		}
+	}
+	config := opts.Config
+	if config == nil {
+		config = make(map[string]any)
+	}
+	if message := s.validateConfig(opts.TypeID, config); message != "" {
+		writeValidationError(w, "%s", message)
+		return
+	}
+
+	datastore := &dbaas.Datastore{
+		ID:                  uuid.NewString(),
+		CreatedAt:           s.timestamp(),
+		UpdatedAt:           s.timestamp(),
+		ProjectID:           s.projectOf(opts.ProjectID),
+		Name:                opts.Name,
+		TypeID:              opts.TypeID,
+		SubnetID:            opts.SubnetID,
+		FlavorID:            opts.FlavorID,
+		Config:              config,
+		Firewall:            []dbaas.Firewall{},
+		SecurityGroups:      opts.SecurityGroups,
+		Flavor:              flavor,
+		NodeCount:           opts.NodeCount,
+		BackupRetentionDays: opts.BackupRetentionDays,
+		AllowRestore:        true,
+		Enabled:             true,
+	}
+	if datastore.SecurityGroups == nil {
+		datastore.SecurityGroups = []string{}
+	}
+	if datastore.BackupRetentionDays == 0 {
+		datastore.BackupRetentionDays = defaultBackupRetentionDays
+	}
+	if opts.Pooler != nil {
+		datastore.Pooler = *opts.Pooler
+	}
+	if opts.LogPlatform != nil {
+		datastore.LogPlatform = *opts.LogPlatform
+	}
+	s.buildInstances(datastore, opts.FloatingIPs)
+	s.datastores.add(datastore.ID, datastore)
+
+	s.scheduleDatastoreStatus(datastore, dbaas.StatusPendingCreate, func() {
+		datastore.CreationFinishedAt = s.timestamp()
+	})
+
+	writeJSON(w, http.StatusOK, "datastore", datastore)
+}
+
+// updateDatastore handles PUT /datastores/{id}.
+func (s *Server) updateDatastore(w http.ResponseWriter, r *http.Request, datastore *dbaas.Datastore) {
+	if !requireActive(w, "datastore", datastore.ID, datastore.Status) {
+		return
+	}
+
+	var opts dbaas.DatastoreUpdateOpts
+	if err := decodeBody(r, "datastore", &opts); err != nil {
+		writeValidationError(w, "%s", err)
+		return
+	}
+	if opts.Name == "" {
+		writeValidationError(w, "{'datastore.name': \"'' is too short\"}")
+		return
+	}
+
+	datastore.Name = opts.Name
+	datastore.UpdatedAt = s.timestamp()
+
+	writeJSON(w, http.StatusOK, "datastore", datastore)
+}
+
+// deleteDatastore handles DELETE /datastores/{id}.
+// Child resources are deleted along with the datastore.
+func (s *Server) deleteDatastore(w http.ResponseWriter, datastore *dbaas.Datastore) {
+	if datastore.Status == dbaas.StatusPendingDelete {
+		writeError(w, http.StatusConflict, fmt.Sprintf("datastore %s is already being deleted", datastore.ID))
+		return
+	}
+	if datastore.IsProtected {
+		writeError(w, http.StatusConflict, fmt.Sprintf("datastore %s is protected", datastore.ID))
+		return
+	}
+
+	s.scheduleDelete(&datastore.Status, func() {
+		s.removeDatastore(datastore.ID)
+	})
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// removeDatastore removes the datastore and all its child resources.
+func (s *Server) removeDatastore(datastoreID string) {
+	s.datastores.remove(datastoreID)
+	removeWhere(s.users, func(u dbaas.User) bool { return u.DatastoreID == datastoreID })
+	removeWhere(s.databases, func(d dbaas.Database) bool { return d.DatastoreID == datastoreID })
+	removeWhere(s.grants, func(g dbaas.Grant) bool { return g.DatastoreID == datastoreID })
+	removeWhere(s.acls, func(a dbaas.ACL) bool { return a.DatastoreID == datastoreID })
+	removeWhere(s.topics, func(t dbaas.Topic) bool { return t.DatastoreID == datastoreID })
+	removeWhere(s.extensions, func(e dbaas.Extension) bool { return e.DatastoreID == datastoreID })
+	removeWhere(s.slots, func(l dbaas.LogicalReplicationSlot) bool { return l.DatastoreID == datastoreID })
+}
+
+// resizeDatastore handles POST /datastores/{id}/resize.
+func (s *Server) resizeDatastore(w http.ResponseWriter, r *http.Request, datastore *dbaas.Datastore) {
+	var opts dbaas.DatastoreResizeOpts
+	if err := decodeBody(r, "resize", &opts); err != nil {
+		writeValidationError(w, "%s", err)
+		return
+	}
+	if opts.Flavor != nil && opts.Flavor.DiskType != "" {
+		writeValidationError(w,
+			"{'resize.flavor': \"Additional properties are not allowed ('disk_type' was unexpected)\"}")
+		return
+	}
+	if opts.NodeCount < 0 {
+		writeValidationError(w, "{'resize.node_count': '%d is less than the minimum of 1'}", opts.NodeCount)
+		return
+	}
+
+	if opts.FlavorID != "" || opts.Flavor != nil {
+		flavor, ok := s.resolveFlavor(w, opts.FlavorID, opts.Flavor, datastore.TypeID)
+		if !ok {
+			return
+		}
+		flavor.DiskType = datastore.Flavor.DiskType
+		datastore.Flavor = flavor
+		datastore.FlavorID = opts.FlavorID
+	}
+	if opts.Disk != nil {
+		datastore.Flavor.Disk = opts.Disk.Size
+	}
+	if opts.NodeCount > 0 && opts.NodeCount != datastore.NodeCount {
+		datastore.NodeCount = opts.NodeCount
+		s.buildInstances(datastore, nil)
+	}
+
+	s.scheduleDatastoreStatus(datastore, dbaas.StatusResizing, nil)
+	writeJSON(w, http.StatusOK, "datastore", datastore)
+}
+
+// poolerDatastore handles PUT /datastores/{id}/pooler.
+func (s *Server) poolerDatastore(w http.ResponseWriter, r *http.Request, datastore *dbaas.Datastore) {
+	if !s.requireEngine(w, datastore, EnginePostgreSQL) {
+		return
+	}
+
+	var opts dbaas.DatastorePoolerOpts
+	if err := decodeBody(r, "pooler", &opts); err != nil {
+		writeValidationError(w, "%s", err)
+		return
+	}
+	switch opts.Mode {
+	case "", "session", "transaction", "statement":
+	default:
+		writeValidationError(w, "{'pooler.mode': \"'%s' is not one of ['session', 'transaction', 'statement']\"}",
+			opts.Mode)
+		return
+	}
+
+	datastore.Pooler = dbaas.Pooler{Mode: opts.Mode, Size: opts.Size}
+	s.scheduleDatastoreStatus(datastore, dbaas.StatusPendingUpdate, nil)
+	writeJSON(w, http.StatusOK, "datastore", datastore)
+}
+
+// firewallDatastore handles PUT /datastores/{id}/firewall.
+func (s *Server) firewallDatastore(w http.ResponseWriter, r *http.Request, datastore *dbaas.Datastore) {
+	var opts dbaas.DatastoreFirewallOpts
+	if err := decodeBody(r, "firewall", &opts); err != nil {
+		writeValidationError(w, "%s", err)
+		return
+	}
+
+	datastore.Firewall = make([]dbaas.Firewall, 0, len(opts.IPs))
+	for _, ip := range opts.IPs {
+		datastore.Firewall = append(datastore.Firewall, dbaas.Firewall{IP: ip})
+	}
+	s.scheduleDatastoreStatus(datastore, dbaas.StatusPendingUpdate, nil)
+	writeJSON(w, http.StatusOK, "datastore", datastore)
+}
+
+// configDatastore handles PUT /datastores/{id}/config.
+// Parameters with null values are reset to their defaults.
+func (s *Server) configDatastore(w http.ResponseWriter, r *http.Request, datastore *dbaas.Datastore) {
+	var opts dbaas.DatastoreConfigOpts
+	if err := decodeBody(r, "", &opts); err != nil {
+		writeValidationError(w, "%s", err)
+		return
+	}
+	if message := s.validateConfig(datastore.TypeID, opts.Config); message != "" {
+		writeValidationError(w, "%s", message)
+		return
+	}
+
+	config := make(map[string]any, len(datastore.Config))
+	for name, value := range datastore.Config {
+		config[name] = value
+	}
+	for name, value := range opts.Config {
+		if value == nil {
+			delete(config, name)
+		} else {
+			config[name] = value
+		}
+	}
+	datastore.Config = config
+	s.scheduleDatastoreStatus(datastore, dbaas.StatusPendingUpdate, nil)
+	writeJSON(w, http.StatusOK, "datastore", datastore)
+}
+
+// passwordDatastore handles PUT /datastores/{id}/password.
+func (s *Server) passwordDatastore(w http.ResponseWriter, r *http.Request, datastore *dbaas.Datastore) {
+	if !s.requireEngine(w, datastore, EngineRedis) {
+		return
+	}
+
+	var opts dbaas.DatastorePasswordOpts
+	if err := decodeBody(r, "password", &opts); err != nil {
+		writeValidationError(w, "%s", err)
+		return
+	}
+	if opts.RedisPassword == "" {
+		writeValidationError(w, "{'password.redis_password': \"'' is too short\"}")
+		return
+	}
+
+	s.scheduleDatastoreStatus(datastore, dbaas.StatusPendingUpdate, nil)
+	writeJSON(w, http.StatusOK, "datastore", datastore)
+}
+
+// backupsDatastore handles PUT /datastores/{id}/backups.
+func (s *Server) backupsDatastore(w http.ResponseWriter, r *http.Request, datastore *dbaas.Datastore) {
+	var opts dbaas.DatastoreBackupsOpts
+	if err := decodeBody(r, "backups", &opts); err != nil {
+		writeValidationError(w, "%s", err)
+		return
+	}
+	if opts.BackupRetentionDays < 1 {
+		writeValidationError(w, "{'backups.backup_retention_days': '%d is less than the minimum of 1'}",
+			opts.BackupRetentionDays)
+		return
+	}
+
+	datastore.BackupRetentionDays = opts.BackupRetentionDays
+	datastore.UpdatedAt = s.timestamp()
+	writeJSON(w, http.StatusOK, "datastore", datastore)
+}
+
+// securityGroupsDatastore handles PUT /datastores/{id}/security-groups.
+func (s *Server) securityGroupsDatastore(w http.ResponseWriter, r *http.Request, datastore *dbaas.Datastore) {
+	var opts dbaas.DatastoreSecurityGroupOpts
+	if err := decodeBody(r, "", &opts); err != nil {
+		writeValidationError(w, "%s", err)
+		return
+	}
+
+	datastore.SecurityGroups = opts.SecurityGroups
+	if datastore.SecurityGroups == nil {
+		datastore.SecurityGroups = []string{}
+	}
+	s.scheduleDatastoreStatus(datastore, dbaas.StatusPendingUpdate, nil)
+	writeJSON(w, http.StatusOK, "datastore", datastore)
+}
+
+// enableLogPlatform handles PUT /datastores/{id}/log-platform.
+func (s *Server) enableLogPlatform(w http.ResponseWriter, r *http.Request, datastore *dbaas.Datastore) {
+	var opts dbaas.LogPlatformOpts
+	if err := decodeBody(r, "", &opts); err != nil {
+		writeValidationError(w, "%s", err)
+		return
+	}
+	if opts.LogPlatform.LogGroup == "" {
+		writeValidationError(w, "{'log_platform.log_group': \"'' is too short\"}")
+		return
+	}
+
+	datastore.LogPlatform = opts.LogPlatform
+	s.scheduleDatastoreStatus(datastore, dbaas.StatusPendingUpdate, nil)
+	writeJSON(w, http.StatusOK, "datastore", datastore)
+}
+
+// disableLogPlatform handles DELETE /datastores/{id}/log-platform.
+func (s *Server) disableLogPlatform(w http.ResponseWriter, _ *http.Request, datastore *dbaas.Datastore) {
+	datastore.LogPlatform = dbaas.DatastoreLogGroup{}
+	s.scheduleDatastoreStatus(datastore, dbaas.StatusPendingUpdate, nil)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handleFloatingIPs serves the /floating-ips endpoint.
+func (s *Server) handleFloatingIPs(w http.ResponseWriter, r *http.Request, path []string) {
+	if len(path) > 0 && path[0] != "" {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("path %s not found", r.URL.Path))
+		return
+	}
+	if r.Method != http.MethodPost && r.Method != http.MethodDelete {
+		writeMethodNotAllowed(w, r)
+		return
+	}
+
+	var opts dbaas.FloatingIPsOpts
+	if err := decodeBody(r, "floating_ip", &opts); err != nil {
+		writeValidationError(w, "%s", err)
+		return
+	}
+
+	for _, datastore := range s.datastores.items {
+		for i := range datastore.Instances {
+			instance := &datastore.Instances[i]
+			if instance.ID != opts.InstanceID {
+				continue
+			}
+			if !requireActive(w, "datastore", datastore.ID, datastore.Status) {
+				return
+			}
+			if r.Method == http.MethodPost {
+				instance.FloatingIP = floatingIP(i)
+			} else {
+				instance.FloatingIP = ""
+			}
+			s.scheduleDatastoreStatus(datastore, dbaas.StatusPendingUpdate, nil)
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+	}
+	writeNotFound(w, "instance", opts.InstanceID)
+}
+
+// scheduleDatastoreStatus puts the datastore and its instances into the pending status
+// and schedules their activation. Injected faults may replace ACTIVE with another result status.
+func (s *Server) scheduleDatastoreStatus(datastore *dbaas.Datastore, pending dbaas.Status, activate func()) {
+	datastore.UpdatedAt = s.timestamp()
+	datastore.Status = pending
+	for i := range datastore.Instances {
+		datastore.Instances[i].Status = pending
+	}
+
+	result := s.resultStatus
+	s.schedule(func() {
+		if datastore.Status != pending {
+			return
+		}
+		datastore.Status = result
+		for i := range datastore.Instances {
+			datastore.Instances[i].Status = result
+		}
+		if activate != nil && result == dbaas.StatusActive {
+			activate()
+		}
+	})
+}
+
+// buildInstances creates instances and connection hosts of the datastore according to its node count.
+// Existing instances are preserved.
+func (s *Server) buildInstances(datastore *dbaas.Datastore, floatingIPs *dbaas.FloatingIPs) {
+	instances := make([]dbaas.Instances, 0, datastore.NodeCount)
+	connection := map[string]string{}
+	for i := 0; i < datastore.NodeCount; i++ {
+		var instance dbaas.Instances
+		if i < len(datastore.Instances) {
+			instance = datastore.Instances[i]
+		} else {
+			instance = dbaas.Instances{
+				ID:               uuid.NewString(),
+				IP:               fmt.Sprintf("10.0.0.%d", 10+i),
+				Status:           datastore.Status,
+				AvailabilityZone: availabilityZone,
+			}
+			instance.Hostname = fmt.Sprintf("%s.%s", instance.ID, connectionDomain)
+		}
+
+		host := fmt.Sprintf("replica-%d", i)
+		instance.Role, instance.RoleName = roleReplica, "Replica"
+		if i == 0 {
+			host = "master"
+			instance.Role, instance.RoleName = roleMaster, "Master"
+			connection["MASTER"] = fmt.Sprintf("%s.%s.%s", host, datastore.ID, connectionDomain)
+		}
+		connection[host] = fmt.Sprintf("%s.%s.%s", host, datastore.ID, connectionDomain)
+
+		if floatingIPs != nil && (i == 0 && floatingIPs.Master > 0 || i > 0 && floatingIPs.Replica > 0) {
+			instance.FloatingIP = floatingIP(i)
+		}
+		instances = append(instances, instance)
+	}
+	datastore.Instances = instances
+	datastore.Connection = connection
+}
+
+// floatingIP returns a floating IP address for the instance with the given index.
+func floatingIP(index int) string {
+	return fmt.Sprintf("203.0.113.%d", 10+index)
+}
+
+// resolveFlavor returns a datastore flavor either by the flavor ID or from the inline flavor.
+func (s *Server) resolveFlavor(
+	w http.ResponseWriter,
+	flavorID string,
+	inline *dbaas.Flavor,
+	datastoreTypeID string,
+) (dbaas.Flavor, bool) {
+	switch {
+	case flavorID != "" && inline != nil:
+		writeValidationError(w, "only one of flavor_id and flavor can be specified")
+		return dbaas.Flavor{}, false
+	case inline != nil:
+		flavor := *inline
+		if flavor.DiskType == "" {
+			flavor.DiskType = defaultDiskType
+		}
+		return flavor, true
+	case flavorID == "":
+		writeValidationError(w, "one of flavor_id and flavor is required")
+		return dbaas.Flavor{}, false
+	}
+
+	for _, flavor := range s.flavors {
+		if flavor.ID != flavorID {
+			continue
+		}
+		for _, id := range flavor.DatastoreTypeIDs {
+			if id == datastoreTypeID {
+				return dbaas.Flavor{
+					DiskType: defaultDiskType,
+					Vcpus:    flavor.Vcpus,
+					RAM:      flavor.RAM,
+					Disk:     flavor.Disk,
+				}, true
+			}
+		}
+		writeValidationError(w, "flavor %s is not available for datastore type %s", flavorID, datastoreTypeID)
+		return dbaas.Flavor{}, false
+	}
+	writeValidationError(w, "flavor %s not found", flavorID)
+	return dbaas.Flavor{}, false
+}
+
+// validateConfig checks configuration parameters against the catalog.
+// It returns a description of the first problem or an empty string.
+func (s *Server) validateConfig(datastoreTypeID string, config map[string]any) string {
+	for name := range config {
+		var parameter *dbaas.ConfigurationParameter
+		for i := range s.configurationParameters {
+			p := &s.configurationParameters[i]
+			if p.DatastoreTypeID == datastoreTypeID && p.Name == name {
+				parameter = p
+				break
+			}
+		}
+		if parameter == nil {
+			return fmt.Sprintf("configuration parameter %s not found", name)
+		}
+		if !parameter.IsChangeable {
+			return fmt.Sprintf("configuration parameter %s can't be changed", name)
+		}
+	}
+	return ""
+}
+
+// datastoreType returns a datastore type from the catalog.
+func (s *Server) datastoreType(datastoreTypeID string) (dbaas.DatastoreType, bool) {
+	for _, datastoreType := range s.datastoreTypes {
+		if datastoreType.ID == datastoreTypeID {
+			return datastoreType, true
+		}
+	}
+	return dbaas.DatastoreType{}, false
+}
+
+// requireEngine checks if the datastore engine is one of the given engines.
+func (s *Server) requireEngine(w http.ResponseWriter, datastore *dbaas.Datastore, engines ...string) bool {
+	datastoreType, _ := s.datastoreType(datastore.TypeID)
+	for _, engine := range engines {
+		if datastoreType.Engine == engine {
+			return true
+		}
+	}
+	writeValidationError(w, "operation is not supported for %s datastores", datastoreType.Engine)
+	return false
+}
+
+// projectOf returns the given project ID or the default one.
+func (s *Server) projectOf(projectID string) string {
+	if projectID == "" {
+		return s.projectID
+	}
+	return projectID
+}
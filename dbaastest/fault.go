@@ -0,0 +1,201 @@
+package dbaastest
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/selectel/dbaas-go"
+)
+
+// Fault is a scripted failure of the requests matching the method and the path.
+// For example, the third config update that fails with 503 is described as
+//
+//	dbaastest.Fault{
+//		Method:     http.MethodPut,
+//		Path:       "/datastores/{id}/config",
+//		Skip:       2,
+//		Times:      1,
+//		StatusCode: http.StatusServiceUnavailable,
+//	}
+type Fault struct {
+	// Method is an HTTP method of the matching requests, empty method matches any method.
+	Method string
+
+	// Path is a path pattern relative to the API endpoint, e.g. "/datastores/{id}/config".
+	// Segments in braces match any value, empty path matches any path.
+	Path string
+
+	// ResultStatus is a status the changed resource ends up in instead of ACTIVE.
+	// Use ERROR to fail the operation or the pending status to make the resource stuck in it.
+	ResultStatus dbaas.Status
+
+	// Skip is a number of the matching requests that are served normally before the fault is injected.
+	Skip int
+
+	// Times is a number of the requests the fault is injected into, zero means no limit.
+	Times int
+
+	// Latency delays the response.
+	Latency time.Duration
+
+	// StatusCode makes the server respond with the error without handling the request.
+	StatusCode int
+
+	// RetryAfter is sent in the Retry-After header along with the StatusCode.
+	RetryAfter time.Duration
+
+	// DropConnection makes the server close the connection without a response.
+	DropConnection bool
+
+	// MalformedJSON makes the server respond with a broken body without handling the request.
+	MalformedJSON bool
+}
+
+// faultState tracks the requests matched by the fault.
+type faultState struct {
+	Fault
+	matched int
+}
+
+// WithFaults sets faults injected into the requests.
+func WithFaults(faults ...Fault) Option {
+	return func(s *Server) {
+		for _, fault := range faults {
+			s.faults = append(s.faults, &faultState{Fault: fault})
+		}
+	}
+}
+
+// InjectFault adds a fault injected into the following requests.
+func (s *Server) InjectFault(fault Fault) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.faults = append(s.faults, &faultState{Fault: fault})
+}
+
+// ClearFaults removes all faults.
+func (s *Server) ClearFaults() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.faults = nil
+}
+
+// matchFault returns the fault to inject into the request.
+// Every matching fault counts the request, the first fault that is due is injected.
+func (s *Server) matchFault(r *http.Request, path []string) *Fault {
+	var injected *Fault
+	for _, state := range s.faults {
+		if !state.matches(r.Method, path) {
+			continue
+		}
+		state.matched++
+		due := state.matched > state.Skip && (state.Times == 0 || state.matched <= state.Skip+state.Times)
+		if due && injected == nil {
+			fault := state.Fault
+			injected = &fault
+		}
+	}
+	return injected
+}
+
+// matches checks if the fault applies to the request method and path.
+func (f *Fault) matches(method string, path []string) bool {
+	if f.Method != "" && !strings.EqualFold(f.Method, method) {
+		return false
+	}
+	if f.Path == "" {
+		return true
+	}
+
+	pattern := strings.Split(strings.Trim(f.Path, "/"), "/")
+	if len(pattern) != len(path) {
+		return false
+	}
+	for i, segment := range pattern {
+		if strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}") {
+			continue
+		}
+		if segment != path[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// inject writes the faulty response. It reports false if the request should be handled normally.
+func (f *Fault) inject(w http.ResponseWriter, r *http.Request) bool {
+	if f.Latency > 0 {
+		timer := time.NewTimer(f.Latency)
+		defer timer.Stop()
+		select {
+		case <-r.Context().Done():
+			return true
+		case <-timer.C:
+		}
+	}
+
+	switch {
+	case f.DropConnection:
+		dropConnection(w)
+	case f.StatusCode != 0:
+		if f.RetryAfter > 0 {
+			w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(f.RetryAfter.Seconds()))))
+		}
+		writeError(w, f.StatusCode, fmt.Sprintf("injected fault for %s %s", r.Method, r.URL.Path))
+	case f.MalformedJSON:
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`{"error": {"code": 200, "title": `))
+	default:
+		return false
+	}
+	return true
+}
+
+// dropConnection closes the client connection without writing a response.
+func dropConnection(w http.ResponseWriter) {
+	hijacker, ok := w.(http.Hijacker)
+	if !ok {
+		panic(http.ErrAbortHandler)
+	}
+	conn, _, err := hijacker.Hijack()
+	if err != nil {
+		panic(http.ErrAbortHandler)
+	}
+	_ = conn.Close()
+}
+
+// SetStatus changes the status of the resource with the given ID, e.g. to emulate its failure.
+// It reports whether the resource was found.
+func (s *Server) SetStatus(id string, status dbaas.Status) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if datastore, ok := s.datastores.get(id); ok {
+		datastore.Status = status
+		for i := range datastore.Instances {
+			datastore.Instances[i].Status = status
+		}
+		return true
+	}
+	return setStatus(s.users, id, status, func(u *dbaas.User) *dbaas.Status { return &u.Status }) ||
+		setStatus(s.databases, id, status, func(d *dbaas.Database) *dbaas.Status { return &d.Status }) ||
+		setStatus(s.grants, id, status, func(g *dbaas.Grant) *dbaas.Status { return &g.Status }) ||
+		setStatus(s.acls, id, status, func(a *dbaas.ACL) *dbaas.Status { return &a.Status }) ||
+		setStatus(s.topics, id, status, func(t *dbaas.Topic) *dbaas.Status { return &t.Status }) ||
+		setStatus(s.extensions, id, status, func(e *dbaas.Extension) *dbaas.Status { return &e.Status }) ||
+		setStatus(s.slots, id, status, func(l *dbaas.LogicalReplicationSlot) *dbaas.Status { return &l.Status })
+}
+
+// setStatus changes the status of the resource in the collection.
+func setStatus[T any](c *collection[T], id string, status dbaas.Status, field func(item *T) *dbaas.Status) bool {
+	item, ok := c.get(id)
+	if ok {
+		*field(item) = status
+	}
+	return ok
+}
@@ -0,0 +1,131 @@
+package dbaastest
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/selectel/dbaas-go"
+)
+
+func testRetryPolicy() *dbaas.RetryPolicy {
+	return &dbaas.RetryPolicy{MaxAttempts: 3, MinBackoff: time.Millisecond, MaxBackoff: 10 * time.Millisecond}
+}
+
+func TestFaultNthRequest(t *testing.T) {
+	server := NewServer(WithFaults(Fault{
+		Method:     http.MethodPut,
+		Path:       "/datastores/{id}/config",
+		Skip:       2,
+		Times:      1,
+		StatusCode: http.StatusServiceUnavailable,
+	}))
+	defer server.Close()
+	api := server.Client()
+	ctx := context.Background()
+	datastore := createActiveDatastore(t, api, EnginePostgreSQL, "16")
+
+	configOpts := dbaas.DatastoreConfigOpts{Config: map[string]any{"work_mem": 8192}}
+	for i := 1; i <= 4; i++ {
+		_, err := api.ConfigDatastore(ctx, datastore.ID, configOpts)
+		if i == 3 {
+			assert.ErrorIs(t, err, dbaas.ErrServerError)
+		} else {
+			assert.NoError(t, err, "request %d", i)
+		}
+	}
+}
+
+func TestFaultRateLimited(t *testing.T) {
+	server := NewServer(WithFaults(Fault{
+		Method:     http.MethodGet,
+		Path:       "/flavors",
+		Times:      2,
+		StatusCode: http.StatusTooManyRequests,
+		RetryAfter: time.Second,
+	}))
+	defer server.Close()
+
+	_, err := server.Client().Flavors(context.Background())
+	assert.ErrorIs(t, err, dbaas.ErrRateLimited)
+
+	flavors, err := server.Client(dbaas.WithRetryPolicy(testRetryPolicy())).Flavors(context.Background())
+	require.NoError(t, err)
+	assert.NotEmpty(t, flavors)
+}
+
+func TestFaultDropConnection(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+	server.InjectFault(Fault{Path: "/datastore-types", Times: 1, DropConnection: true})
+
+	datastoreTypes, err := server.Client(dbaas.WithRetryPolicy(testRetryPolicy())).DatastoreTypes(context.Background())
+	require.NoError(t, err)
+	assert.NotEmpty(t, datastoreTypes)
+
+	server.InjectFault(Fault{Path: "/datastore-types", DropConnection: true})
+	_, err = server.Client().DatastoreTypes(context.Background())
+	assert.Error(t, err)
+
+	server.ClearFaults()
+	_, err = server.Client().DatastoreTypes(context.Background())
+	assert.NoError(t, err)
+}
+
+func TestFaultMalformedJSON(t *testing.T) {
+	server := NewServer(WithFaults(Fault{Method: http.MethodGet, Path: "/flavors/{id}", MalformedJSON: true}))
+	defer server.Close()
+
+	_, err := server.Client().Flavor(context.Background(), CatalogID("flavor", "1-2048-16"))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "Unmarshal")
+}
+
+func TestFaultLatency(t *testing.T) {
+	server := NewServer(WithFaults(Fault{Latency: time.Second}))
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	_, err := server.Client().Flavors(ctx)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestFaultResultStatus(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+	api := server.Client()
+	ctx := context.Background()
+	datastore := createActiveDatastore(t, api, EnginePostgreSQL, "16")
+
+	server.InjectFault(Fault{
+		Method:       http.MethodPost,
+		Path:         "/datastores/{id}/resize",
+		ResultStatus: dbaas.StatusError,
+	})
+	_, err := api.ResizeDatastore(ctx, datastore.ID, dbaas.DatastoreResizeOpts{NodeCount: 2})
+	require.NoError(t, err)
+	_, err = api.WaitForDatastoreStatus(ctx, datastore.ID, []dbaas.Status{dbaas.StatusActive},
+		&dbaas.WaitOpts{Interval: testWaitInterval})
+	assert.ErrorIs(t, err, dbaas.ErrWaitFailed)
+
+	require.True(t, server.SetStatus(datastore.ID, dbaas.StatusActive))
+	server.InjectFault(Fault{
+		Method:       http.MethodPut,
+		Path:         "/datastores/{id}/firewall",
+		ResultStatus: dbaas.StatusPendingUpdate,
+	})
+	_, err = api.FirewallDatastore(ctx, datastore.ID, dbaas.DatastoreFirewallOpts{IPs: []string{"127.0.0.1"}})
+	require.NoError(t, err)
+	_, err = api.WaitForDatastoreStatus(ctx, datastore.ID, []dbaas.Status{dbaas.StatusActive},
+		&dbaas.WaitOpts{Interval: testWaitInterval, Timeout: 50 * time.Millisecond})
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	datastore, err = api.Datastore(ctx, datastore.ID)
+	require.NoError(t, err)
+	assert.Equal(t, dbaas.StatusPendingUpdate, datastore.Status)
+}
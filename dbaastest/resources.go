@@ -0,0 +1,698 @@
+package dbaastest
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+
+	"github.com/google/uuid"
+
+	"github.com/selectel/dbaas-go"
+)
+
+// Default database locale.
+const defaultLocale = "C"
+
+// handleUsers serves the /users endpoints.
+func (s *Server) handleUsers(w http.ResponseWriter, r *http.Request, path []string) {
+	handleResource(w, r, path, s.users, "user", "users", resourceHandlers[dbaas.User]{
+		create: s.createUser,
+		update: s.updateUser,
+		delete: s.deleteUser,
+	})
+}
+
+// createUser handles POST /users.
+func (s *Server) createUser(w http.ResponseWriter, r *http.Request) {
+	var opts dbaas.UserCreateOpts
+	if err := decodeBody(r, "user", &opts); err != nil {
+		writeValidationError(w, "%s", err)
+		return
+	}
+	datastore, ok := s.activeDatastore(w, opts.DatastoreID, EnginePostgreSQL, EngineMySQL, EngineKafka)
+	if !ok {
+		return
+	}
+	switch {
+	case opts.Name == "":
+		writeValidationError(w, "{'user.name': \"'' is too short\"}")
+		return
+	case opts.Password == "":
+		writeValidationError(w, "{'user.password': \"'' is too short\"}")
+		return
+	case exists(s.users, func(u dbaas.User) bool { return u.DatastoreID == datastore.ID && u.Name == opts.Name }):
+		writeError(w, http.StatusConflict, fmt.Sprintf("user %s already exists", opts.Name))
+		return
+	}
+
+	user := &dbaas.User{
+		ID:          uuid.NewString(),
+		CreatedAt:   s.timestamp(),
+		UpdatedAt:   s.timestamp(),
+		ProjectID:   datastore.ProjectID,
+		DatastoreID: datastore.ID,
+		Name:        opts.Name,
+	}
+	s.users.add(user.ID, user)
+	s.scheduleStatus(&user.Status, dbaas.StatusPendingCreate)
+
+	writeJSON(w, http.StatusOK, "user", user)
+}
+
+// updateUser handles PUT /users/{id}.
+func (s *Server) updateUser(w http.ResponseWriter, r *http.Request, user *dbaas.User) {
+	var opts dbaas.UserUpdateOpts
+	if err := decodeBody(r, "user", &opts); err != nil {
+		writeValidationError(w, "%s", err)
+		return
+	}
+	if opts.Password == "" {
+		writeValidationError(w, "{'user.password': \"'' is too short\"}")
+		return
+	}
+
+	user.UpdatedAt = s.timestamp()
+	s.scheduleStatus(&user.Status, dbaas.StatusPendingUpdate)
+
+	writeJSON(w, http.StatusOK, "user", user)
+}
+
+// deleteUser handles DELETE /users/{id}.
+// Grants and ACLs of the user are deleted along with it.
+func (s *Server) deleteUser(w http.ResponseWriter, _ *http.Request, user *dbaas.User) {
+	if exists(s.databases, func(d dbaas.Database) bool { return d.OwnerID == user.ID }) {
+		writeError(w, http.StatusConflict, fmt.Sprintf("user %s owns databases", user.ID))
+		return
+	}
+
+	s.scheduleDelete(&user.Status, func() {
+		s.users.remove(user.ID)
+		removeWhere(s.grants, func(g dbaas.Grant) bool { return g.UserID == user.ID })
+		removeWhere(s.acls, func(a dbaas.ACL) bool { return a.UserID == user.ID })
+	})
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handleDatabases serves the /databases endpoints.
+func (s *Server) handleDatabases(w http.ResponseWriter, r *http.Request, path []string) {
+	handleResource(w, r, path, s.databases, "database", "databases", resourceHandlers[dbaas.Database]{
+		create: s.createDatabase,
+		update: s.updateDatabase,
+		delete: s.deleteDatabase,
+	})
+}
+
+// createDatabase handles POST /databases.
+func (s *Server) createDatabase(w http.ResponseWriter, r *http.Request) {
+	var opts dbaas.DatabaseCreateOpts
+	if err := decodeBody(r, "database", &opts); err != nil {
+		writeValidationError(w, "%s", err)
+		return
+	}
+	datastore, ok := s.activeDatastore(w, opts.DatastoreID, EnginePostgreSQL, EngineMySQL)
+	if !ok {
+		return
+	}
+	switch {
+	case opts.Name == "":
+		writeValidationError(w, "{'database.name': \"'' is too short\"}")
+		return
+	case exists(s.databases, func(d dbaas.Database) bool {
+		return d.DatastoreID == datastore.ID && d.Name == opts.Name
+	}):
+		writeError(w, http.StatusConflict, fmt.Sprintf("database %s already exists", opts.Name))
+		return
+	}
+	if opts.OwnerID != "" && !s.requireUser(w, opts.OwnerID, datastore.ID) {
+		return
+	}
+
+	database := &dbaas.Database{
+		ID:          uuid.NewString(),
+		CreatedAt:   s.timestamp(),
+		UpdatedAt:   s.timestamp(),
+		ProjectID:   datastore.ProjectID,
+		Name:        opts.Name,
+		OwnerID:     opts.OwnerID,
+		LcCollate:   opts.LcCollate,
+		LcCtype:     opts.LcCtype,
+		DatastoreID: datastore.ID,
+	}
+	if database.LcCollate == "" {
+		database.LcCollate = defaultLocale
+	}
+	if database.LcCtype == "" {
+		database.LcCtype = defaultLocale
+	}
+	s.databases.add(database.ID, database)
+	s.scheduleStatus(&database.Status, dbaas.StatusPendingCreate)
+
+	writeJSON(w, http.StatusOK, "database", database)
+}
+
+// updateDatabase handles PUT /databases/{id}.
+func (s *Server) updateDatabase(w http.ResponseWriter, r *http.Request, database *dbaas.Database) {
+	var opts dbaas.DatabaseUpdateOpts
+	if err := decodeBody(r, "database", &opts); err != nil {
+		writeValidationError(w, "%s", err)
+		return
+	}
+	if !s.requireUser(w, opts.OwnerID, database.DatastoreID) {
+		return
+	}
+
+	database.OwnerID = opts.OwnerID
+	database.UpdatedAt = s.timestamp()
+	s.scheduleStatus(&database.Status, dbaas.StatusPendingUpdate)
+
+	writeJSON(w, http.StatusOK, "database", database)
+}
+
+// deleteDatabase handles DELETE /databases/{id}.
+// Grants, extensions and logical replication slots of the database are deleted along with it.
+func (s *Server) deleteDatabase(w http.ResponseWriter, _ *http.Request, database *dbaas.Database) {
+	s.scheduleDelete(&database.Status, func() {
+		s.databases.remove(database.ID)
+		removeWhere(s.grants, func(g dbaas.Grant) bool { return g.DatabaseID == database.ID })
+		removeWhere(s.extensions, func(e dbaas.Extension) bool { return e.DatabaseID == database.ID })
+		removeWhere(s.slots, func(l dbaas.LogicalReplicationSlot) bool { return l.DatabaseID == database.ID })
+	})
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handleGrants serves the /grants endpoints.
+func (s *Server) handleGrants(w http.ResponseWriter, r *http.Request, path []string) {
+	handleResource(w, r, path, s.grants, "grant", "grants", resourceHandlers[dbaas.Grant]{
+		create: s.createGrant,
+		delete: func(w http.ResponseWriter, _ *http.Request, grant *dbaas.Grant) {
+			s.scheduleDelete(&grant.Status, func() { s.grants.remove(grant.ID) })
+			w.WriteHeader(http.StatusNoContent)
+		},
+	})
+}
+
+// createGrant handles POST /grants.
+func (s *Server) createGrant(w http.ResponseWriter, r *http.Request) {
+	var opts dbaas.GrantCreateOpts
+	if err := decodeBody(r, "grant", &opts); err != nil {
+		writeValidationError(w, "%s", err)
+		return
+	}
+	datastore, ok := s.activeDatastore(w, opts.DatastoreID, EnginePostgreSQL, EngineMySQL)
+	if !ok || !s.requireUser(w, opts.UserID, datastore.ID) || !s.requireDatabase(w, opts.DatabaseID, datastore.ID) {
+		return
+	}
+	if exists(s.grants, func(g dbaas.Grant) bool {
+		return g.UserID == opts.UserID && g.DatabaseID == opts.DatabaseID
+	}) {
+		writeError(w, http.StatusConflict, fmt.Sprintf("grant for user %s on database %s already exists",
+			opts.UserID, opts.DatabaseID))
+		return
+	}
+
+	grant := &dbaas.Grant{
+		ID:          uuid.NewString(),
+		CreatedAt:   s.timestamp(),
+		UpdatedAt:   s.timestamp(),
+		ProjectID:   datastore.ProjectID,
+		DatastoreID: datastore.ID,
+		DatabaseID:  opts.DatabaseID,
+		UserID:      opts.UserID,
+	}
+	s.grants.add(grant.ID, grant)
+	s.scheduleStatus(&grant.Status, dbaas.StatusPendingCreate)
+
+	writeJSON(w, http.StatusOK, "grant", grant)
+}
+
+// handleACLs serves the /acls endpoints.
+func (s *Server) handleACLs(w http.ResponseWriter, r *http.Request, path []string) {
+	handleResource(w, r, path, s.acls, "acl", "acls", resourceHandlers[dbaas.ACL]{
+		create: s.createACL,
+		update: s.updateACL,
+		delete: func(w http.ResponseWriter, _ *http.Request, acl *dbaas.ACL) {
+			s.scheduleDelete(&acl.Status, func() { s.acls.remove(acl.ID) })
+			w.WriteHeader(http.StatusNoContent)
+		},
+	})
+}
+
+// createACL handles POST /acls.
+func (s *Server) createACL(w http.ResponseWriter, r *http.Request) {
+	var opts dbaas.ACLCreateOpts
+	if err := decodeBody(r, "acl", &opts); err != nil {
+		writeValidationError(w, "%s", err)
+		return
+	}
+	datastore, ok := s.activeDatastore(w, opts.DatastoreID, EngineKafka)
+	if !ok || !s.requireUser(w, opts.UserID, datastore.ID) {
+		return
+	}
+	switch {
+	case opts.PatternType != "literal" && opts.PatternType != "prefixed" && opts.PatternType != "all":
+		writeValidationError(w, "{'acl.pattern_type': \"'%s' is not one of ['literal', 'prefixed', 'all']\"}",
+			opts.PatternType)
+		return
+	case opts.PatternType != "all" && opts.Pattern == "":
+		writeValidationError(w, "{'acl.pattern': \"'' is too short\"}")
+		return
+	case !opts.AllowRead && !opts.AllowWrite:
+		writeValidationError(w, "at least one of allow_read and allow_write must be true")
+		return
+	}
+
+	acl := &dbaas.ACL{
+		ID:          uuid.NewString(),
+		CreatedAt:   s.timestamp(),
+		UpdatedAt:   s.timestamp(),
+		ProjectID:   datastore.ProjectID,
+		DatastoreID: datastore.ID,
+		Pattern:     opts.Pattern,
+		PatternType: opts.PatternType,
+		UserID:      opts.UserID,
+		AllowRead:   opts.AllowRead,
+		AllowWrite:  opts.AllowWrite,
+	}
+	s.acls.add(acl.ID, acl)
+	s.scheduleStatus(&acl.Status, dbaas.StatusPendingCreate)
+
+	writeJSON(w, http.StatusOK, "acl", acl)
+}
+
+// updateACL handles PUT /acls/{id}.
+func (s *Server) updateACL(w http.ResponseWriter, r *http.Request, acl *dbaas.ACL) {
+	var opts dbaas.ACLUpdateOpts
+	if err := decodeBody(r, "acl", &opts); err != nil {
+		writeValidationError(w, "%s", err)
+		return
+	}
+	if !opts.AllowRead && !opts.AllowWrite {
+		writeValidationError(w, "at least one of allow_read and allow_write must be true")
+		return
+	}
+
+	acl.AllowRead = opts.AllowRead
+	acl.AllowWrite = opts.AllowWrite
+	acl.UpdatedAt = s.timestamp()
+	s.scheduleStatus(&acl.Status, dbaas.StatusPendingUpdate)
+
+	writeJSON(w, http.StatusOK, "acl", acl)
+}
+
+// handleTopics serves the /topics endpoints.
+func (s *Server) handleTopics(w http.ResponseWriter, r *http.Request, path []string) {
+	handleResource(w, r, path, s.topics, "topic", "topics", resourceHandlers[dbaas.Topic]{
+		create: s.createTopic,
+		update: s.updateTopic,
+		delete: func(w http.ResponseWriter, _ *http.Request, topic *dbaas.Topic) {
+			s.scheduleDelete(&topic.Status, func() { s.topics.remove(topic.ID) })
+			w.WriteHeader(http.StatusNoContent)
+		},
+	})
+}
+
+// createTopic handles POST /topics.
+func (s *Server) createTopic(w http.ResponseWriter, r *http.Request) {
+	var opts dbaas.TopicCreateOpts
+	if err := decodeBody(r, "topic", &opts); err != nil {
+		writeValidationError(w, "%s", err)
+		return
+	}
+	datastore, ok := s.activeDatastore(w, opts.DatastoreID, EngineKafka)
+	if !ok {
+		return
+	}
+	switch {
+	case opts.Name == "":
+		writeValidationError(w, "{'topic.name': \"'' is too short\"}")
+		return
+	case opts.Partitions < 1:
+		writeValidationError(w, "{'topic.partitions': '0 is less than the minimum of 1'}")
+		return
+	case exists(s.topics, func(t dbaas.Topic) bool { return t.DatastoreID == datastore.ID && t.Name == opts.Name }):
+		writeError(w, http.StatusConflict, fmt.Sprintf("topic %s already exists", opts.Name))
+		return
+	}
+
+	topic := &dbaas.Topic{
+		ID:          uuid.NewString(),
+		CreatedAt:   s.timestamp(),
+		UpdatedAt:   s.timestamp(),
+		ProjectID:   datastore.ProjectID,
+		DatastoreID: datastore.ID,
+		Name:        opts.Name,
+		Partitions:  opts.Partitions,
+	}
+	s.topics.add(topic.ID, topic)
+	s.scheduleStatus(&topic.Status, dbaas.StatusPendingCreate)
+
+	writeJSON(w, http.StatusOK, "topic", topic)
+}
+
+// updateTopic handles PUT /topics/{id}. The number of partitions can only be increased.
+func (s *Server) updateTopic(w http.ResponseWriter, r *http.Request, topic *dbaas.Topic) {
+	var opts dbaas.TopicUpdateOpts
+	if err := decodeBody(r, "topic", &opts); err != nil {
+		writeValidationError(w, "%s", err)
+		return
+	}
+	if opts.Partitions < topic.Partitions {
+		writeValidationError(w, "number of partitions can't be decreased from %d to %d",
+			topic.Partitions, opts.Partitions)
+		return
+	}
+
+	topic.Partitions = opts.Partitions
+	topic.UpdatedAt = s.timestamp()
+	s.scheduleStatus(&topic.Status, dbaas.StatusPendingUpdate)
+
+	writeJSON(w, http.StatusOK, "topic", topic)
+}
+
+// handleExtensions serves the /extensions endpoints.
+func (s *Server) handleExtensions(w http.ResponseWriter, r *http.Request, path []string) {
+	handleResource(w, r, path, s.extensions, "extension", "extensions", resourceHandlers[dbaas.Extension]{
+		create: s.createExtension,
+		delete: func(w http.ResponseWriter, _ *http.Request, extension *dbaas.Extension) {
+			s.scheduleDelete(&extension.Status, func() { s.extensions.remove(extension.ID) })
+			w.WriteHeader(http.StatusNoContent)
+		},
+	})
+}
+
+// createExtension handles POST /extensions.
+func (s *Server) createExtension(w http.ResponseWriter, r *http.Request) {
+	var opts dbaas.ExtensionCreateOpts
+	if err := decodeBody(r, "extension", &opts); err != nil {
+		writeValidationError(w, "%s", err)
+		return
+	}
+	datastore, ok := s.activeDatastore(w, opts.DatastoreID, EnginePostgreSQL)
+	if !ok || !s.requireDatabase(w, opts.DatabaseID, datastore.ID) {
+		return
+	}
+	if !s.extensionAvailable(opts.AvailableExtensionID, datastore.TypeID) {
+		writeValidationError(w, "available extension %s not found for datastore type %s",
+			opts.AvailableExtensionID, datastore.TypeID)
+		return
+	}
+	if exists(s.extensions, func(e dbaas.Extension) bool {
+		return e.DatabaseID == opts.DatabaseID && e.AvailableExtensionID == opts.AvailableExtensionID
+	}) {
+		writeError(w, http.StatusConflict, fmt.Sprintf("extension %s already exists in database %s",
+			opts.AvailableExtensionID, opts.DatabaseID))
+		return
+	}
+
+	extension := &dbaas.Extension{
+		ID:                   uuid.NewString(),
+		ProjectID:            datastore.ProjectID,
+		AvailableExtensionID: opts.AvailableExtensionID,
+		CreatedAt:            s.timestamp(),
+		UpdatedAt:            s.timestamp(),
+		DatastoreID:          datastore.ID,
+		DatabaseID:           opts.DatabaseID,
+	}
+	s.extensions.add(extension.ID, extension)
+	s.scheduleStatus(&extension.Status, dbaas.StatusPendingCreate)
+
+	writeJSON(w, http.StatusOK, "extension", extension)
+}
+
+// extensionAvailable checks if the extension can be installed on the datastore type.
+func (s *Server) extensionAvailable(availableExtensionID, datastoreTypeID string) bool {
+	for _, extension := range s.availableExtensions {
+		if extension.ID != availableExtensionID {
+			continue
+		}
+		for _, id := range extension.DatastoreTypeIDs {
+			if id == datastoreTypeID {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// handleLogicalReplicationSlots serves the /logical-replication-slots endpoints.
+func (s *Server) handleLogicalReplicationSlots(w http.ResponseWriter, r *http.Request, path []string) {
+	handleResource(w, r, path, s.slots, "logical-replication-slot", "logical-replication-slots",
+		resourceHandlers[dbaas.LogicalReplicationSlot]{
+			create: s.createLogicalReplicationSlot,
+			delete: func(w http.ResponseWriter, _ *http.Request, slot *dbaas.LogicalReplicationSlot) {
+				s.scheduleDelete(&slot.Status, func() { s.slots.remove(slot.ID) })
+				w.WriteHeader(http.StatusNoContent)
+			},
+		})
+}
+
+// createLogicalReplicationSlot handles POST /logical-replication-slots.
+func (s *Server) createLogicalReplicationSlot(w http.ResponseWriter, r *http.Request) {
+	var opts dbaas.LogicalReplicationSlotCreateOpts
+	if err := decodeBody(r, "logical-replication-slot", &opts); err != nil {
+		writeValidationError(w, "%s", err)
+		return
+	}
+	datastore, ok := s.activeDatastore(w, opts.DatastoreID, EnginePostgreSQL)
+	if !ok || !s.requireDatabase(w, opts.DatabaseID, datastore.ID) {
+		return
+	}
+	switch {
+	case opts.Name == "":
+		writeValidationError(w, "{'logical-replication-slot.name': \"'' is too short\"}")
+		return
+	case exists(s.slots, func(l dbaas.LogicalReplicationSlot) bool {
+		return l.DatastoreID == datastore.ID && l.Name == opts.Name
+	}):
+		writeError(w, http.StatusConflict, fmt.Sprintf("logical replication slot %s already exists", opts.Name))
+		return
+	}
+
+	slot := &dbaas.LogicalReplicationSlot{
+		ID:          uuid.NewString(),
+		CreatedAt:   s.timestamp(),
+		UpdatedAt:   s.timestamp(),
+		ProjectID:   datastore.ProjectID,
+		Name:        opts.Name,
+		DatastoreID: datastore.ID,
+		DatabaseID:  opts.DatabaseID,
+	}
+	s.slots.add(slot.ID, slot)
+	s.scheduleStatus(&slot.Status, dbaas.StatusPendingCreate)
+
+	writeJSON(w, http.StatusOK, "logical-replication-slot", slot)
+}
+
+// handlePrometheusMetricTokens serves the /prometheus-metrics-tokens endpoints.
+// Tokens have no status, so changes are applied immediately.
+func (s *Server) handlePrometheusMetricTokens(w http.ResponseWriter, r *http.Request, path []string) {
+	handleResource(w, r, path, s.tokens, "prometheus-metrics-token", "prometheus-metrics-tokens",
+		resourceHandlers[dbaas.PrometheusMetricToken]{
+			create: s.createPrometheusMetricToken,
+			update: s.updatePrometheusMetricToken,
+			delete: func(w http.ResponseWriter, _ *http.Request, token *dbaas.PrometheusMetricToken) {
+				s.tokens.remove(token.ID)
+				w.WriteHeader(http.StatusNoContent)
+			},
+		})
+}
+
+// createPrometheusMetricToken handles POST /prometheus-metrics-tokens.
+func (s *Server) createPrometheusMetricToken(w http.ResponseWriter, r *http.Request) {
+	var opts dbaas.PrometheusMetricTokenCreateOpts
+	if err := decodeBody(r, "prometheus-metrics-token", &opts); err != nil {
+		writeValidationError(w, "%s", err)
+		return
+	}
+	if opts.Name == "" {
+		writeValidationError(w, "{'prometheus-metrics-token.name': \"'' is too short\"}")
+		return
+	}
+
+	value := make([]byte, 16)
+	if _, err := rand.Read(value); err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	token := &dbaas.PrometheusMetricToken{
+		ID:        uuid.NewString(),
+		CreatedAt: s.timestamp(),
+		UpdatedAt: s.timestamp(),
+		ProjectID: s.projectID,
+		Name:      opts.Name,
+		Value:     hex.EncodeToString(value),
+	}
+	s.tokens.add(token.ID, token)
+
+	writeJSON(w, http.StatusOK, "prometheus-metrics-token", token)
+}
+
+// updatePrometheusMetricToken handles PUT /prometheus-metrics-tokens/{id}.
+// Unlike other endpoints, the updated token is not wrapped into an object.
+func (s *Server) updatePrometheusMetricToken(
+	w http.ResponseWriter,
+	r *http.Request,
+	token *dbaas.PrometheusMetricToken,
+) {
+	var opts dbaas.PrometheusMetricTokenUpdateOpts
+	if err := decodeBody(r, "prometheus-metrics-token", &opts); err != nil {
+		writeValidationError(w, "%s", err)
+		return
+	}
+	if opts.Name == "" {
+		writeValidationError(w, "{'prometheus-metrics-token.name': \"'' is too short\"}")
+		return
+	}
+
+	token.Name = opts.Name
+	token.UpdatedAt = s.timestamp()
+
+	writeJSON(w, http.StatusOK, "", token)
+}
+
+// resourceHandlers holds handlers of the resource methods, nil handlers are not allowed.
+type resourceHandlers[T any] struct {
+	create func(w http.ResponseWriter, r *http.Request)
+	update func(w http.ResponseWriter, r *http.Request, item *T)
+	delete func(w http.ResponseWriter, r *http.Request, item *T)
+}
+
+// handleResource serves the collection and the single resource endpoints.
+// Resources can't be changed while they are in pending statuses.
+func handleResource[T any](
+	w http.ResponseWriter,
+	r *http.Request,
+	path []string,
+	items *collection[T],
+	single, plural string,
+	handlers resourceHandlers[T],
+) {
+	if len(path) == 0 || path[0] == "" {
+		switch {
+		case r.Method == http.MethodGet:
+			writeJSON(w, http.StatusOK, plural, filter(items.list(), r))
+		case r.Method == http.MethodPost && handlers.create != nil:
+			handlers.create(w, r)
+		default:
+			writeMethodNotAllowed(w, r)
+		}
+		return
+	}
+	if len(path) > 1 {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("path %s not found", r.URL.Path))
+		return
+	}
+
+	item, ok := items.get(path[0])
+	if !ok {
+		writeNotFound(w, single, path[0])
+		return
+	}
+	var handler func(w http.ResponseWriter, r *http.Request, item *T)
+	switch r.Method {
+	case http.MethodGet:
+		writeJSON(w, http.StatusOK, single, item)
+		return
+	case http.MethodPut:
+		handler = handlers.update
+	case http.MethodDelete:
+		handler = handlers.delete
+	}
+	if handler == nil {
+		writeMethodNotAllowed(w, r)
+		return
+	}
+	if resource, ok := any(item).(dbaas.StatusResource); ok {
+		if !requireActive(w, single, path[0], resource.ResourceStatus()) {
+			return
+		}
+	}
+	handler(w, r, item)
+}
+
+// activeDatastore returns the datastore for a new child resource.
+// The datastore must be active and its engine must be one of the given engines.
+func (s *Server) activeDatastore(
+	w http.ResponseWriter,
+	datastoreID string,
+	engines ...string,
+) (*dbaas.Datastore, bool) {
+	datastore, ok := s.datastores.get(datastoreID)
+	if !ok {
+		writeValidationError(w, "datastore %s not found", datastoreID)
+		return nil, false
+	}
+	if !requireActive(w, "datastore", datastore.ID, datastore.Status) || !s.requireEngine(w, datastore, engines...) {
+		return nil, false
+	}
+	return datastore, true
+}
+
+// requireUser checks if the user exists in the datastore.
+func (s *Server) requireUser(w http.ResponseWriter, userID, datastoreID string) bool {
+	user, ok := s.users.get(userID)
+	if !ok || user.DatastoreID != datastoreID || user.Status == dbaas.StatusPendingDelete {
+		writeValidationError(w, "user %s not found in datastore %s", userID, datastoreID)
+		return false
+	}
+	return true
+}
+
+// requireDatabase checks if the database exists in the datastore.
+func (s *Server) requireDatabase(w http.ResponseWriter, databaseID, datastoreID string) bool {
+	database, ok := s.databases.get(databaseID)
+	if !ok || database.DatastoreID != datastoreID || database.Status == dbaas.StatusPendingDelete {
+		writeValidationError(w, "database %s not found in datastore %s", databaseID, datastoreID)
+		return false
+	}
+	return true
+}
+
+// requireActive checks if the resource is active and can be changed.
+func requireActive(w http.ResponseWriter, kind, id string, status dbaas.Status) bool {
+	if status != dbaas.StatusActive {
+		writeError(w, http.StatusConflict, fmt.Sprintf("%s %s is in %s status", kind, id, status))
+		return false
+	}
+	return true
+}
+
+// scheduleStatus puts the resource into the pending status and schedules its activation.
+// Injected faults may replace ACTIVE with another result status.
+func (s *Server) scheduleStatus(status *dbaas.Status, pending dbaas.Status) {
+	*status = pending
+	result := s.resultStatus
+	s.schedule(func() {
+		if *status == pending {
+			*status = result
+		}
+	})
+}
+
+// scheduleDelete puts the resource into PENDING_DELETE status and schedules its removal.
+func (s *Server) scheduleDelete(status *dbaas.Status, remove func()) {
+	*status = dbaas.StatusPendingDelete
+	s.schedule(remove)
+}
+
+// exists checks if any resource of the collection matches the predicate.
+func exists[T any](c *collection[T], match func(item T) bool) bool {
+	for _, item := range c.items {
+		if match(*item) {
+			return true
+		}
+	}
+	return false
+}
+
+// removeWhere removes all resources of the collection that match the predicate.
+func removeWhere[T any](c *collection[T], match func(item T) bool) {
+	for _, id := range append([]string(nil), c.order...) {
+		if match(*c.items[id]) {
+			c.remove(id)
+		}
+	}
+}
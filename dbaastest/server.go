@@ -0,0 +1,499 @@
+// Package dbaastest provides an in-memory fake of the Selectel DBaaS v1 API
+// for testing code that depends on the dbaas package.
+package dbaastest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/selectel/dbaas-go"
+)
+
+const (
+	// apiPrefix is a path prefix of the fake API endpoint.
+	apiPrefix = "/v1"
+
+	// timeFormat is a format of timestamps returned by the API.
+	timeFormat = "2006-01-02T15:04:05"
+
+	// defaultToken is an authentication token accepted by the server by default.
+	defaultToken = "test-token"
+)
+
+// Server is a stateful in-process fake of the DBaaS API.
+// Resources pass through the pending statuses and become active or disappear
+// after the transition delay, just like in the real service.
+type Server struct {
+	server *httptest.Server
+	now    func() time.Time
+
+	datastores *collection[dbaas.Datastore]
+	users      *collection[dbaas.User]
+	databases  *collection[dbaas.Database]
+	grants     *collection[dbaas.Grant]
+	acls       *collection[dbaas.ACL]
+	topics     *collection[dbaas.Topic]
+	extensions *collection[dbaas.Extension]
+	slots      *collection[dbaas.LogicalReplicationSlot]
+	tokens     *collection[dbaas.PrometheusMetricToken]
+
+	token        string
+	projectID    string
+	resultStatus dbaas.Status
+
+	transitions []transition
+	faults      []*faultState
+
+	datastoreTypes          []dbaas.DatastoreType
+	flavors                 []dbaas.FlavorResponse
+	configurationParameters []dbaas.ConfigurationParameter
+	availableExtensions     []dbaas.AvailableExtension
+
+	transitionDelay time.Duration
+
+	mu sync.Mutex
+}
+
+// Option configures the Server created by NewServer.
+type Option func(*Server)
+
+// WithTransitionDelay sets how long resources stay in the pending statuses.
+// With zero delay resources are pending until the next request.
+func WithTransitionDelay(delay time.Duration) Option {
+	return func(s *Server) {
+		s.transitionDelay = delay
+	}
+}
+
+// WithToken sets an authentication token accepted by the server.
+func WithToken(token string) Option {
+	return func(s *Server) {
+		s.token = token
+	}
+}
+
+// WithProjectID sets a project ID assigned to the created resources.
+func WithProjectID(projectID string) Option {
+	return func(s *Server) {
+		s.projectID = projectID
+	}
+}
+
+// WithClock sets a function that returns the current time.
+// It allows to control status transitions in tests without sleeping.
+func WithClock(now func() time.Time) Option {
+	return func(s *Server) {
+		s.now = now
+	}
+}
+
+// WithDatastoreTypes replaces the default datastore types catalog.
+func WithDatastoreTypes(datastoreTypes []dbaas.DatastoreType) Option {
+	return func(s *Server) {
+		s.datastoreTypes = datastoreTypes
+	}
+}
+
+// WithFlavors replaces the default flavors catalog.
+func WithFlavors(flavors []dbaas.FlavorResponse) Option {
+	return func(s *Server) {
+		s.flavors = flavors
+	}
+}
+
+// WithConfigurationParameters replaces the default configuration parameters catalog.
+func WithConfigurationParameters(parameters []dbaas.ConfigurationParameter) Option {
+	return func(s *Server) {
+		s.configurationParameters = parameters
+	}
+}
+
+// WithAvailableExtensions replaces the default available extensions catalog.
+func WithAvailableExtensions(availableExtensions []dbaas.AvailableExtension) Option {
+	return func(s *Server) {
+		s.availableExtensions = availableExtensions
+	}
+}
+
+// NewServer starts a new fake DBaaS server. It should be closed with Close.
+func NewServer(opts ...Option) *Server {
+	s := &Server{
+		now:                     time.Now,
+		datastores:              newCollection[dbaas.Datastore](),
+		users:                   newCollection[dbaas.User](),
+		databases:               newCollection[dbaas.Database](),
+		grants:                  newCollection[dbaas.Grant](),
+		acls:                    newCollection[dbaas.ACL](),
+		topics:                  newCollection[dbaas.Topic](),
+		extensions:              newCollection[dbaas.Extension](),
+		slots:                   newCollection[dbaas.LogicalReplicationSlot](),
+		tokens:                  newCollection[dbaas.PrometheusMetricToken](),
+		datastoreTypes:          defaultDatastoreTypes(),
+		flavors:                 defaultFlavors(),
+		configurationParameters: defaultConfigurationParameters(),
+		availableExtensions:     defaultAvailableExtensions(),
+		token:                   defaultToken,
+		projectID:               uuid.NewString(),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	s.server = httptest.NewServer(s)
+
+	return s
+}
+
+// Close shuts down the server.
+func (s *Server) Close() {
+	s.server.Close()
+}
+
+// Endpoint returns the DBaaS API endpoint of the server.
+func (s *Server) Endpoint() string {
+	return s.server.URL + apiPrefix
+}
+
+// Client returns a new API client pointed at the server.
+// Options are applied after the endpoint, token and HTTP client settings.
+func (s *Server) Client(opts ...dbaas.Option) *dbaas.API {
+	opts = append([]dbaas.Option{
+		dbaas.WithToken(s.token),
+		dbaas.WithHTTPClient(s.server.Client()),
+	}, opts...)
+
+	api, err := dbaas.New(s.Endpoint(), opts...)
+	if err != nil {
+		panic(fmt.Sprintf("dbaastest: could not create client: %v", err))
+	}
+
+	return api
+}
+
+// ServeHTTP handles the API requests.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Header.Get("X-Auth-Token") != s.token {
+		writeError(w, http.StatusUnauthorized, "authentication required")
+		return
+	}
+	if !strings.HasPrefix(r.URL.Path, apiPrefix+"/") {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("path %s not found", r.URL.Path))
+		return
+	}
+	path := strings.Split(strings.Trim(strings.TrimPrefix(r.URL.Path, apiPrefix), "/"), "/")
+
+	s.mu.Lock()
+	fault := s.matchFault(r, path)
+	s.mu.Unlock()
+	if fault != nil && fault.inject(w, r) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.advance()
+
+	s.resultStatus = dbaas.StatusActive
+	if fault != nil && fault.ResultStatus != "" {
+		s.resultStatus = fault.ResultStatus
+	}
+
+	switch "/" + path[0] {
+	case dbaas.DatastoresURI:
+		s.handleDatastores(w, r, path[1:])
+	case dbaas.UsersURI:
+		s.handleUsers(w, r, path[1:])
+	case dbaas.DatabasesURI:
+		s.handleDatabases(w, r, path[1:])
+	case dbaas.GrantsURI:
+		s.handleGrants(w, r, path[1:])
+	case dbaas.ACLsURI:
+		s.handleACLs(w, r, path[1:])
+	case dbaas.TopicsURI:
+		s.handleTopics(w, r, path[1:])
+	case dbaas.ExtensionsURI:
+		s.handleExtensions(w, r, path[1:])
+	case dbaas.LogicalReplicationSlotsURI:
+		s.handleLogicalReplicationSlots(w, r, path[1:])
+	case dbaas.PrometheusMetricsTokensURI:
+		s.handlePrometheusMetricTokens(w, r, path[1:])
+	case dbaas.FloatingIPsURI:
+		s.handleFloatingIPs(w, r, path[1:])
+	case dbaas.DatastoreTypesURI:
+		handleCatalog(w, r, path[1:], s.datastoreTypes,
+			func(t dbaas.DatastoreType) string { return t.ID }, "datastore-type", "datastore-types")
+	case dbaas.FlavorsURI:
+		handleCatalog(w, r, path[1:], s.flavors,
+			func(f dbaas.FlavorResponse) string { return f.ID }, "flavor", "flavors")
+	case dbaas.ConfigurationParametersURI:
+		handleCatalog(w, r, path[1:], s.configurationParameters,
+			func(p dbaas.ConfigurationParameter) string { return p.ID },
+			"configuration-parameter", "configuration-parameters")
+	case dbaas.AvailableExtensionsURI:
+		handleCatalog(w, r, path[1:], s.availableExtensions,
+			func(e dbaas.AvailableExtension) string { return e.ID }, "available-extension", "available-extensions")
+	default:
+		writeError(w, http.StatusNotFound, fmt.Sprintf("path %s not found", r.URL.Path))
+	}
+}
+
+// transition is a scheduled change of a resource state.
+type transition struct {
+	at    time.Time
+	apply func()
+}
+
+// schedule postpones the change of a resource state for the transition delay.
+func (s *Server) schedule(apply func()) {
+	s.transitions = append(s.transitions, transition{
+		at:    s.now().Add(s.transitionDelay),
+		apply: apply,
+	})
+}
+
+// advance applies all transitions that are due.
+func (s *Server) advance() {
+	now := s.now()
+	pending := s.transitions[:0]
+	var due []transition
+	for _, t := range s.transitions {
+		if t.at.After(now) {
+			pending = append(pending, t)
+		} else {
+			due = append(due, t)
+		}
+	}
+	s.transitions = pending
+	for _, t := range due {
+		t.apply()
+	}
+}
+
+// timestamp returns the current time in the API format.
+func (s *Server) timestamp() string {
+	return s.now().UTC().Format(timeFormat)
+}
+
+// collection stores resources of a single kind in creation order.
+type collection[T any] struct {
+	items map[string]*T
+	order []string
+}
+
+// newCollection creates an empty collection.
+func newCollection[T any]() *collection[T] {
+	return &collection[T]{items: make(map[string]*T)}
+}
+
+// add stores a resource with the given ID.
+func (c *collection[T]) add(id string, item *T) {
+	c.items[id] = item
+	c.order = append(c.order, id)
+}
+
+// get returns a resource with the given ID.
+func (c *collection[T]) get(id string) (*T, bool) {
+	item, ok := c.items[id]
+	return item, ok
+}
+
+// remove deletes a resource with the given ID.
+func (c *collection[T]) remove(id string) {
+	delete(c.items, id)
+	for i, itemID := range c.order {
+		if itemID == id {
+			c.order = append(c.order[:i], c.order[i+1:]...)
+			break
+		}
+	}
+}
+
+// list returns copies of all resources in creation order.
+func (c *collection[T]) list() []T {
+	result := make([]T, 0, len(c.order))
+	for _, id := range c.order {
+		result = append(result, *c.items[id])
+	}
+	return result
+}
+
+// filter returns resources that match all the query parameters.
+// Parameters are compared with the JSON fields of the resources,
+// parameters that don't correspond to any field are ignored.
+// The result is paginated with the marker, offset and limit parameters.
+func filter[T any](items []T, r *http.Request) []T {
+	query := r.URL.Query()
+	if len(query) == 0 {
+		return items
+	}
+
+	result := make([]T, 0, len(items))
+	for _, item := range items {
+		if matchQuery(item, query) {
+			result = append(result, item)
+		}
+	}
+	return paginate(result, query)
+}
+
+// paginate returns the page of the resources. The page starts after the resource with the marker ID
+// or after the offset, resources that follow an unknown marker are not returned.
+func paginate[T any](items []T, query url.Values) []T {
+	if marker := query.Get("marker"); marker != "" {
+		start := len(items)
+		for i, item := range items {
+			if fields := jsonFields(item); fields != nil && fields["id"] == marker {
+				start = i + 1
+				break
+			}
+		}
+		items = items[start:]
+	}
+	if offset, err := strconv.Atoi(query.Get("offset")); err == nil && offset > 0 {
+		if offset > len(items) {
+			offset = len(items)
+		}
+		items = items[offset:]
+	}
+	if limit, err := strconv.Atoi(query.Get("limit")); err == nil && limit > 0 && limit < len(items) {
+		items = items[:limit]
+	}
+	return items
+}
+
+// jsonFields returns the JSON fields of the resource.
+func jsonFields(item any) map[string]any {
+	body, err := json.Marshal(item)
+	if err != nil {
+		return nil
+	}
+	var fields map[string]any
+	if err := json.Unmarshal(body, &fields); err != nil {
+		return nil
+	}
+	return fields
+}
+
+// matchQuery checks if the resource fields are equal to the query parameters.
+// A parameter named after a list field in singular, like datastore_type_id for datastore_type_ids,
+// matches resources whose list contains the value.
+func matchQuery(item any, query map[string][]string) bool {
+	fields := jsonFields(item)
+	if fields == nil {
+		return false
+	}
+
+	for key, values := range query {
+		if len(values) == 0 {
+			continue
+		}
+		if value, ok := fields[key]; ok {
+			if fmt.Sprintf("%v", value) != values[0] {
+				return false
+			}
+			continue
+		}
+		if list, ok := fields[key+"s"].([]any); ok && !containsValue(list, values[0]) {
+			return false
+		}
+	}
+	return true
+}
+
+// containsValue reports whether the list contains the query value.
+func containsValue(list []any, value string) bool {
+	for _, item := range list {
+		if fmt.Sprintf("%v", item) == value {
+			return true
+		}
+	}
+	return false
+}
+
+// handleCatalog serves read-only catalog endpoints.
+func handleCatalog[T any](
+	w http.ResponseWriter,
+	r *http.Request,
+	path []string,
+	items []T,
+	idOf func(item T) string,
+	single, plural string,
+) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, fmt.Sprintf("method %s is not allowed", r.Method))
+		return
+	}
+	if len(path) == 0 || path[0] == "" {
+		writeJSON(w, http.StatusOK, plural, filter(items, r))
+		return
+	}
+	for _, item := range items {
+		if idOf(item) == path[0] {
+			writeJSON(w, http.StatusOK, single, item)
+			return
+		}
+	}
+	writeError(w, http.StatusNotFound, fmt.Sprintf("%s %s not found.", strings.ReplaceAll(single, "-", ""), path[0]))
+}
+
+// decodeBody decodes the request body wrapped into an object with the given key.
+// Empty key means the body is not wrapped.
+func decodeBody(r *http.Request, key string, v any) error {
+	if key == "" {
+		return json.NewDecoder(r.Body).Decode(v)
+	}
+
+	var wrapper map[string]json.RawMessage
+	if err := json.NewDecoder(r.Body).Decode(&wrapper); err != nil {
+		return err
+	}
+	body, ok := wrapper[key]
+	if !ok {
+		return fmt.Errorf("'%s' is a required property", key)
+	}
+	return json.Unmarshal(body, v)
+}
+
+// writeJSON writes the value wrapped into an object with the given key.
+// Empty key means the value is written as is.
+func writeJSON(w http.ResponseWriter, statusCode int, key string, value any) {
+	var body any = value
+	if key != "" {
+		body = map[string]any{key: value}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+// writeError writes the error in the API format.
+func writeError(w http.ResponseWriter, statusCode int, message string) {
+	writeJSON(w, statusCode, "error", map[string]any{
+		"code":    statusCode,
+		"title":   http.StatusText(statusCode),
+		"message": message,
+	})
+}
+
+// writeValidationError writes the 400 error for the invalid request.
+func writeValidationError(w http.ResponseWriter, format string, args ...any) {
+	writeError(w, http.StatusBadRequest, "Validation failure: "+fmt.Sprintf(format, args...))
+}
+
+// writeNotFound writes the 404 error for the missing resource.
+func writeNotFound(w http.ResponseWriter, kind, id string) {
+	writeError(w, http.StatusNotFound, fmt.Sprintf("%s %s not found.", kind, id))
+}
+
+// writeMethodNotAllowed writes the 405 error.
+func writeMethodNotAllowed(w http.ResponseWriter, r *http.Request) {
+	writeError(w, http.StatusMethodNotAllowed, fmt.Sprintf("method %s is not allowed for %s", r.Method, r.URL.Path))
+}
@@ -0,0 +1,272 @@
+package dbaastest
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/selectel/dbaas-go"
+)
+
+// testClock is a manually advanced clock.
+type testClock struct {
+	now time.Time
+	mu  sync.Mutex
+}
+
+func (c *testClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *testClock) Add(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+const testWaitInterval = time.Millisecond
+
+func createActiveDatastore(t *testing.T, api *dbaas.API, engine, version string) dbaas.Datastore {
+	t.Helper()
+
+	datastore, err := api.CreateDatastore(context.Background(), dbaas.DatastoreCreateOpts{
+		Name:      "test",
+		TypeID:    DatastoreTypeID(engine, version),
+		SubnetID:  "b1bd8b6a-7ad6-4fda-8f3a-e8ba8e0d1f3e",
+		FlavorID:  CatalogID("flavor", "2-4096-32"),
+		NodeCount: 1,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, dbaas.StatusPendingCreate, datastore.Status)
+
+	datastore, err = api.WaitForDatastoreStatus(context.Background(), datastore.ID,
+		[]dbaas.Status{dbaas.StatusActive}, &dbaas.WaitOpts{Interval: testWaitInterval})
+	require.NoError(t, err)
+
+	return datastore
+}
+
+func TestServerDatastoreLifecycle(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+	api := server.Client()
+	ctx := context.Background()
+
+	datastore := createActiveDatastore(t, api, EnginePostgreSQL, "16")
+	assert.Equal(t, dbaas.StatusActive, datastore.Status)
+	assert.NotEmpty(t, datastore.CreationFinishedAt)
+	require.Len(t, datastore.Instances, 1)
+	assert.Equal(t, roleMaster, datastore.Instances[0].Role)
+	assert.Equal(t, "master."+datastore.ID+"."+connectionDomain, datastore.Connection["MASTER"])
+
+	datastore, err := api.ConfigDatastore(ctx, datastore.ID, dbaas.DatastoreConfigOpts{
+		Config: map[string]any{"work_mem": 8192},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, dbaas.StatusPendingUpdate, datastore.Status)
+
+	user, err := api.CreateUserAndWait(ctx, dbaas.UserCreateOpts{
+		Name: "user", Password: "secret", DatastoreID: datastore.ID,
+	}, &dbaas.WaitOpts{Interval: testWaitInterval})
+	require.NoError(t, err)
+	assert.Equal(t, dbaas.StatusActive, user.Status)
+
+	database, err := api.CreateDatabaseAndWait(ctx, dbaas.DatabaseCreateOpts{
+		Name: "db", OwnerID: user.ID, DatastoreID: datastore.ID,
+	}, &dbaas.WaitOpts{Interval: testWaitInterval})
+	require.NoError(t, err)
+
+	_, err = api.CreateGrantAndWait(ctx, dbaas.GrantCreateOpts{
+		DatastoreID: datastore.ID, DatabaseID: database.ID, UserID: user.ID,
+	}, &dbaas.WaitOpts{Interval: testWaitInterval})
+	require.NoError(t, err)
+
+	datastore, err = api.Datastore(ctx, datastore.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 8192.0, datastore.Config["work_mem"])
+
+	require.NoError(t, api.DeleteDatastore(ctx, datastore.ID))
+	require.NoError(t, api.WaitForDatastoreDeleted(ctx, datastore.ID, &dbaas.WaitOpts{Interval: testWaitInterval}))
+
+	users, err := api.Users(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, users)
+	grants, err := api.Grants(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, grants)
+}
+
+func TestServerReferentialChecks(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+	api := server.Client()
+	ctx := context.Background()
+
+	first := createActiveDatastore(t, api, EnginePostgreSQL, "16")
+	second := createActiveDatastore(t, api, EnginePostgreSQL, "16")
+
+	user, err := api.CreateUserAndWait(ctx, dbaas.UserCreateOpts{
+		Name: "user", Password: "secret", DatastoreID: first.ID,
+	}, &dbaas.WaitOpts{Interval: testWaitInterval})
+	require.NoError(t, err)
+	database, err := api.CreateDatabaseAndWait(ctx, dbaas.DatabaseCreateOpts{
+		Name: "db", DatastoreID: second.ID,
+	}, &dbaas.WaitOpts{Interval: testWaitInterval})
+	require.NoError(t, err)
+
+	_, err = api.CreateGrant(ctx, dbaas.GrantCreateOpts{
+		DatastoreID: second.ID, DatabaseID: database.ID, UserID: user.ID,
+	})
+	assert.ErrorIs(t, err, dbaas.ErrBadRequest)
+
+	_, err = api.CreateUser(ctx, dbaas.UserCreateOpts{Name: "user", Password: "secret", DatastoreID: first.ID})
+	assert.ErrorIs(t, err, dbaas.ErrConflict)
+
+	_, err = api.CreateTopic(ctx, dbaas.TopicCreateOpts{Name: "topic", Partitions: 1, DatastoreID: first.ID})
+	assert.ErrorIs(t, err, dbaas.ErrBadRequest)
+
+	_, err = api.CreateExtension(ctx, dbaas.ExtensionCreateOpts{
+		AvailableExtensionID: CatalogID("available-extension", "hstore"),
+		DatastoreID:          second.ID,
+		DatabaseID:           database.ID,
+	})
+	require.NoError(t, err)
+
+	_, err = api.ConfigDatastore(ctx, first.ID, dbaas.DatastoreConfigOpts{
+		Config: map[string]any{"data_directory": "/tmp"},
+	})
+	assert.ErrorIs(t, err, dbaas.ErrBadRequest)
+
+	_, err = api.User(ctx, "missing")
+	assert.ErrorIs(t, err, dbaas.ErrNotFound)
+}
+
+func TestServerPendingDatastore(t *testing.T) {
+	clock := &testClock{now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+	server := NewServer(WithClock(clock.Now), WithTransitionDelay(time.Minute))
+	defer server.Close()
+	api := server.Client()
+	ctx := context.Background()
+
+	datastore, err := api.CreateDatastore(ctx, dbaas.DatastoreCreateOpts{
+		Name:      "kafka",
+		TypeID:    DatastoreTypeID(EngineKafka, "3.5"),
+		SubnetID:  "b1bd8b6a-7ad6-4fda-8f3a-e8ba8e0d1f3e",
+		Flavor:    &dbaas.Flavor{Vcpus: 2, RAM: 4096, Disk: 32},
+		NodeCount: 1,
+	})
+	require.NoError(t, err)
+
+	_, err = api.CreateTopic(ctx, dbaas.TopicCreateOpts{Name: "topic", Partitions: 1, DatastoreID: datastore.ID})
+	assert.ErrorIs(t, err, dbaas.ErrConflict)
+
+	clock.Add(time.Minute)
+
+	datastore, err = api.Datastore(ctx, datastore.ID)
+	require.NoError(t, err)
+	assert.Equal(t, dbaas.StatusActive, datastore.Status)
+	assert.Equal(t, "2024-01-01T00:01:00", datastore.CreationFinishedAt)
+
+	topic, err := api.CreateTopic(ctx, dbaas.TopicCreateOpts{Name: "topic", Partitions: 1, DatastoreID: datastore.ID})
+	require.NoError(t, err)
+	assert.Equal(t, dbaas.StatusPendingCreate, topic.Status)
+}
+
+func TestServerPrometheusMetricTokens(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+	api := server.Client()
+	ctx := context.Background()
+
+	token, err := api.CreatePrometheusMetricToken(ctx, dbaas.PrometheusMetricTokenCreateOpts{Name: "token"})
+	require.NoError(t, err)
+	assert.NotEmpty(t, token.Value)
+
+	token, err = api.UpdatePrometheusMetricToken(ctx, token.ID, dbaas.PrometheusMetricTokenUpdateOpts{Name: "renamed"})
+	require.NoError(t, err)
+	assert.Equal(t, "renamed", token.Name)
+
+	require.NoError(t, api.DeletePrometheusMetricToken(ctx, token.ID))
+	_, err = api.PrometheusMetricToken(ctx, token.ID)
+	assert.ErrorIs(t, err, dbaas.ErrNotFound)
+}
+
+func TestServerPagination(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+	api := server.Client()
+	ctx := context.Background()
+
+	var names []string
+	for _, name := range []string{"a", "b", "c", "d", "e"} {
+		_, err := api.CreatePrometheusMetricToken(ctx, dbaas.PrometheusMetricTokenCreateOpts{Name: name})
+		require.NoError(t, err)
+		names = append(names, name)
+	}
+
+	params := &dbaas.PrometheusMetricTokenQueryParams{Page: dbaas.Page{Limit: 2}}
+	tokens, err := api.IteratePrometheusMetricTokens(params).Collect(ctx)
+	require.NoError(t, err)
+	actual := make([]string, 0, len(tokens))
+	for _, token := range tokens {
+		actual = append(actual, token.Name)
+	}
+	assert.Equal(t, names, actual)
+
+	params = &dbaas.PrometheusMetricTokenQueryParams{Page: dbaas.Page{Limit: 2, Marker: tokens[2].ID}}
+	tokens, err = api.IteratePrometheusMetricTokens(params).Collect(ctx)
+	require.NoError(t, err)
+	require.Len(t, tokens, 2)
+	assert.Equal(t, "d", tokens[0].Name)
+	assert.Equal(t, "e", tokens[1].Name)
+}
+
+func TestServerCatalog(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+	api := server.Client()
+	ctx := context.Background()
+
+	datastoreType, err := api.DatastoreType(ctx, DatastoreTypeID(EngineMySQL, "8"))
+	require.NoError(t, err)
+	assert.Equal(t, EngineMySQL, datastoreType.Engine)
+
+	flavors, err := api.Flavors(ctx)
+	require.NoError(t, err)
+	assert.Len(t, flavors, 4)
+
+	flavors, err = api.Flavors(ctx, &dbaas.FlavorQueryParams{DatastoreTypeID: datastoreType.ID, FlSize: "standard"})
+	require.NoError(t, err)
+	assert.Len(t, flavors, 2)
+
+	parameters, err := api.ConfigurationParameters(ctx)
+	require.NoError(t, err)
+	assert.NotEmpty(t, parameters)
+
+	parameters, err = api.ConfigurationParameters(ctx, &dbaas.ConfigurationParameterQueryParams{
+		DatastoreTypeID: datastoreType.ID,
+	})
+	require.NoError(t, err)
+	assert.Len(t, parameters, 3)
+
+	extensions, err := api.AvailableExtensions(ctx, &dbaas.AvailableExtensionQueryParams{
+		DatastoreTypeID: datastoreType.ID,
+	})
+	require.NoError(t, err)
+	assert.Empty(t, extensions)
+}
+
+func TestServerUnauthorized(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+	api := server.Client(dbaas.WithToken("wrong"))
+
+	_, err := api.Datastores(context.Background(), nil)
+	assert.ErrorIs(t, err, dbaas.ErrUnauthorized)
+}
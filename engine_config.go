@@ -0,0 +1,230 @@
+package dbaas
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// EngineConfig is implemented by typed engine configurations.
+// Map returns the configuration in the form accepted by DatastoreCreateOpts and DatastoreConfigOpts.
+type EngineConfig interface {
+	Map() map[string]any
+}
+
+// PostgreSQLConfig represents PostgreSQL configuration parameters.
+// Nil fields are not set. Parameters without a field are kept in Extra.
+type PostgreSQLConfig struct {
+	Extra                           map[string]any `json:"-"`
+	WorkMem                         *int           `json:"work_mem,omitempty"`
+	MaintenanceWorkMem              *int           `json:"maintenance_work_mem,omitempty"`
+	SharedBuffers                   *int           `json:"shared_buffers,omitempty"`
+	EffectiveCacheSize              *int           `json:"effective_cache_size,omitempty"`
+	MaxConnections                  *int           `json:"max_connections,omitempty"`
+	MaxWorkerProcesses              *int           `json:"max_worker_processes,omitempty"`
+	MaxParallelWorkers              *int           `json:"max_parallel_workers,omitempty"`
+	MaxParallelWorkersPerGather     *int           `json:"max_parallel_workers_per_gather,omitempty"`
+	EffectiveIOConcurrency          *int           `json:"effective_io_concurrency,omitempty"`
+	RandomPageCost                  *float64       `json:"random_page_cost,omitempty"`
+	DefaultStatisticsTarget         *int           `json:"default_statistics_target,omitempty"`
+	MaxWALSize                      *int           `json:"max_wal_size,omitempty"`
+	MinWALSize                      *int           `json:"min_wal_size,omitempty"`
+	CheckpointTimeout               *int           `json:"checkpoint_timeout,omitempty"`
+	StatementTimeout                *int           `json:"statement_timeout,omitempty"`
+	IdleInTransactionSessionTimeout *int           `json:"idle_in_transaction_session_timeout,omitempty"`
+	LogMinDurationStatement         *int           `json:"log_min_duration_statement,omitempty"`
+	TempFileLimit                   *int           `json:"temp_file_limit,omitempty"`
+	AutovacuumMaxWorkers            *int           `json:"autovacuum_max_workers,omitempty"`
+	Autovacuum                      *bool          `json:"autovacuum,omitempty"`
+	JIT                             *bool          `json:"jit,omitempty"`
+	SessionReplicationRole          *string        `json:"session_replication_role,omitempty"`
+}
+
+// MySQLConfig represents MySQL configuration parameters.
+// Nil fields are not set. Parameters without a field are kept in Extra.
+type MySQLConfig struct {
+	Extra                        map[string]any `json:"-"`
+	InnodbBufferPoolSize         *int           `json:"innodb_buffer_pool_size,omitempty"`
+	InnodbLogFileSize            *int           `json:"innodb_log_file_size,omitempty"`
+	InnodbFlushLogAtTrxCommit    *int           `json:"innodb_flush_log_at_trx_commit,omitempty"`
+	MaxConnections               *int           `json:"max_connections,omitempty"`
+	MaxAllowedPacket             *int           `json:"max_allowed_packet,omitempty"`
+	TmpTableSize                 *int           `json:"tmp_table_size,omitempty"`
+	MaxHeapTableSize             *int           `json:"max_heap_table_size,omitempty"`
+	TableOpenCache               *int           `json:"table_open_cache,omitempty"`
+	ThreadCacheSize              *int           `json:"thread_cache_size,omitempty"`
+	SortBufferSize               *int           `json:"sort_buffer_size,omitempty"`
+	JoinBufferSize               *int           `json:"join_buffer_size,omitempty"`
+	WaitTimeout                  *int           `json:"wait_timeout,omitempty"`
+	InteractiveTimeout           *int           `json:"interactive_timeout,omitempty"`
+	LongQueryTime                *float64       `json:"long_query_time,omitempty"`
+	ConcurrentInsert             *string        `json:"concurrent_insert,omitempty"`
+	SQLMode                      *string        `json:"sql_mode,omitempty"`
+	TransactionIsolation         *string        `json:"transaction_isolation,omitempty"`
+	InnodbPrintAllDeadlocks      *bool          `json:"innodb_print_all_deadlocks,omitempty"`
+	ExplicitDefaultsForTimestamp *bool          `json:"explicit_defaults_for_timestamp,omitempty"`
+}
+
+// RedisConfig represents Redis configuration parameters.
+// Nil fields are not set. Parameters without a field are kept in Extra.
+type RedisConfig struct {
+	Extra                map[string]any `json:"-"`
+	MaxmemoryPolicy      *string        `json:"maxmemory-policy,omitempty"`
+	NotifyKeyspaceEvents *string        `json:"notify-keyspace-events,omitempty"`
+	Timeout              *int           `json:"timeout,omitempty"`
+	TCPKeepalive         *int           `json:"tcp-keepalive,omitempty"`
+	MaxmemorySamples     *int           `json:"maxmemory-samples,omitempty"`
+	LFULogFactor         *int           `json:"lfu-log-factor,omitempty"`
+	LFUDecayTime         *int           `json:"lfu-decay-time,omitempty"`
+}
+
+// KafkaConfig represents Kafka configuration parameters.
+// Nil fields are not set. Parameters without a field are kept in Extra.
+type KafkaConfig struct {
+	Extra                       map[string]any `json:"-"`
+	LogRetentionHours           *int           `json:"log.retention.hours,omitempty"`
+	LogRetentionBytes           *int           `json:"log.retention.bytes,omitempty"`
+	LogSegmentBytes             *int           `json:"log.segment.bytes,omitempty"`
+	NumPartitions               *int           `json:"num.partitions,omitempty"`
+	MessageMaxBytes             *int           `json:"message.max.bytes,omitempty"`
+	CompressionType             *string        `json:"compression.type,omitempty"`
+	LogCleanupPolicy            *string        `json:"log.cleanup.policy,omitempty"`
+	AutoCreateTopicsEnable      *bool          `json:"auto.create.topics.enable,omitempty"`
+	DeleteTopicEnable           *bool          `json:"delete.topic.enable,omitempty"`
+	UncleanLeaderElectionEnable *bool          `json:"unclean.leader.election.enable,omitempty"`
+	LogMessageTimestampType     *string        `json:"log.message.timestamp.type,omitempty"`
+}
+
+// Compile-time checks that typed configurations implement EngineConfig.
+var (
+	_ EngineConfig = PostgreSQLConfig{}
+	_ EngineConfig = MySQLConfig{}
+	_ EngineConfig = RedisConfig{}
+	_ EngineConfig = KafkaConfig{}
+)
+
+// Map returns the configuration as a map of parameter names to values.
+func (c PostgreSQLConfig) Map() map[string]any {
+	return configToMap(c, c.Extra)
+}
+
+// PostgreSQLConfigFromMap converts the configuration map, e.g. Datastore.Config, to PostgreSQLConfig.
+func PostgreSQLConfigFromMap(values map[string]any) (PostgreSQLConfig, error) {
+	var config PostgreSQLConfig
+	extra, err := configFromMap(values, &config)
+	if err != nil {
+		return PostgreSQLConfig{}, err
+	}
+	config.Extra = extra
+
+	return config, nil
+}
+
+// Map returns the configuration as a map of parameter names to values.
+func (c MySQLConfig) Map() map[string]any {
+	return configToMap(c, c.Extra)
+}
+
+// MySQLConfigFromMap converts the configuration map, e.g. Datastore.Config, to MySQLConfig.
+func MySQLConfigFromMap(values map[string]any) (MySQLConfig, error) {
+	var config MySQLConfig
+	extra, err := configFromMap(values, &config)
+	if err != nil {
+		return MySQLConfig{}, err
+	}
+	config.Extra = extra
+
+	return config, nil
+}
+
+// Map returns the configuration as a map of parameter names to values.
+func (c RedisConfig) Map() map[string]any {
+	return configToMap(c, c.Extra)
+}
+
+// RedisConfigFromMap converts the configuration map, e.g. Datastore.Config, to RedisConfig.
+func RedisConfigFromMap(values map[string]any) (RedisConfig, error) {
+	var config RedisConfig
+	extra, err := configFromMap(values, &config)
+	if err != nil {
+		return RedisConfig{}, err
+	}
+	config.Extra = extra
+
+	return config, nil
+}
+
+// Map returns the configuration as a map of parameter names to values.
+func (c KafkaConfig) Map() map[string]any {
+	return configToMap(c, c.Extra)
+}
+
+// KafkaConfigFromMap converts the configuration map, e.g. Datastore.Config, to KafkaConfig.
+func KafkaConfigFromMap(values map[string]any) (KafkaConfig, error) {
+	var config KafkaConfig
+	extra, err := configFromMap(values, &config)
+	if err != nil {
+		return KafkaConfig{}, err
+	}
+	config.Extra = extra
+
+	return config, nil
+}
+
+// configFields returns pointer fields of the typed configuration by parameter names.
+func configFields(config reflect.Value) map[string]reflect.Value {
+	fields := make(map[string]reflect.Value)
+	configType := config.Type()
+	for i := 0; i < configType.NumField(); i++ {
+		name, _, _ := strings.Cut(configType.Field(i).Tag.Get("json"), ",")
+		if name == "" || name == "-" || configType.Field(i).Type.Kind() != reflect.Pointer {
+			continue
+		}
+		fields[name] = config.Field(i)
+	}
+	return fields
+}
+
+// configToMap converts the typed configuration to the map, extra parameters are copied as is.
+func configToMap(config any, extra map[string]any) map[string]any {
+	values := make(map[string]any, len(extra))
+	for name, value := range extra {
+		values[name] = value
+	}
+	for name, field := range configFields(reflect.ValueOf(config)) {
+		if !field.IsNil() {
+			values[name] = field.Elem().Interface()
+		}
+	}
+	return values
+}
+
+// configFromMap fills fields of the typed configuration from the map.
+// It returns parameters that don't have a field, along with parameters with null values,
+// which reset parameters to their defaults.
+func configFromMap(values map[string]any, config any) (map[string]any, error) {
+	fields := configFields(reflect.ValueOf(config).Elem())
+	var extra map[string]any
+	for name, value := range values {
+		field, ok := fields[name]
+		if !ok || value == nil {
+			if extra == nil {
+				extra = make(map[string]any)
+			}
+			extra[name] = value
+			continue
+		}
+
+		body, err := json.Marshal(value)
+		if err != nil {
+			return nil, fmt.Errorf("Error marshalling config parameter %s, %w", name, err)
+		}
+		fieldValue := reflect.New(field.Type().Elem())
+		if err := json.Unmarshal(body, fieldValue.Interface()); err != nil {
+			return nil, fmt.Errorf("Error during Unmarshal of config parameter %s, %w", name, err)
+		}
+		field.Set(fieldValue)
+	}
+	return extra, nil
+}
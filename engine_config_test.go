@@ -0,0 +1,111 @@
+package dbaas
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPostgreSQLConfigFromMap(t *testing.T) {
+	var datastore Datastore
+	err := json.Unmarshal([]byte(`{
+		"config": {
+			"work_mem": 8192,
+			"random_page_cost": 1.1,
+			"jit": false,
+			"session_replication_role": "replica",
+			"new_parameter": "value",
+			"max_connections": null
+		}
+	}`), &datastore)
+	require.NoError(t, err)
+
+	config, err := PostgreSQLConfigFromMap(datastore.Config)
+	require.NoError(t, err)
+
+	require.NotNil(t, config.WorkMem)
+	assert.Equal(t, 8192, *config.WorkMem)
+	require.NotNil(t, config.RandomPageCost)
+	assert.Equal(t, 1.1, *config.RandomPageCost)
+	require.NotNil(t, config.JIT)
+	assert.False(t, *config.JIT)
+	require.NotNil(t, config.SessionReplicationRole)
+	assert.Equal(t, "replica", *config.SessionReplicationRole)
+	assert.Nil(t, config.MaxConnections)
+	assert.Equal(t, map[string]any{"new_parameter": "value", "max_connections": nil}, config.Extra)
+
+	assert.Equal(t, map[string]any{
+		"work_mem":                 8192,
+		"random_page_cost":         1.1,
+		"jit":                      false,
+		"session_replication_role": "replica",
+		"new_parameter":            "value",
+		"max_connections":          nil,
+	}, config.Map())
+}
+
+func TestPostgreSQLConfigFromMapInvalidType(t *testing.T) {
+	_, err := PostgreSQLConfigFromMap(map[string]any{"work_mem": "a lot"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "work_mem")
+
+	_, err = PostgreSQLConfigFromMap(map[string]any{"max_connections": 1.5})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "max_connections")
+}
+
+func TestEngineConfigMap(t *testing.T) {
+	maxConnections := 200
+	policy := "allkeys-lru"
+	retention := 24
+	autoCreate := false
+
+	tests := []struct {
+		config   EngineConfig
+		expected map[string]any
+	}{
+		{
+			config:   MySQLConfig{MaxConnections: &maxConnections},
+			expected: map[string]any{"max_connections": 200},
+		},
+		{
+			config:   RedisConfig{MaxmemoryPolicy: &policy, Extra: map[string]any{"hz": 10}},
+			expected: map[string]any{"maxmemory-policy": "allkeys-lru", "hz": 10},
+		},
+		{
+			config:   KafkaConfig{LogRetentionHours: &retention, AutoCreateTopicsEnable: &autoCreate},
+			expected: map[string]any{"log.retention.hours": 24, "auto.create.topics.enable": false},
+		},
+		{
+			config:   PostgreSQLConfig{},
+			expected: map[string]any{},
+		},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.expected, test.config.Map())
+	}
+}
+
+func TestEngineConfigRoundTrip(t *testing.T) {
+	values := map[string]any{
+		"innodb_buffer_pool_size": 134217728,
+		"sql_mode":                "STRICT_TRANS_TABLES",
+		"long_query_time":         2.5,
+		"unknown":                 []any{"a", "b"},
+	}
+
+	mysql, err := MySQLConfigFromMap(values)
+	require.NoError(t, err)
+	assert.Equal(t, values, mysql.Map())
+
+	redis, err := RedisConfigFromMap(map[string]any{"timeout": 300.0})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"timeout": 300}, redis.Map())
+
+	kafka, err := KafkaConfigFromMap(nil)
+	require.NoError(t, err)
+	assert.Empty(t, kafka.Map())
+}
@@ -1,7 +1,9 @@
 package dbaas
 
 import (
+	"errors"
 	"fmt"
+	"net/http"
 )
 
 // Error titles.
@@ -10,13 +12,38 @@ const (
 	ErrorBadRequestTitle = "Bad Request"
 )
 
+// Sentinel errors that can be matched with errors.Is against errors returned by API calls.
+var (
+	ErrBadRequest   = errors.New("bad request")
+	ErrUnauthorized = errors.New("unauthorized")
+	ErrForbidden    = errors.New("forbidden")
+	ErrNotFound     = errors.New("not found")
+	ErrConflict     = errors.New("conflict")
+	ErrRateLimited  = errors.New("rate limited")
+	ErrServerError  = errors.New("server error")
+)
+
 // DBaaSAPIError is a type of an error raised by API calls made by this library.
+// It is returned for every response with 4xx or 5xx status code.
 type DBaaSAPIError struct {
 	APIError struct {
 		Message string `json:"message"`
 		Title   string `json:"title"`
 		Code    int    `json:"code"`
 	} `json:"error"`
+
+	// Method and URI describe the failed request.
+	Method string `json:"-"`
+	URI    string `json:"-"`
+
+	// RequestID is an identifier of the request assigned by the service.
+	RequestID string `json:"-"`
+
+	// Body is the raw response body.
+	Body []byte `json:"-"`
+
+	// HTTPStatus is the HTTP status code of the response.
+	HTTPStatus int `json:"-"`
 }
 
 // Error returns string representation of the error.
@@ -26,5 +53,41 @@ func (e DBaaSAPIError) Error() string {
 
 // StatusCode returns the HTTP status from the error response.
 func (e DBaaSAPIError) StatusCode() int {
+	if e.HTTPStatus != 0 {
+		return e.HTTPStatus
+	}
 	return e.APIError.Code
 }
+
+// Is reports whether the error matches one of the sentinel errors.
+func (e DBaaSAPIError) Is(target error) bool {
+	statusCode := e.StatusCode()
+	switch target {
+	case ErrBadRequest:
+		return statusCode == http.StatusBadRequest
+	case ErrUnauthorized:
+		return statusCode == http.StatusUnauthorized
+	case ErrForbidden:
+		return statusCode == http.StatusForbidden
+	case ErrNotFound:
+		return statusCode == http.StatusNotFound
+	case ErrConflict:
+		return statusCode == http.StatusConflict
+	case ErrRateLimited:
+		return statusCode == http.StatusTooManyRequests
+	case ErrServerError:
+		return statusCode >= http.StatusInternalServerError
+	default:
+		return false
+	}
+}
+
+// IsRetryable checks if the error is caused by a transient failure
+// and the request can be repeated later.
+func IsRetryable(err error) bool {
+	var apiErr *DBaaSAPIError
+	if errors.As(err, &apiErr) {
+		return isRetryableStatusCode(apiErr.StatusCode())
+	}
+	return isConnectionReset(err)
+}
@@ -0,0 +1,81 @@
+package dbaas
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"syscall"
+	"testing"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestErrorIsSentinel(t *testing.T) {
+	httpmock.Activate()
+	testClient := SetupTestClient()
+	defer httpmock.DeactivateAndReset()
+
+	notFoundResponse := fmt.Sprintf(testUserNotFoundResponse, NotFoundEntityID)
+	httpmock.RegisterResponder("GET", testClient.Endpoint+UsersURI+"/"+NotFoundEntityID,
+		func(req *http.Request) (*http.Response, error) {
+			resp := httpmock.NewStringResponse(404, notFoundResponse)
+			resp.Header.Set("X-Request-Id", "req-42")
+			return resp, nil
+		})
+
+	_, err := testClient.User(context.Background(), NotFoundEntityID)
+
+	require.ErrorIs(t, err, ErrNotFound)
+	assert.NotErrorIs(t, err, ErrBadRequest)
+	assert.False(t, IsRetryable(err))
+
+	var apiErr *DBaaSAPIError
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusNotFound, apiErr.StatusCode())
+	assert.Equal(t, http.MethodGet, apiErr.Method)
+	assert.Equal(t, UsersURI+"/"+NotFoundEntityID, apiErr.URI)
+	assert.Equal(t, "req-42", apiErr.RequestID)
+	assert.Equal(t, notFoundResponse, string(apiErr.Body))
+	assert.Equal(t, ErrorNotFoundTitle, apiErr.APIError.Title)
+}
+
+func TestErrorServerFailure(t *testing.T) {
+	httpmock.Activate()
+	testClient := SetupTestClient()
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder("GET", testClient.Endpoint+UsersURI+"/"+userID,
+		httpmock.NewStringResponder(503, "upstream is unavailable"))
+
+	_, err := testClient.User(context.Background(), userID)
+
+	require.ErrorIs(t, err, ErrServerError)
+	assert.True(t, IsRetryable(err))
+	assert.EqualError(t, err, "Service Unavailable: upstream is unavailable. Code: 503")
+}
+
+func TestErrorSentinels(t *testing.T) {
+	for statusCode, sentinel := range map[int]error{
+		http.StatusBadRequest:          ErrBadRequest,
+		http.StatusUnauthorized:        ErrUnauthorized,
+		http.StatusForbidden:           ErrForbidden,
+		http.StatusNotFound:            ErrNotFound,
+		http.StatusConflict:            ErrConflict,
+		http.StatusTooManyRequests:     ErrRateLimited,
+		http.StatusInternalServerError: ErrServerError,
+		http.StatusGatewayTimeout:      ErrServerError,
+	} {
+		err := fmt.Errorf("wrapped: %w", &DBaaSAPIError{HTTPStatus: statusCode})
+		assert.ErrorIs(t, err, sentinel, statusCode)
+	}
+}
+
+func TestIsRetryable(t *testing.T) {
+	assert.True(t, IsRetryable(&DBaaSAPIError{HTTPStatus: http.StatusTooManyRequests}))
+	assert.True(t, IsRetryable(&DBaaSAPIError{HTTPStatus: http.StatusBadGateway}))
+	assert.False(t, IsRetryable(&DBaaSAPIError{HTTPStatus: http.StatusInternalServerError}))
+	assert.True(t, IsRetryable(fmt.Errorf("HTTP request failed, %w", syscall.ECONNRESET)))
+	assert.False(t, IsRetryable(context.Canceled))
+}
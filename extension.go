@@ -19,6 +19,11 @@ type Extension struct {
 	Status               Status `json:"status"`
 }
 
+// ResourceStatus returns the extension status.
+func (e Extension) ResourceStatus() Status {
+	return e.Status
+}
+
 // ExtensionCreateOpts represents options for the extension Create request.
 type ExtensionCreateOpts struct {
 	AvailableExtensionID string `json:"available_extension_id"`
@@ -34,6 +39,7 @@ type ExtensionQueryParams struct {
 	DatastoreID          string `json:"datastore_id,omitempty"`
 	DatabaseID           string `json:"database_id,omitempty"`
 	Status               Status `json:"status,omitempty"`
+	Page
 }
 
 const ExtensionsURI = "/extensions"
@@ -83,6 +89,9 @@ func (api *API) Extension(ctx context.Context, extensionID string) (Extension, e
 
 // CreateExtension creates a new extension.
 func (api *API) CreateExtension(ctx context.Context, opts ExtensionCreateOpts) (Extension, error) {
+	if err := api.guardEngine(ctx, opts.DatastoreID, CapabilityExtensions); err != nil {
+		return Extension{}, err
+	}
 	createExtensionOpts := struct {
 		Extension ExtensionCreateOpts `json:"extension"`
 	}{
@@ -120,3 +129,26 @@ func (api *API) DeleteExtension(ctx context.Context, extensionID string) error {
 
 	return nil
 }
+
+// CreateExtensionAndWait creates a new extension and waits until it becomes active.
+func (api *API) CreateExtensionAndWait(
+	ctx context.Context,
+	opts ExtensionCreateOpts,
+	waitOpts *WaitOpts,
+) (Extension, error) {
+	extension, err := api.CreateExtension(ctx, opts)
+	if err != nil {
+		return Extension{}, err
+	}
+
+	return WaitFor(ctx, api.Extension, extension.ID, []Status{StatusActive}, waitOpts)
+}
+
+// DeleteExtensionAndWait deletes an existing extension and waits until it disappears.
+func (api *API) DeleteExtensionAndWait(ctx context.Context, extensionID string, waitOpts *WaitOpts) error {
+	if err := api.DeleteExtension(ctx, extensionID); err != nil {
+		return err
+	}
+
+	return WaitForDeleted(ctx, api.Extension, extensionID, waitOpts)
+}
@@ -26,11 +26,22 @@ type FlavorResponse struct {
 	Disk             int         `json:"disk"`
 }
 
+// FlavorQueryParams represents available query parameters for flavor.
+type FlavorQueryParams struct {
+	DatastoreTypeID string `json:"datastore_type_id,omitempty"`
+	FlSize          string `json:"fl_size,omitempty"`
+}
+
 const FlavorsURI = "/flavors"
 
-// Flavors returns all flavors.
-func (api *API) Flavors(ctx context.Context) ([]FlavorResponse, error) {
-	resp, err := api.makeRequest(ctx, http.MethodGet, FlavorsURI, nil)
+// Flavors returns all flavors or the flavors that match the optional params.
+func (api *API) Flavors(ctx context.Context, params ...*FlavorQueryParams) ([]FlavorResponse, error) {
+	uri, err := setQueryParams(FlavorsURI, optionalParams(params))
+	if err != nil {
+		return []FlavorResponse{}, err
+	}
+
+	resp, err := api.makeRequest(ctx, http.MethodGet, uri, nil)
 	if err != nil {
 		return []FlavorResponse{}, err
 	}
@@ -0,0 +1,161 @@
+package dbaas
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ErrNoFlavor is returned by SelectFlavor if no flavor matches the requirements.
+var ErrNoFlavor = errors.New("no flavor matches the requirements")
+
+// FlavorRequirements represents requirements for the flavor selection.
+// Zero values don't restrict the selection.
+type FlavorRequirements struct {
+	// DatastoreTypeID requires the flavor to be available for the datastore type.
+	DatastoreTypeID string
+
+	// DiskType is the preferred disk type. Network disks are sized with DatastoreCreateOpts.Disk,
+	// so MinDisk is checked against the flavor disk only for the local disk.
+	DiskType DiskType
+
+	// HostLine requires the flavor to run on the host line, e.g. "L".
+	HostLine string
+
+	// HostProcessor requires the flavor to run on the processor, e.g. "Intel-Gold6240".
+	HostProcessor string
+
+	// MinVcpus is a minimum number of vCPUs.
+	MinVcpus int
+
+	// MinRAM is a minimum amount of RAM in MB.
+	MinRAM int
+
+	// MinDisk is a minimum size of the local disk in GB.
+	MinDisk int
+
+	// MinAvailable is a minimum number of hosts available for the flavor.
+	MinAvailable int
+}
+
+// flavorSizeOrder ranks the known flavor sizes from the cheapest one.
+func flavorSizeOrder(flSize string) int {
+	switch flSize {
+	case "small":
+		return 0
+	case "standard":
+		return 1
+	case "large":
+		return 2
+	default:
+		return 3
+	}
+}
+
+// mismatch returns an empty string if the flavor meets the requirements,
+// otherwise it returns the first requirement that the flavor doesn't meet.
+func (r FlavorRequirements) mismatch(flavor FlavorResponse) string {
+	if r.DatastoreTypeID != "" && !containsString(flavor.DatastoreTypeIDs, r.DatastoreTypeID) {
+		return "not available for datastore type " + r.DatastoreTypeID
+	}
+	if flavor.Vcpus < r.MinVcpus {
+		return fmt.Sprintf("less than %d vCPUs", r.MinVcpus)
+	}
+	if flavor.RAM < r.MinRAM {
+		return fmt.Sprintf("less than %d MB of RAM", r.MinRAM)
+	}
+	if (r.DiskType == "" || r.DiskType == DiskLocal) && flavor.Disk < r.MinDisk {
+		return fmt.Sprintf("less than %d GB of disk", r.MinDisk)
+	}
+	if r.HostLine != "" && (flavor.Host == nil || !strings.EqualFold(flavor.Host.Line, r.HostLine)) {
+		return "not on host line " + r.HostLine
+	}
+	if r.HostProcessor != "" && (flavor.Host == nil || !strings.EqualFold(flavor.Host.Processor, r.HostProcessor)) {
+		return "not on processor " + r.HostProcessor
+	}
+	if r.MinAvailable > 0 && (flavor.Host == nil || flavor.Host.AvailableCount < r.MinAvailable) {
+		return fmt.Sprintf("less than %d available hosts", r.MinAvailable)
+	}
+
+	return ""
+}
+
+// Flavor returns the resources of the selected flavor with the preferred disk type.
+func (r FlavorRequirements) Flavor(flavor FlavorResponse) Flavor {
+	result := flavor.Flavor()
+	result.DiskType = r.DiskType
+	return result
+}
+
+// SelectFlavor returns the cheapest flavor that meets the requirements. Flavors are ordered by FlSize
+// from "small" to "large" and then by vCPUs, RAM and disk. If no flavor matches, the error wraps
+// ErrNoFlavor and explains how many flavors failed each requirement.
+func SelectFlavor(flavors []FlavorResponse, requirements FlavorRequirements) (FlavorResponse, error) {
+	var matched []FlavorResponse
+	var reasons []string
+	mismatches := make(map[string]int)
+	for _, flavor := range flavors {
+		reason := requirements.mismatch(flavor)
+		if reason == "" {
+			matched = append(matched, flavor)
+			continue
+		}
+		if mismatches[reason] == 0 {
+			reasons = append(reasons, reason)
+		}
+		mismatches[reason]++
+	}
+
+	if len(matched) == 0 {
+		if len(flavors) == 0 {
+			return FlavorResponse{}, fmt.Errorf("%w: no flavors", ErrNoFlavor)
+		}
+		explanations := make([]string, 0, len(reasons))
+		for _, reason := range reasons {
+			noun := "flavors"
+			if mismatches[reason] == 1 {
+				noun = "flavor"
+			}
+			explanations = append(explanations, fmt.Sprintf("%d %s %s", mismatches[reason], noun, reason))
+		}
+		return FlavorResponse{}, fmt.Errorf("%w: %s", ErrNoFlavor, strings.Join(explanations, ", "))
+	}
+
+	sort.SliceStable(matched, func(i, j int) bool {
+		a, b := matched[i], matched[j]
+		if flavorSizeOrder(a.FlSize) != flavorSizeOrder(b.FlSize) {
+			return flavorSizeOrder(a.FlSize) < flavorSizeOrder(b.FlSize)
+		}
+		if a.Vcpus != b.Vcpus {
+			return a.Vcpus < b.Vcpus
+		}
+		if a.RAM != b.RAM {
+			return a.RAM < b.RAM
+		}
+		return a.Disk < b.Disk
+	})
+
+	return matched[0], nil
+}
+
+// SelectFlavor lists flavors and returns the cheapest one that meets the requirements.
+func (api *API) SelectFlavor(ctx context.Context, requirements FlavorRequirements) (FlavorResponse, error) {
+	flavors, err := api.Flavors(ctx, &FlavorQueryParams{DatastoreTypeID: requirements.DatastoreTypeID})
+	if err != nil {
+		return FlavorResponse{}, err
+	}
+
+	return SelectFlavor(flavors, requirements)
+}
+
+// containsString reports whether the slice contains the string.
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
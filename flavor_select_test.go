@@ -0,0 +1,99 @@
+package dbaas
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testSelectFlavors() []FlavorResponse {
+	typeIDs := []string{datastoreTypeID}
+	return []FlavorResponse{
+		{ID: "large", FlSize: "large", Vcpus: 8, RAM: 16384, Disk: 128, DatastoreTypeIDs: typeIDs},
+		{ID: "standard-4", FlSize: "standard", Vcpus: 4, RAM: 8192, Disk: 64, DatastoreTypeIDs: typeIDs},
+		{ID: "standard-2", FlSize: "standard", Vcpus: 2, RAM: 4096, Disk: 32, DatastoreTypeIDs: typeIDs},
+		{ID: "small", FlSize: "small", Vcpus: 1, RAM: 2048, Disk: 16, DatastoreTypeIDs: typeIDs},
+		{ID: "other-type", FlSize: "small", Vcpus: 1, RAM: 2048, Disk: 16, DatastoreTypeIDs: []string{"other"}},
+		{
+			ID: "dedicated", FlSize: "standard", Vcpus: 4, RAM: 8192, Disk: 64, DatastoreTypeIDs: typeIDs,
+			Host: &FlavorHost{Line: "L", Processor: "Intel-Gold6240", AvailableCount: 2},
+		},
+	}
+}
+
+func TestSelectFlavor(t *testing.T) {
+	tests := []struct {
+		name         string
+		expected     string
+		requirements FlavorRequirements
+	}{
+		{name: "cheapest", requirements: FlavorRequirements{DatastoreTypeID: datastoreTypeID}, expected: "small"},
+		{name: "resources", requirements: FlavorRequirements{MinVcpus: 2, MinRAM: 6000}, expected: "standard-4"},
+		{name: "disk", requirements: FlavorRequirements{MinDisk: 100}, expected: "large"},
+		{
+			name:         "network disk",
+			requirements: FlavorRequirements{MinDisk: 100, DiskType: DiskNetworkUltra},
+			expected:     "small",
+		},
+		{
+			name:         "host",
+			requirements: FlavorRequirements{HostLine: "l", HostProcessor: "Intel-Gold6240", MinAvailable: 2},
+			expected:     "dedicated",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			actual, err := SelectFlavor(testSelectFlavors(), test.requirements)
+
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, actual.ID)
+		})
+	}
+}
+
+func TestSelectFlavorNoMatch(t *testing.T) {
+	_, err := SelectFlavor(testSelectFlavors(), FlavorRequirements{
+		DatastoreTypeID: datastoreTypeID,
+		MinVcpus:        4,
+		MinAvailable:    3,
+	})
+
+	require.Error(t, err)
+	assert.ErrorIs(t, err, ErrNoFlavor)
+	assert.Equal(t, "no flavor matches the requirements: 3 flavors less than 3 available hosts, "+
+		"2 flavors less than 4 vCPUs, 1 flavor not available for datastore type "+datastoreTypeID,
+		err.Error())
+
+	_, err = SelectFlavor(nil, FlavorRequirements{})
+
+	assert.ErrorIs(t, err, ErrNoFlavor)
+}
+
+func TestFlavorRequirementsFlavor(t *testing.T) {
+	requirements := FlavorRequirements{DiskType: DiskNetworkUltra}
+
+	actual := requirements.Flavor(testSelectFlavors()[3])
+
+	assert.Equal(t, Flavor{DiskType: DiskNetworkUltra, Vcpus: 1, RAM: 2048, Disk: 16}, actual)
+}
+
+func TestAPISelectFlavor(t *testing.T) {
+	httpmock.Activate()
+	testClient := SetupTestClient()
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder("GET", testClient.Endpoint+FlavorsURI,
+		httpmock.NewStringResponder(200, testFlavorsResponse))
+
+	actual, err := testClient.SelectFlavor(context.Background(), FlavorRequirements{
+		DatastoreTypeID: "20d7bcf4-f8d6-4bf6-b8f6-46cb440a87f5",
+		MinRAM:          8192,
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, "flavor-3", actual.Name)
+}
@@ -120,6 +120,24 @@ func TestFlavors(t *testing.T) {
 	assert.Equal(t, expected, actual)
 }
 
+func TestFlavorsWithParams(t *testing.T) {
+	httpmock.Activate()
+	testClient := SetupTestClient()
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponderWithQuery("GET", testClient.Endpoint+FlavorsURI,
+		"datastore_type_id=20d7bcf4-f8d6-4bf6-b8f6-46cb440a87f4&fl_size=standard",
+		httpmock.NewStringResponder(200, testFlavorsResponse))
+
+	actual, err := testClient.Flavors(context.Background(), &FlavorQueryParams{
+		DatastoreTypeID: datastoreTypeID,
+		FlSize:          "standard",
+	})
+
+	require.NoError(t, err)
+	assert.Len(t, actual, 2)
+}
+
 func TestFlavor(t *testing.T) {
 	httpmock.Activate()
 	testClient := SetupTestClient()
@@ -26,6 +26,11 @@ type Grant struct {
 	Status      Status `json:"status"`
 }
 
+// ResourceStatus returns the grant status.
+func (g Grant) ResourceStatus() Status {
+	return g.Status
+}
+
 const GrantsURI = "/grants"
 
 // Grant returns a grant based on the ID.
@@ -48,9 +53,25 @@ func (api *API) Grant(ctx context.Context, grantID string) (Grant, error) {
 	return result.Grant, nil
 }
 
-// Grants returns all grants.
-func (api *API) Grants(ctx context.Context) ([]Grant, error) {
-	resp, err := api.makeRequest(ctx, http.MethodGet, GrantsURI, nil)
+// GrantQueryParams represents available query parameters for grant.
+type GrantQueryParams struct {
+	ID          string `json:"id,omitempty"`
+	ProjectID   string `json:"project_id,omitempty"`
+	DatastoreID string `json:"datastore_id,omitempty"`
+	DatabaseID  string `json:"database_id,omitempty"`
+	UserID      string `json:"user_id,omitempty"`
+	Status      Status `json:"status,omitempty"`
+	Page
+}
+
+// Grants returns all grants or the grants that match the optional params.
+func (api *API) Grants(ctx context.Context, params ...*GrantQueryParams) ([]Grant, error) {
+	uri, err := setQueryParams(GrantsURI, optionalParams(params))
+	if err != nil {
+		return []Grant{}, err
+	}
+
+	resp, err := api.makeRequest(ctx, http.MethodGet, uri, nil)
 	if err != nil {
 		return []Grant{}, err
 	}
@@ -68,6 +89,9 @@ func (api *API) Grants(ctx context.Context) ([]Grant, error) {
 
 // CreateGrant creates a new grant.
 func (api *API) CreateGrant(ctx context.Context, opts GrantCreateOpts) (Grant, error) {
+	if err := api.guardEngine(ctx, opts.DatastoreID, CapabilityGrants); err != nil {
+		return Grant{}, err
+	}
 	createGrantOpts := struct {
 		Grant GrantCreateOpts `json:"grant"`
 	}{
@@ -105,3 +129,22 @@ func (api *API) DeleteGrant(ctx context.Context, grantID string) error {
 
 	return nil
 }
+
+// CreateGrantAndWait creates a new grant and waits until it becomes active.
+func (api *API) CreateGrantAndWait(ctx context.Context, opts GrantCreateOpts, waitOpts *WaitOpts) (Grant, error) {
+	grant, err := api.CreateGrant(ctx, opts)
+	if err != nil {
+		return Grant{}, err
+	}
+
+	return WaitFor(ctx, api.Grant, grant.ID, []Status{StatusActive}, waitOpts)
+}
+
+// DeleteGrantAndWait deletes an existing grant and waits until it disappears.
+func (api *API) DeleteGrantAndWait(ctx context.Context, grantID string, waitOpts *WaitOpts) error {
+	if err := api.DeleteGrant(ctx, grantID); err != nil {
+		return err
+	}
+
+	return WaitForDeleted(ctx, api.Grant, grantID, waitOpts)
+}
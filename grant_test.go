@@ -99,6 +99,24 @@ func TestGrants(t *testing.T) {
 	assert.Equal(t, expected, actual)
 }
 
+func TestGrantsWithParams(t *testing.T) {
+	httpmock.Activate()
+	testClient := SetupTestClient()
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponderWithQuery("GET", testClient.Endpoint+GrantsURI,
+		"datastore_id=20d7bcf4-f8d6-4bf6-b8f6-46cb440a87f4&user_id=20d7bcf4-f8d6-4bf6-b8f6-46cb440a87f4",
+		httpmock.NewStringResponder(200, testGrantsResponse))
+
+	actual, err := testClient.Grants(context.Background(), &GrantQueryParams{
+		DatastoreID: datastoreID,
+		UserID:      userID,
+	})
+
+	require.NoError(t, err)
+	assert.Len(t, actual, 2)
+}
+
 func TestGrant(t *testing.T) {
 	httpmock.Activate()
 	testClient := SetupTestClient()
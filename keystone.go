@@ -0,0 +1,167 @@
+package dbaas
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gophercloud/gophercloud"
+	"github.com/gophercloud/gophercloud/openstack"
+	tokens2 "github.com/gophercloud/gophercloud/openstack/identity/v2/tokens"
+	tokens3 "github.com/gophercloud/gophercloud/openstack/identity/v3/tokens"
+)
+
+// defaultTokenRefreshWindow specifies how long before the expiration a Keystone token is renewed.
+const defaultTokenRefreshWindow = 5 * time.Minute
+
+// ExpirableTokenSource is a TokenSource which tokens can be rejected by the caller.
+// The API expires a token after it gets 401 response and repeats the request once with a new token.
+type ExpirableTokenSource interface {
+	TokenSource
+
+	// Expire marks the given token as expired, so the next call to Token obtains a new one.
+	// Tokens that are not current anymore are ignored.
+	Expire(token string)
+}
+
+// KeystoneTokenSource is a TokenSource that obtains tokens from OpenStack Keystone
+// and re-authenticates before they expire.
+// Any authentication method supported by gophercloud.AuthOptions can be used:
+// password, application credential or service user scoped to a project.
+type KeystoneTokenSource struct {
+	provider  *gophercloud.ProviderClient
+	expiresAt time.Time
+	authOpts  gophercloud.AuthOptions
+	token     string
+
+	// RefreshWindow specifies how long before the expiration the token is renewed.
+	RefreshWindow time.Duration
+
+	mu sync.Mutex
+}
+
+var _ ExpirableTokenSource = (*KeystoneTokenSource)(nil)
+
+// NewKeystoneTokenSource creates a new Keystone token source.
+// If HTTP client is nil - default HTTP client will be used.
+// Authentication happens on the first call to Token.
+func NewKeystoneTokenSource(authOpts gophercloud.AuthOptions, httpClient *http.Client) (*KeystoneTokenSource, error) {
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+
+	provider, err := openstack.NewClient(authOpts.IdentityEndpoint)
+	if err != nil {
+		return nil, fmt.Errorf("could not create openstack client, %w", err)
+	}
+	provider.HTTPClient = *httpClient
+
+	// Re-authentication is handled by the token source itself.
+	authOpts.AllowReauth = false
+
+	return &KeystoneTokenSource{
+		provider:      provider,
+		authOpts:      authOpts,
+		RefreshWindow: defaultTokenRefreshWindow,
+	}, nil
+}
+
+// Token returns a current Keystone token, re-authenticating if it is about to expire.
+func (s *KeystoneTokenSource) Token(ctx context.Context) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.valid() {
+		return s.token, nil
+	}
+	if err := s.authenticate(ctx); err != nil {
+		return "", err
+	}
+
+	return s.token, nil
+}
+
+// Expire marks the given token as expired, so the next call to Token re-authenticates.
+func (s *KeystoneTokenSource) Expire(token string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.token == token {
+		s.token = ""
+	}
+}
+
+// Endpoint locates a service endpoint in the Keystone catalog.
+// The trailing slash is removed, so the result can be passed to New as is.
+func (s *KeystoneTokenSource) Endpoint(ctx context.Context, region, serviceType string) (string, error) {
+	if _, err := s.Token(ctx); err != nil {
+		return "", err
+	}
+
+	endpointOpts := gophercloud.EndpointOpts{Region: region}
+	endpointOpts.ApplyDefaults(serviceType)
+	endpoint, err := s.provider.EndpointLocator(endpointOpts)
+	if err != nil {
+		return "", fmt.Errorf("could not locate an endpoint, %w", err)
+	}
+
+	return strings.TrimSuffix(endpoint, "/"), nil
+}
+
+// valid checks if the current token can be used without re-authentication.
+// Tokens with unknown expiration time are considered valid until they are expired explicitly.
+func (s *KeystoneTokenSource) valid() bool {
+	if s.token == "" {
+		return false
+	}
+	return s.expiresAt.IsZero() || time.Until(s.expiresAt) > s.RefreshWindow
+}
+
+// authenticate obtains a new token from Keystone.
+func (s *KeystoneTokenSource) authenticate(ctx context.Context) error {
+	s.provider.Context = ctx
+	defer func() { s.provider.Context = nil }()
+
+	if err := openstack.Authenticate(s.provider, s.authOpts); err != nil {
+		return fmt.Errorf("could not authenticate to openstack, %w", err)
+	}
+
+	expiresAt, err := tokenExpiration(s.provider.GetAuthResult())
+	if err != nil {
+		return fmt.Errorf("could not extract token expiration, %w", err)
+	}
+	s.token = s.provider.Token()
+	s.expiresAt = expiresAt
+
+	return nil
+}
+
+// tokenExpiration returns an expiration time of the token from the Keystone authentication result.
+// Zero time is returned if the result type is unknown.
+func tokenExpiration(result gophercloud.AuthResult) (time.Time, error) {
+	switch result := result.(type) {
+	case tokens3.CreateResult:
+		token, err := result.ExtractToken()
+		if err != nil {
+			return time.Time{}, err
+		}
+		return token.ExpiresAt, nil
+	case tokens3.GetResult:
+		token, err := result.ExtractToken()
+		if err != nil {
+			return time.Time{}, err
+		}
+		return token.ExpiresAt, nil
+	case tokens2.CreateResult:
+		token, err := result.ExtractToken()
+		if err != nil {
+			return time.Time{}, err
+		}
+		return token.ExpiresAt, nil
+	default:
+		return time.Time{}, nil
+	}
+}
@@ -0,0 +1,157 @@
+package dbaas
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gophercloud/gophercloud"
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testKeystoneEndpoint = "http://keystone/identity/v3/"
+
+const testKeystoneTokenResponse = `{
+	"token": {
+		"expires_at": "%s",
+		"catalog": [
+			{
+				"type": "dbaas",
+				"name": "dbaas",
+				"endpoints": [
+					{
+						"interface": "public",
+						"region_id": "ru-1",
+						"region": "ru-1",
+						"url": "https://ru-1.dbaas.selcloud.ru/v1"
+					}
+				]
+			}
+		]
+	}
+}`
+
+// setupKeystoneTokenSource creates a token source backed by a mocked Keystone,
+// which issues tokens "token-1", "token-2" and so on, valid for the given duration.
+func setupKeystoneTokenSource(t *testing.T, validFor time.Duration) (*KeystoneTokenSource, *httpmock.MockTransport) {
+	t.Helper()
+
+	transport := httpmock.NewMockTransport()
+	issued := 0
+	transport.RegisterResponder("POST", testKeystoneEndpoint+"auth/tokens",
+		func(req *http.Request) (*http.Response, error) {
+			issued++
+			expiresAt := time.Now().Add(validFor).UTC().Format(time.RFC3339)
+			resp := httpmock.NewStringResponse(201, fmt.Sprintf(testKeystoneTokenResponse, expiresAt))
+			resp.Header.Set("X-Subject-Token", fmt.Sprintf("token-%d", issued))
+			return resp, nil
+		})
+
+	authOpts := gophercloud.AuthOptions{
+		IdentityEndpoint: testKeystoneEndpoint,
+		Username:         "user",
+		Password:         "password",
+		DomainName:       "domain",
+	}
+	tokenSource, err := NewKeystoneTokenSource(authOpts, &http.Client{Transport: transport})
+	require.NoError(t, err)
+
+	return tokenSource, transport
+}
+
+func TestKeystoneTokenSourceCachesToken(t *testing.T) {
+	tokenSource, transport := setupKeystoneTokenSource(t, time.Hour)
+
+	token, err := tokenSource.Token(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "token-1", token)
+
+	token, err = tokenSource.Token(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "token-1", token)
+	assert.Equal(t, 1, transport.GetTotalCallCount())
+}
+
+func TestKeystoneTokenSourceRenewsTokenBeforeExpiration(t *testing.T) {
+	tokenSource, transport := setupKeystoneTokenSource(t, time.Minute)
+
+	token, err := tokenSource.Token(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "token-1", token)
+
+	token, err = tokenSource.Token(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "token-2", token)
+	assert.Equal(t, 2, transport.GetTotalCallCount())
+}
+
+func TestKeystoneTokenSourceExpire(t *testing.T) {
+	tokenSource, _ := setupKeystoneTokenSource(t, time.Hour)
+
+	token, err := tokenSource.Token(context.Background())
+	require.NoError(t, err)
+
+	tokenSource.Expire("stale-token")
+	actual, err := tokenSource.Token(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, token, actual)
+
+	tokenSource.Expire(token)
+	actual, err = tokenSource.Token(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "token-2", actual)
+}
+
+func TestKeystoneTokenSourceEndpoint(t *testing.T) {
+	tokenSource, _ := setupKeystoneTokenSource(t, time.Hour)
+
+	endpoint, err := tokenSource.Endpoint(context.Background(), "ru-1", "dbaas")
+
+	require.NoError(t, err)
+	assert.Equal(t, "https://ru-1.dbaas.selcloud.ru/v1", endpoint)
+}
+
+func TestRenewTokenOnUnauthorized(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	tokenSource, _ := setupKeystoneTokenSource(t, time.Hour)
+	testClient, err := New("http://localhost/v1", WithTokenSource(tokenSource))
+	require.NoError(t, err)
+
+	var tokens []string
+	httpmock.RegisterResponder("GET", testClient.Endpoint+UsersURI+"/"+userID,
+		func(req *http.Request) (*http.Response, error) {
+			tokens = append(tokens, req.Header.Get("X-Auth-Token"))
+			if len(tokens) == 1 {
+				return httpmock.NewStringResponse(401, ""), nil
+			}
+			return httpmock.NewStringResponse(200, testUserResponse), nil
+		})
+
+	_, err = testClient.User(context.Background(), userID)
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"token-1", "token-2"}, tokens)
+}
+
+func TestRenewTokenOnUnauthorizedOnlyOnce(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	tokenSource, _ := setupKeystoneTokenSource(t, time.Hour)
+	testClient, err := New("http://localhost/v1", WithTokenSource(tokenSource))
+	require.NoError(t, err)
+
+	httpmock.RegisterResponder("GET", testClient.Endpoint+UsersURI+"/"+userID,
+		httpmock.NewStringResponder(401, `{"error": {"code": 401, "title": "Unauthorized", "message": ""}}`))
+
+	_, err = testClient.User(context.Background(), userID)
+
+	require.Error(t, err)
+	assert.Equal(t, 2, httpmock.GetTotalCallCount())
+}
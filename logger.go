@@ -0,0 +1,61 @@
+package dbaas
+
+import (
+	"context"
+	"time"
+)
+
+// RequestInfo contains details of a single HTTP request attempt made by the API.
+// Request and response bodies are never included as they can contain passwords and tokens.
+type RequestInfo struct {
+	// Err is set if the request has failed before a response was received.
+	Err        error
+	Method     string
+	URI        string
+	StatusCode int
+	Attempt    int
+	Latency    time.Duration
+}
+
+// Fields returns request details as a list of alternating keys and values,
+// which can be passed to structured loggers such as log/slog.
+func (i RequestInfo) Fields() []any {
+	fields := []any{
+		"method", i.Method,
+		"uri", i.URI,
+		"status", i.StatusCode,
+		"latency", i.Latency,
+		"attempt", i.Attempt,
+	}
+	if i.Err != nil {
+		fields = append(fields, "error", i.Err)
+	}
+	return fields
+}
+
+// Logger receives details of every HTTP request attempt made by the API.
+type Logger interface {
+	LogRequest(ctx context.Context, info RequestInfo)
+}
+
+// LoggerFunc is an adapter to allow the use of ordinary functions as Logger.
+type LoggerFunc func(ctx context.Context, info RequestInfo)
+
+// LogRequest calls f(ctx, info).
+func (f LoggerFunc) LogRequest(ctx context.Context, info RequestInfo) {
+	f(ctx, info)
+}
+
+// noopLogger is used when no logger is set for the API.
+type noopLogger struct{}
+
+// LogRequest discards request details.
+func (noopLogger) LogRequest(context.Context, RequestInfo) {}
+
+// logger returns the API logger or a no-op logger if it is not set.
+func (api *API) logger() Logger {
+	if api.Logger == nil {
+		return noopLogger{}
+	}
+	return api.Logger
+}
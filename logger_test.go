@@ -0,0 +1,62 @@
+package dbaas
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoggerReceivesRequestAttempts(t *testing.T) {
+	httpmock.Activate()
+	testClient := SetupTestClient()
+	testClient.RetryPolicy = &RetryPolicy{MaxAttempts: 2, MinBackoff: time.Millisecond}
+	defer httpmock.DeactivateAndReset()
+
+	var logged []RequestInfo
+	testClient.Logger = LoggerFunc(func(_ context.Context, info RequestInfo) {
+		logged = append(logged, info)
+	})
+
+	httpmock.RegisterResponder("PUT", testClient.Endpoint+UsersURI+"/"+userID,
+		httpmock.NewStringResponder(503, "").Then(
+			httpmock.NewStringResponder(200, testUserResponse)))
+
+	_, err := testClient.UpdateUser(context.Background(), userID, UserUpdateOpts{Password: "secret"})
+
+	require.NoError(t, err)
+	require.Len(t, logged, 2)
+	assert.Equal(t, http.MethodPut, logged[0].Method)
+	assert.Equal(t, UsersURI+"/"+userID, logged[0].URI)
+	assert.Equal(t, 503, logged[0].StatusCode)
+	assert.Equal(t, 1, logged[0].Attempt)
+	assert.Equal(t, 200, logged[1].StatusCode)
+	assert.Equal(t, 2, logged[1].Attempt)
+	for _, info := range logged {
+		assert.NotContains(t, info.Fields(), "secret")
+	}
+}
+
+func TestRequestInfoFields(t *testing.T) {
+	info := RequestInfo{
+		Method:     http.MethodGet,
+		URI:        UsersURI,
+		StatusCode: 200,
+		Attempt:    1,
+		Latency:    time.Second,
+	}
+
+	expected := []any{
+		"method", http.MethodGet,
+		"uri", UsersURI,
+		"status", 200,
+		"latency", time.Second,
+		"attempt", 1,
+	}
+
+	assert.Equal(t, expected, info.Fields())
+}
@@ -19,6 +19,11 @@ type LogicalReplicationSlot struct {
 	Status      Status `json:"status"`
 }
 
+// ResourceStatus returns the slot status.
+func (s LogicalReplicationSlot) ResourceStatus() Status {
+	return s.Status
+}
+
 type LogicalReplicationSlotCreateOpts struct {
 	Name        string `json:"name"`
 	DatastoreID string `json:"datastore_id"`
@@ -32,6 +37,7 @@ type LogicalReplicationSlotQueryParams struct {
 	DatastoreID string `json:"datastore_id,omitempty"`
 	DatabaseID  string `json:"database_id,omitempty"`
 	Status      Status `json:"status,omitempty"`
+	Page
 }
 
 const LogicalReplicationSlotsURI = "/logical-replication-slots"
@@ -87,6 +93,9 @@ func (api *API) CreateLogicalReplicationSlot(
 	ctx context.Context,
 	opts LogicalReplicationSlotCreateOpts,
 ) (LogicalReplicationSlot, error) {
+	if err := api.guardEngine(ctx, opts.DatastoreID, CapabilityLogicalReplicationSlots); err != nil {
+		return LogicalReplicationSlot{}, err
+	}
 	createLogicalReplicationSlotsOpts := struct {
 		LogicalReplicationSlot LogicalReplicationSlotCreateOpts `json:"logical-replication-slot"`
 	}{
@@ -123,3 +132,26 @@ func (api *API) DeleteLogicalReplicationSlot(ctx context.Context, slotID string)
 
 	return nil
 }
+
+// CreateLogicalReplicationSlotAndWait creates a new slot and waits until it becomes active.
+func (api *API) CreateLogicalReplicationSlotAndWait(
+	ctx context.Context,
+	opts LogicalReplicationSlotCreateOpts,
+	waitOpts *WaitOpts,
+) (LogicalReplicationSlot, error) {
+	slot, err := api.CreateLogicalReplicationSlot(ctx, opts)
+	if err != nil {
+		return LogicalReplicationSlot{}, err
+	}
+
+	return WaitFor(ctx, api.LogicalReplicationSlot, slot.ID, []Status{StatusActive}, waitOpts)
+}
+
+// DeleteLogicalReplicationSlotAndWait deletes an existing slot and waits until it disappears.
+func (api *API) DeleteLogicalReplicationSlotAndWait(ctx context.Context, slotID string, waitOpts *WaitOpts) error {
+	if err := api.DeleteLogicalReplicationSlot(ctx, slotID); err != nil {
+		return err
+	}
+
+	return WaitForDeleted(ctx, api.LogicalReplicationSlot, slotID, waitOpts)
+}
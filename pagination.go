@@ -0,0 +1,282 @@
+package dbaas
+
+import (
+	"context"
+)
+
+// DefaultPageLimit is a number of objects requested per page by iterators if Page.Limit is not set.
+const DefaultPageLimit = 100
+
+// Page represents pagination parameters of list requests.
+// It is embedded into the query parameters of the collections.
+type Page struct {
+	// Marker is an ID of the last object of the previous page.
+	// Iterators started with a marker request the next pages by markers instead of offsets.
+	Marker string `json:"marker,omitempty"`
+
+	// Limit is a maximum number of objects on the page.
+	Limit int `json:"limit,omitempty"`
+
+	// Offset is a number of objects to skip.
+	Offset int `json:"offset,omitempty"`
+}
+
+// Iterator fetches objects of a collection page by page on demand:
+//
+//	it := dbaasClient.IterateDatastores(&dbaas.DatastoreQueryParams{Status: dbaas.StatusActive})
+//	for it.Next(ctx) {
+//		datastore := it.Value()
+//		...
+//	}
+//	if err := it.Err(); err != nil {
+//		...
+//	}
+//
+// An iterator must not be used concurrently.
+type Iterator[T any] struct {
+	fetch   func(ctx context.Context, page Page) ([]T, error)
+	id      func(object T) string
+	err     error
+	items   []T
+	current T
+	first   string
+	page    Page
+	marker  bool
+	done    bool
+}
+
+// newIterator returns an iterator that starts at the page.
+func newIterator[T any](
+	page Page,
+	id func(object T) string,
+	fetch func(ctx context.Context, page Page) ([]T, error),
+) *Iterator[T] {
+	if page.Limit <= 0 {
+		page.Limit = DefaultPageLimit
+	}
+
+	return &Iterator[T]{
+		fetch:  fetch,
+		id:     id,
+		page:   page,
+		marker: page.Marker != "",
+	}
+}
+
+// Next advances the iterator to the next object and fetches the next page if needed.
+// It returns false when the collection ends, the request fails or the context is done.
+func (it *Iterator[T]) Next(ctx context.Context) bool {
+	if it.err != nil {
+		return false
+	}
+	if err := ctx.Err(); err != nil {
+		it.err = err
+		return false
+	}
+
+	if len(it.items) == 0 {
+		if it.done {
+			return false
+		}
+		items, err := it.fetch(ctx, it.page)
+		if err != nil {
+			it.err = err
+			return false
+		}
+		if it.repeats(items) {
+			it.done = true
+			return false
+		}
+		it.advance(items)
+		if len(items) == 0 {
+			return false
+		}
+		it.items = items
+	}
+	it.current, it.items = it.items[0], it.items[1:]
+
+	return true
+}
+
+// repeats reports whether the page starts with the same object as the previous one.
+// It means that the server ignores the pagination parameters and all objects have been fetched already.
+func (it *Iterator[T]) repeats(items []T) bool {
+	if len(items) == 0 {
+		return false
+	}
+	first := it.id(items[0])
+	repeated := first != "" && first == it.first
+	it.first = first
+
+	return repeated
+}
+
+// advance moves the page forward after the fetched objects.
+// A page shorter than the limit is the last one. A page longer than the limit means that
+// the server doesn't paginate the collection and returned all objects.
+func (it *Iterator[T]) advance(items []T) {
+	if len(items) != it.page.Limit {
+		it.done = true
+		return
+	}
+	if it.marker {
+		marker := it.id(items[len(items)-1])
+		if marker == it.page.Marker {
+			it.done = true
+			return
+		}
+		it.page.Marker = marker
+	} else {
+		it.page.Offset += len(items)
+	}
+}
+
+// Value returns the current object.
+func (it *Iterator[T]) Value() T {
+	return it.current
+}
+
+// Err returns the error that stopped the iterator.
+func (it *Iterator[T]) Err() error {
+	return it.err
+}
+
+// Collect fetches all remaining objects. Objects fetched before an error are returned together with it.
+func (it *Iterator[T]) Collect(ctx context.Context) ([]T, error) {
+	result := []T{}
+	for it.Next(ctx) {
+		result = append(result, it.Value())
+	}
+
+	return result, it.Err()
+}
+
+// IterateDatastores returns an iterator over the datastores that match the params.
+func (api *API) IterateDatastores(params *DatastoreQueryParams) *Iterator[Datastore] {
+	query := DatastoreQueryParams{}
+	if params != nil {
+		query = *params
+	}
+
+	return newIterator(query.Page, func(d Datastore) string { return d.ID },
+		func(ctx context.Context, page Page) ([]Datastore, error) {
+			query.Page = page
+			return api.Datastores(ctx, &query)
+		})
+}
+
+// IterateUsers returns an iterator over the users that match the params.
+func (api *API) IterateUsers(params *UserQueryParams) *Iterator[User] {
+	query := UserQueryParams{}
+	if params != nil {
+		query = *params
+	}
+
+	return newIterator(query.Page, func(u User) string { return u.ID },
+		func(ctx context.Context, page Page) ([]User, error) {
+			query.Page = page
+			return api.Users(ctx, &query)
+		})
+}
+
+// IterateDatabases returns an iterator over the databases that match the params.
+func (api *API) IterateDatabases(params *DatabaseQueryParams) *Iterator[Database] {
+	query := DatabaseQueryParams{}
+	if params != nil {
+		query = *params
+	}
+
+	return newIterator(query.Page, func(d Database) string { return d.ID },
+		func(ctx context.Context, page Page) ([]Database, error) {
+			query.Page = page
+			return api.Databases(ctx, &query)
+		})
+}
+
+// IterateGrants returns an iterator over the grants that match the params.
+func (api *API) IterateGrants(params *GrantQueryParams) *Iterator[Grant] {
+	query := GrantQueryParams{}
+	if params != nil {
+		query = *params
+	}
+
+	return newIterator(query.Page, func(g Grant) string { return g.ID },
+		func(ctx context.Context, page Page) ([]Grant, error) {
+			query.Page = page
+			return api.Grants(ctx, &query)
+		})
+}
+
+// IterateACLs returns an iterator over the ACLs that match the params.
+func (api *API) IterateACLs(params *ACLQueryParams) *Iterator[ACL] {
+	query := ACLQueryParams{}
+	if params != nil {
+		query = *params
+	}
+
+	return newIterator(query.Page, func(a ACL) string { return a.ID },
+		func(ctx context.Context, page Page) ([]ACL, error) {
+			query.Page = page
+			return api.ACLs(ctx, &query)
+		})
+}
+
+// IterateTopics returns an iterator over the topics that match the params.
+func (api *API) IterateTopics(params *TopicQueryParams) *Iterator[Topic] {
+	query := TopicQueryParams{}
+	if params != nil {
+		query = *params
+	}
+
+	return newIterator(query.Page, func(t Topic) string { return t.ID },
+		func(ctx context.Context, page Page) ([]Topic, error) {
+			query.Page = page
+			return api.Topics(ctx, &query)
+		})
+}
+
+// IterateExtensions returns an iterator over the extensions that match the params.
+func (api *API) IterateExtensions(params *ExtensionQueryParams) *Iterator[Extension] {
+	query := ExtensionQueryParams{}
+	if params != nil {
+		query = *params
+	}
+
+	return newIterator(query.Page, func(e Extension) string { return e.ID },
+		func(ctx context.Context, page Page) ([]Extension, error) {
+			query.Page = page
+			return api.Extensions(ctx, &query)
+		})
+}
+
+// IterateLogicalReplicationSlots returns an iterator over the slots that match the params.
+func (api *API) IterateLogicalReplicationSlots(
+	params *LogicalReplicationSlotQueryParams,
+) *Iterator[LogicalReplicationSlot] {
+	query := LogicalReplicationSlotQueryParams{}
+	if params != nil {
+		query = *params
+	}
+
+	return newIterator(query.Page, func(s LogicalReplicationSlot) string { return s.ID },
+		func(ctx context.Context, page Page) ([]LogicalReplicationSlot, error) {
+			query.Page = page
+			return api.LogicalReplicationSlots(ctx, &query)
+		})
+}
+
+// IteratePrometheusMetricTokens returns an iterator over the tokens that match the params.
+func (api *API) IteratePrometheusMetricTokens(
+	params *PrometheusMetricTokenQueryParams,
+) *Iterator[PrometheusMetricToken] {
+	query := PrometheusMetricTokenQueryParams{}
+	if params != nil {
+		query = *params
+	}
+
+	return newIterator(query.Page, func(t PrometheusMetricToken) string { return t.ID },
+		func(ctx context.Context, page Page) ([]PrometheusMetricToken, error) {
+			query.Page = page
+			return api.PrometheusMetricTokens(ctx, &query)
+		})
+}
@@ -0,0 +1,198 @@
+package dbaas
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// paginatedDatastoresResponder serves the datastores with the marker, offset and limit parameters.
+// It records the query of every request.
+func paginatedDatastoresResponder(count int, paginate bool, queries *[]string) httpmock.Responder {
+	datastores := make([]Datastore, 0, count)
+	for i := 0; i < count; i++ {
+		datastores = append(datastores, Datastore{ID: fmt.Sprintf("datastore-%d", i)})
+	}
+
+	return func(req *http.Request) (*http.Response, error) {
+		query := req.URL.Query()
+		*queries = append(*queries, req.URL.RawQuery)
+		page := datastores
+		if paginate {
+			start, _ := strconv.Atoi(query.Get("offset"))
+			if marker := query.Get("marker"); marker != "" {
+				for i, datastore := range datastores {
+					if datastore.ID == marker {
+						start = i + 1
+					}
+				}
+			}
+			end := len(datastores)
+			if limit, _ := strconv.Atoi(query.Get("limit")); start+limit < end {
+				end = start + limit
+			}
+			if start > end {
+				start = end
+			}
+			page = datastores[start:end]
+		}
+		return httpmock.NewJsonResponse(200, map[string][]Datastore{"datastores": page})
+	}
+}
+
+func datastoreIDs(datastores []Datastore) []string {
+	ids := make([]string, 0, len(datastores))
+	for _, datastore := range datastores {
+		ids = append(ids, datastore.ID)
+	}
+	return ids
+}
+
+func TestIteratorOffset(t *testing.T) {
+	httpmock.Activate()
+	testClient := SetupTestClient()
+	defer httpmock.DeactivateAndReset()
+
+	var queries []string
+	httpmock.RegisterResponder("GET", testClient.Endpoint+DatastoresURI,
+		paginatedDatastoresResponder(5, true, &queries))
+
+	params := &DatastoreQueryParams{Status: StatusActive, Page: Page{Limit: 2}}
+	actual, err := testClient.IterateDatastores(params).Collect(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"datastore-0", "datastore-1", "datastore-2", "datastore-3", "datastore-4"},
+		datastoreIDs(actual))
+	assert.Equal(t, []string{
+		"limit=2&status=ACTIVE",
+		"limit=2&offset=2&status=ACTIVE",
+		"limit=2&offset=4&status=ACTIVE",
+	}, queries)
+	assert.Equal(t, Page{Limit: 2}, params.Page)
+}
+
+func TestIteratorMarker(t *testing.T) {
+	httpmock.Activate()
+	testClient := SetupTestClient()
+	defer httpmock.DeactivateAndReset()
+
+	var queries []string
+	httpmock.RegisterResponder("GET", testClient.Endpoint+DatastoresURI,
+		paginatedDatastoresResponder(5, true, &queries))
+
+	params := &DatastoreQueryParams{Page: Page{Limit: 2, Marker: "datastore-0"}}
+	actual, err := testClient.IterateDatastores(params).Collect(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"datastore-1", "datastore-2", "datastore-3", "datastore-4"}, datastoreIDs(actual))
+	assert.Equal(t, []string{
+		"limit=2&marker=datastore-0",
+		"limit=2&marker=datastore-2",
+		"limit=2&marker=datastore-4",
+	}, queries)
+}
+
+func TestIteratorWithoutServerPagination(t *testing.T) {
+	httpmock.Activate()
+	testClient := SetupTestClient()
+	defer httpmock.DeactivateAndReset()
+
+	var queries []string
+	httpmock.RegisterResponder("GET", testClient.Endpoint+DatastoresURI,
+		paginatedDatastoresResponder(5, false, &queries))
+
+	actual, err := testClient.IterateDatastores(&DatastoreQueryParams{Page: Page{Limit: 2}}).
+		Collect(context.Background())
+
+	require.NoError(t, err)
+	assert.Len(t, actual, 5)
+	assert.Len(t, queries, 1)
+}
+
+func TestIteratorWithoutServerPaginationFullPage(t *testing.T) {
+	httpmock.Activate()
+	testClient := SetupTestClient()
+	defer httpmock.DeactivateAndReset()
+
+	var queries []string
+	httpmock.RegisterResponder("GET", testClient.Endpoint+DatastoresURI,
+		paginatedDatastoresResponder(2, false, &queries))
+
+	for _, page := range []Page{{Limit: 2}, {Limit: 2, Marker: "datastore-0"}} {
+		queries = nil
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		actual, err := testClient.IterateDatastores(&DatastoreQueryParams{Page: page}).Collect(ctx)
+		cancel()
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{"datastore-0", "datastore-1"}, datastoreIDs(actual))
+		assert.Len(t, queries, 2)
+	}
+}
+
+func TestIteratorDefaultLimit(t *testing.T) {
+	httpmock.Activate()
+	testClient := SetupTestClient()
+	defer httpmock.DeactivateAndReset()
+
+	var queries []string
+	httpmock.RegisterResponder("GET", testClient.Endpoint+UsersURI,
+		httpmock.NewStringResponder(200, testUsersResponse))
+	httpmock.RegisterResponder("GET", testClient.Endpoint+DatastoresURI,
+		paginatedDatastoresResponder(DefaultPageLimit, true, &queries))
+
+	actual, err := testClient.IterateDatastores(nil).Collect(context.Background())
+
+	require.NoError(t, err)
+	assert.Len(t, actual, DefaultPageLimit)
+	assert.Equal(t, []string{"limit=100", "limit=100&offset=100"}, queries)
+
+	users, err := testClient.IterateUsers(nil).Collect(context.Background())
+
+	require.NoError(t, err)
+	assert.NotEmpty(t, users)
+}
+
+func TestIteratorContextCancel(t *testing.T) {
+	httpmock.Activate()
+	testClient := SetupTestClient()
+	defer httpmock.DeactivateAndReset()
+
+	var queries []string
+	httpmock.RegisterResponder("GET", testClient.Endpoint+DatastoresURI,
+		paginatedDatastoresResponder(5, true, &queries))
+	ctx, cancel := context.WithCancel(context.Background())
+	it := testClient.IterateDatastores(&DatastoreQueryParams{Page: Page{Limit: 2}})
+
+	require.True(t, it.Next(ctx))
+	assert.Equal(t, "datastore-0", it.Value().ID)
+	cancel()
+
+	assert.False(t, it.Next(ctx))
+	assert.True(t, errors.Is(it.Err(), context.Canceled))
+	assert.Len(t, queries, 1)
+}
+
+func TestIteratorError(t *testing.T) {
+	httpmock.Activate()
+	testClient := SetupTestClient()
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder("GET", testClient.Endpoint+DatastoresURI,
+		httpmock.NewStringResponder(200, `{"datastores": [{"id": "first"}]}`).
+			Then(httpmock.NewStringResponder(500, "")))
+
+	actual, err := testClient.IterateDatastores(&DatastoreQueryParams{Page: Page{Limit: 1}}).
+		Collect(context.Background())
+
+	require.Error(t, err)
+	assert.Equal(t, []string{"first"}, datastoreIDs(actual))
+}
@@ -0,0 +1,303 @@
+package dbaas
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	// defaultProbeTimeout specifies a default time limit of a single probe.
+	defaultProbeTimeout = 5 * time.Second
+
+	// defaultProbeConcurrency specifies a default number of probes running at the same time.
+	defaultProbeConcurrency = 4
+)
+
+// ErrProbeFailed is returned by ProbeReport.Err if some instances are not reachable.
+var ErrProbeFailed = errors.New("datastore is not reachable")
+
+// ProbeOpts represents options of the connectivity probe.
+type ProbeOpts struct {
+	// DialContext dials the instances, net.Dialer is used by default.
+	DialContext func(ctx context.Context, network, address string) (net.Conn, error)
+
+	// RootCA is a PEM bundle of the root CA that verifies TLS handshakes.
+	// It is required with TLS while the embedded bundle has no certificates, see RootCA.
+	RootCA []byte
+
+	// Timeout limits a single dial together with the TLS handshake.
+	Timeout time.Duration
+
+	// Concurrency limits the number of probes running at the same time.
+	Concurrency int
+
+	// Port overrides the default port of the engine.
+	Port int
+
+	// TLS performs a TLS handshake after the dial.
+	TLS bool
+}
+
+// ProbeResult is a result of probing an address of the instance.
+type ProbeResult struct {
+	// Err is nil if the address is reachable.
+	Err error `json:"-"`
+
+	Address string        `json:"address"`
+	Error   string        `json:"error,omitempty"`
+	Kind    AddressKind   `json:"kind"`
+	Latency time.Duration `json:"latency"`
+}
+
+// Reachable reports whether the probe succeeded.
+func (r ProbeResult) Reachable() bool {
+	return r.Err == nil
+}
+
+// InstanceProbe is a result of probing the private and the floating IP of the instance.
+type InstanceProbe struct {
+	InstanceID string        `json:"instance_id"`
+	Role       string        `json:"role"`
+	Hostname   string        `json:"hostname"`
+	Results    []ProbeResult `json:"results"`
+}
+
+// Reachable reports whether the instance is reachable by any of its addresses.
+func (p InstanceProbe) Reachable() bool {
+	for _, result := range p.Results {
+		if result.Reachable() {
+			return true
+		}
+	}
+	return false
+}
+
+// ProbeReport is a result of probing all instances of the datastore.
+type ProbeReport struct {
+	Instances []InstanceProbe `json:"instances"`
+}
+
+// Err returns ErrProbeFailed with the failed addresses if some instances are not reachable by any address.
+func (r ProbeReport) Err() error {
+	var failures []error
+	for _, instance := range r.Instances {
+		if instance.Reachable() {
+			continue
+		}
+		for _, result := range instance.Results {
+			failures = append(failures, fmt.Errorf("instance %s %s address %s: %w",
+				instance.InstanceID, result.Kind, result.Address, result.Err))
+		}
+		if len(instance.Results) == 0 {
+			failures = append(failures, fmt.Errorf("instance %s has no addresses", instance.InstanceID))
+		}
+	}
+	if len(failures) == 0 {
+		return nil
+	}
+
+	return fmt.Errorf("%w: %w", ErrProbeFailed, errors.Join(failures...))
+}
+
+// withDefaults returns a copy of options with default values for unset fields.
+func (opts *ProbeOpts) withDefaults() ProbeOpts {
+	result := ProbeOpts{}
+	if opts != nil {
+		result = *opts
+	}
+	if result.DialContext == nil {
+		result.DialContext = (&net.Dialer{}).DialContext
+	}
+	if result.Timeout <= 0 {
+		result.Timeout = defaultProbeTimeout
+	}
+	if result.Concurrency <= 0 {
+		result.Concurrency = defaultProbeConcurrency
+	}
+	return result
+}
+
+// Probe dials the private and the floating IPs of every instance on the port of the engine.
+// Probes run concurrently and the report lists instances in the order of Datastore.Instances.
+func (c ConnectionInfo) Probe(ctx context.Context, opts *ProbeOpts) ProbeReport {
+	probeOpts := opts.withDefaults()
+	port := probeOpts.Port
+	if port == 0 {
+		port = enginePort(c.Engine)
+	}
+
+	report := ProbeReport{Instances: make([]InstanceProbe, len(c.Instances))}
+	semaphore := make(chan struct{}, probeOpts.Concurrency)
+	var wg sync.WaitGroup
+	for i, instance := range c.Instances {
+		report.Instances[i] = InstanceProbe{
+			InstanceID: instance.ID,
+			Role:       instance.Role,
+			Hostname:   instance.Hostname,
+			Results:    []ProbeResult{},
+		}
+		addresses := []struct {
+			kind AddressKind
+			ip   string
+		}{{AddressPrivateIP, instance.PrivateIP}, {AddressFloatingIP, instance.FloatingIP}}
+		for _, address := range addresses {
+			if address.ip == "" {
+				continue
+			}
+			report.Instances[i].Results = append(report.Instances[i].Results, ProbeResult{
+				Address: net.JoinHostPort(address.ip, strconv.Itoa(port)),
+				Kind:    address.kind,
+			})
+		}
+
+		for j := range report.Instances[i].Results {
+			result := &report.Instances[i].Results[j]
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				semaphore <- struct{}{}
+				defer func() { <-semaphore }()
+
+				started := time.Now()
+				result.Err = c.probe(ctx, result.Address, probeOpts)
+				result.Latency = time.Since(started)
+				if result.Err != nil {
+					result.Error = result.Err.Error()
+				}
+			}()
+		}
+	}
+	wg.Wait()
+
+	return report
+}
+
+// ProbeDatastore loads the datastore with its datastore type and probes its instances.
+// It returns ErrNoRootCA without probing if TLS is requested and there is no root CA to verify handshakes.
+func (api *API) ProbeDatastore(ctx context.Context, datastoreID string, opts *ProbeOpts) (ProbeReport, error) {
+	if opts != nil && opts.TLS {
+		if _, err := RootCAPool(opts.RootCA); err != nil {
+			return ProbeReport{}, err
+		}
+	}
+	info, err := api.ConnectionInfo(ctx, datastoreID)
+	if err != nil {
+		return ProbeReport{}, err
+	}
+
+	return info.Probe(ctx, opts), nil
+}
+
+// probe dials the address and performs the TLS handshake if requested.
+func (c ConnectionInfo) probe(ctx context.Context, address string, opts ProbeOpts) error {
+	ctx, cancel := context.WithTimeout(ctx, opts.Timeout)
+	defer cancel()
+
+	conn, err := opts.DialContext(ctx, "tcp", address)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	if !opts.TLS {
+		return nil
+	}
+
+	if deadline, ok := ctx.Deadline(); ok {
+		if err := conn.SetDeadline(deadline); err != nil {
+			return err
+		}
+	}
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	config, err := c.TLSConfig(host, opts.RootCA)
+	if err != nil {
+		return err
+	}
+	if err := startTLS(conn, c.Engine); err != nil {
+		return err
+	}
+
+	return tls.Client(conn, config).HandshakeContext(ctx)
+}
+
+// startTLS asks PostgreSQL and MySQL servers to switch the connection to TLS.
+// Other engines accept TLS handshakes right away.
+func startTLS(conn net.Conn, engine string) error {
+	switch engine {
+	case EnginePostgreSQL:
+		return startPostgreSQLTLS(conn)
+	case EngineMySQL:
+		return startMySQLTLS(conn)
+	default:
+		return nil
+	}
+}
+
+// startPostgreSQLTLS sends SSLRequest and checks that the server accepts it.
+func startPostgreSQLTLS(conn net.Conn) error {
+	const sslRequestCode = 80877103
+
+	request := make([]byte, 8)
+	binary.BigEndian.PutUint32(request[0:4], 8)
+	binary.BigEndian.PutUint32(request[4:8], sslRequestCode)
+	if _, err := conn.Write(request); err != nil {
+		return fmt.Errorf("send SSLRequest: %w", err)
+	}
+
+	response := make([]byte, 1)
+	if _, err := io.ReadFull(conn, response); err != nil {
+		return fmt.Errorf("read SSLRequest response: %w", err)
+	}
+	if response[0] != 'S' {
+		return errors.New("server doesn't accept TLS connections")
+	}
+
+	return nil
+}
+
+// startMySQLTLS reads the server greeting and sends SSLRequest.
+func startMySQLTLS(conn net.Conn) error {
+	const (
+		errorPacket         = 0xff
+		clientProtocol41    = 0x00000200
+		clientSSL           = 0x00000800
+		clientSecureConnect = 0x00008000
+		maxPacketSize       = 1 << 24
+		charsetUTF8         = 33
+		sslRequestLength    = 32
+	)
+
+	header := make([]byte, 4)
+	if _, err := io.ReadFull(conn, header); err != nil {
+		return fmt.Errorf("read server greeting: %w", err)
+	}
+	greeting := make([]byte, int(header[0])|int(header[1])<<8|int(header[2])<<16)
+	if _, err := io.ReadFull(conn, greeting); err != nil {
+		return fmt.Errorf("read server greeting: %w", err)
+	}
+	if len(greeting) > 0 && greeting[0] == errorPacket {
+		return errors.New("server rejected the connection")
+	}
+
+	request := make([]byte, 4+sslRequestLength)
+	request[0] = sslRequestLength
+	request[3] = header[3] + 1
+	binary.LittleEndian.PutUint32(request[4:8], clientProtocol41|clientSSL|clientSecureConnect)
+	binary.LittleEndian.PutUint32(request[8:12], maxPacketSize)
+	request[12] = charsetUTF8
+	if _, err := conn.Write(request); err != nil {
+		return fmt.Errorf("send SSLRequest: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,224 @@
+package dbaas
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"io"
+	"net"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testListener accepts connections and passes them to the handler until the test ends.
+func testListener(t *testing.T, handle func(conn net.Conn)) int {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				handle(conn)
+			}()
+		}
+	}()
+
+	return listener.Addr().(*net.TCPAddr).Port
+}
+
+func testProbeInfo(engine string) ConnectionInfo {
+	return ConnectionInfo{
+		Engine: engine,
+		Instances: []InstanceAddress{
+			{ID: "master-id", Role: "MASTER", Hostname: "master-host", PrivateIP: "127.0.0.1", FloatingIP: "127.0.0.2"},
+			{ID: "replica-id", Role: "REPLICA", Hostname: "replica-host", PrivateIP: "127.0.0.3"},
+		},
+	}
+}
+
+func TestProbe(t *testing.T) {
+	port := testListener(t, func(conn net.Conn) {})
+
+	report := testProbeInfo("redis").Probe(context.Background(), &ProbeOpts{Port: port, Timeout: time.Second})
+
+	require.Len(t, report.Instances, 2)
+	master := report.Instances[0]
+	assert.Equal(t, "master-id", master.InstanceID)
+	require.Len(t, master.Results, 2)
+	assert.Equal(t, "127.0.0.1:"+strconv.Itoa(port), master.Results[0].Address)
+	assert.Equal(t, AddressPrivateIP, master.Results[0].Kind)
+	assert.True(t, master.Results[0].Reachable())
+	assert.Empty(t, master.Results[0].Error)
+	assert.Equal(t, AddressFloatingIP, master.Results[1].Kind)
+	assert.False(t, master.Results[1].Reachable())
+	assert.NotEmpty(t, master.Results[1].Error)
+	assert.True(t, master.Reachable())
+
+	replica := report.Instances[1]
+	require.Len(t, replica.Results, 1)
+	assert.False(t, replica.Reachable())
+
+	err := report.Err()
+	assert.ErrorIs(t, err, ErrProbeFailed)
+	assert.Contains(t, err.Error(), "instance replica-id private address 127.0.0.3")
+	assert.NotContains(t, err.Error(), "master-id")
+}
+
+func TestProbeTLS(t *testing.T) {
+	caPEM, certificate := testCertificates(t, "master-host")
+	serverConfig := &tls.Config{Certificates: []tls.Certificate{certificate}, MinVersion: tls.VersionTLS12}
+
+	tests := []struct {
+		handle func(conn net.Conn)
+		engine string
+	}{
+		{
+			engine: "redis",
+			handle: func(conn net.Conn) {
+				_ = tls.Server(conn, serverConfig).Handshake()
+			},
+		},
+		{
+			engine: "postgresql",
+			handle: func(conn net.Conn) {
+				request := make([]byte, 8)
+				if _, err := io.ReadFull(conn, request); err != nil {
+					return
+				}
+				if _, err := conn.Write([]byte("S")); err != nil {
+					return
+				}
+				_ = tls.Server(conn, serverConfig).Handshake()
+			},
+		},
+		{
+			engine: "mysql",
+			handle: func(conn net.Conn) {
+				if _, err := conn.Write([]byte{3, 0, 0, 0, 10, '8', 0}); err != nil {
+					return
+				}
+				request := make([]byte, 36)
+				if _, err := io.ReadFull(conn, request); err != nil || request[3] != 1 {
+					return
+				}
+				_ = tls.Server(conn, serverConfig).Handshake()
+			},
+		},
+	}
+
+	for _, test := range tests {
+		port := testListener(t, test.handle)
+		info := testProbeInfo(test.engine)
+		info.Instances = info.Instances[:1]
+		info.Instances[0].FloatingIP = ""
+
+		report := info.Probe(context.Background(), &ProbeOpts{
+			RootCA:  caPEM,
+			Timeout: time.Second,
+			Port:    port,
+			TLS:     true,
+		})
+
+		assert.NoError(t, report.Err(), test.engine)
+	}
+
+	otherCAPEM, _ := testCertificates(t, "master-host")
+	port := testListener(t, tests[0].handle)
+	info := testProbeInfo("redis")
+	info.Instances = info.Instances[:1]
+	info.Instances[0].FloatingIP = ""
+	opts := &ProbeOpts{RootCA: otherCAPEM, Timeout: time.Second, Port: port, TLS: true}
+
+	report := info.Probe(context.Background(), opts)
+
+	assert.ErrorIs(t, report.Err(), ErrProbeFailed)
+}
+
+func TestProbeTimeoutAndConcurrency(t *testing.T) {
+	var running, maxRunning int32
+	var mu sync.Mutex
+	var addresses []string
+	dial := func(ctx context.Context, network, address string) (net.Conn, error) {
+		current := atomic.AddInt32(&running, 1)
+		defer atomic.AddInt32(&running, -1)
+		mu.Lock()
+		addresses = append(addresses, address)
+		if current > maxRunning {
+			maxRunning = current
+		}
+		mu.Unlock()
+
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+
+	report := testProbeInfo("postgresql").Probe(context.Background(), &ProbeOpts{
+		DialContext: dial,
+		Timeout:     10 * time.Millisecond,
+		Concurrency: 1,
+	})
+
+	assert.ElementsMatch(t, []string{"127.0.0.1:5432", "127.0.0.2:5432", "127.0.0.3:5432"}, addresses)
+	assert.Equal(t, int32(1), maxRunning)
+	for _, instance := range report.Instances {
+		for _, result := range instance.Results {
+			assert.True(t, errors.Is(result.Err, context.DeadlineExceeded))
+		}
+	}
+}
+
+func TestProbeDatastore(t *testing.T) {
+	httpmock.Activate()
+	testClient := SetupTestClient()
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder("GET", testClient.Endpoint+DatastoresURI+"/"+datastoreID,
+		httpmock.NewStringResponder(200, testDatastoreResponse))
+	httpmock.RegisterResponder("GET", testClient.Endpoint+DatastoreTypesURI+"/"+datastoreTypeID,
+		httpmock.NewStringResponder(200, testDatastoreTypeResponse))
+
+	var mu sync.Mutex
+	var addresses []string
+	dial := func(ctx context.Context, network, address string) (net.Conn, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		addresses = append(addresses, address)
+		client, server := net.Pipe()
+		server.Close()
+		return client, nil
+	}
+
+	report, err := testClient.ProbeDatastore(context.Background(), datastoreID, &ProbeOpts{DialContext: dial})
+
+	require.NoError(t, err)
+	assert.NoError(t, report.Err())
+	assert.ElementsMatch(t, []string{"127.0.0.1:6033", "192.168.1.1:6033"}, addresses)
+}
+
+func TestProbeDatastoreWithoutRootCA(t *testing.T) {
+	httpmock.Activate()
+	testClient := SetupTestClient()
+	defer httpmock.DeactivateAndReset()
+
+	opts := &ProbeOpts{TLS: true, RootCA: []byte("not a certificate")}
+
+	_, err := testClient.ProbeDatastore(context.Background(), datastoreID, opts)
+
+	assert.ErrorIs(t, err, ErrNoRootCA)
+	assert.Equal(t, 0, httpmock.GetTotalCallCount())
+}
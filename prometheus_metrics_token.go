@@ -52,9 +52,25 @@ func (api *API) PrometheusMetricToken(
 	return result.PrometheusMetricToken, nil
 }
 
-// PrometheusMetricTokens returns all tokens.
-func (api *API) PrometheusMetricTokens(ctx context.Context) ([]PrometheusMetricToken, error) {
-	resp, err := api.makeRequest(ctx, http.MethodGet, PrometheusMetricsTokensURI, nil)
+// PrometheusMetricTokenQueryParams represents available query parameters for prometheus metrics token.
+type PrometheusMetricTokenQueryParams struct {
+	ID        string `json:"id,omitempty"`
+	ProjectID string `json:"project_id,omitempty"`
+	Name      string `json:"name,omitempty"`
+	Page
+}
+
+// PrometheusMetricTokens returns all tokens or the tokens that match the optional params.
+func (api *API) PrometheusMetricTokens(
+	ctx context.Context,
+	params ...*PrometheusMetricTokenQueryParams,
+) ([]PrometheusMetricToken, error) {
+	uri, err := setQueryParams(PrometheusMetricsTokensURI, optionalParams(params))
+	if err != nil {
+		return []PrometheusMetricToken{}, err
+	}
+
+	resp, err := api.makeRequest(ctx, http.MethodGet, uri, nil)
 	if err != nil {
 		return []PrometheusMetricToken{}, err
 	}
@@ -87,6 +87,23 @@ func TestPrometheusMetricTokens(t *testing.T) {
 	assert.Equal(t, expected, actual)
 }
 
+func TestPrometheusMetricTokensWithParams(t *testing.T) {
+	httpmock.Activate()
+	testClient := SetupTestClient()
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponderWithQuery("GET", testClient.Endpoint+PrometheusMetricsTokensURI,
+		"name=token",
+		httpmock.NewStringResponder(200, testPrometheusMetricTokensResponse))
+
+	actual, err := testClient.PrometheusMetricTokens(context.Background(), &PrometheusMetricTokenQueryParams{
+		Name: "token",
+	})
+
+	require.NoError(t, err)
+	assert.Len(t, actual, 2)
+}
+
 func TestPrometheusMetricToken(t *testing.T) {
 	httpmock.Activate()
 	testClient := SetupTestClient()
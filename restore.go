@@ -0,0 +1,122 @@
+package dbaas
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// RestoreTimeFormat is a format of the restore target time. Target time is sent in UTC.
+const RestoreTimeFormat = "2006-01-02T15:04:05"
+
+// ErrInvalidRestore is returned if the datastore can't be restored to the target time.
+var ErrInvalidRestore = errors.New("invalid restore")
+
+// RestoreDatastore creates a new datastore from the backups of the source datastore at the target time.
+// Type, flavor, subnet, node count, config, pooler and security groups are inherited from the source
+// unless they are set in the overrides. Name defaults to the source name with the "-restore" suffix.
+// The target time must be in the past and within the backup retention period of the source.
+func (api *API) RestoreDatastore(
+	ctx context.Context,
+	sourceID string,
+	targetTime time.Time,
+	overrides DatastoreCreateOpts,
+) (Datastore, error) {
+	source, err := api.Datastore(ctx, sourceID)
+	if err != nil {
+		return Datastore{}, err
+	}
+	opts, err := restoreCreateOpts(source, targetTime, time.Now(), overrides)
+	if err != nil {
+		return Datastore{}, err
+	}
+
+	return api.CreateDatastore(ctx, opts)
+}
+
+// RestoreDatastoreAndWait restores the datastore and waits until the new datastore becomes active.
+func (api *API) RestoreDatastoreAndWait(
+	ctx context.Context,
+	sourceID string,
+	targetTime time.Time,
+	overrides DatastoreCreateOpts,
+	waitOpts *WaitOpts,
+) (Datastore, error) {
+	datastore, err := api.RestoreDatastore(ctx, sourceID, targetTime, overrides)
+	if err != nil {
+		return Datastore{}, err
+	}
+
+	return api.WaitForDatastoreStatus(ctx, datastore.ID, []Status{StatusActive}, waitOpts)
+}
+
+// restoreCreateOpts checks that the source can be restored to the target time
+// and fills the options of the new datastore.
+func restoreCreateOpts(
+	source Datastore,
+	targetTime time.Time,
+	now time.Time,
+	overrides DatastoreCreateOpts,
+) (DatastoreCreateOpts, error) {
+	if !source.AllowRestore {
+		return DatastoreCreateOpts{}, fmt.Errorf("%w: datastore %s doesn't allow restore", ErrInvalidRestore, source.ID)
+	}
+	if targetTime.After(now) {
+		return DatastoreCreateOpts{}, fmt.Errorf("%w: target time %s is in the future",
+			ErrInvalidRestore, targetTime.UTC().Format(RestoreTimeFormat))
+	}
+	earliest := now.AddDate(0, 0, -source.BackupRetentionDays)
+	if targetTime.Before(earliest) {
+		return DatastoreCreateOpts{}, fmt.Errorf("%w: target time %s is earlier than %s, backups are kept for %d days",
+			ErrInvalidRestore, targetTime.UTC().Format(RestoreTimeFormat), earliest.UTC().Format(RestoreTimeFormat),
+			source.BackupRetentionDays)
+	}
+	if createdAt, err := time.Parse(RestoreTimeFormat, source.CreatedAt); err == nil && targetTime.Before(createdAt) {
+		return DatastoreCreateOpts{}, fmt.Errorf("%w: target time %s is earlier than the datastore creation at %s",
+			ErrInvalidRestore, targetTime.UTC().Format(RestoreTimeFormat), source.CreatedAt)
+	}
+
+	opts := inheritCreateOpts(source, overrides, "-restore")
+	opts.Restore = &Restore{DatastoreID: source.ID, TargetTime: targetTime.UTC().Format(RestoreTimeFormat)}
+
+	return opts, nil
+}
+
+// inheritCreateOpts fills unset options of the new datastore from the source.
+// The name defaults to the source name with the suffix.
+func inheritCreateOpts(source Datastore, overrides DatastoreCreateOpts, nameSuffix string) DatastoreCreateOpts {
+	opts := overrides
+	if opts.Name == "" {
+		opts.Name = source.Name + nameSuffix
+	}
+	if opts.TypeID == "" {
+		opts.TypeID = source.TypeID
+	}
+	if opts.SubnetID == "" {
+		opts.SubnetID = source.SubnetID
+	}
+	if opts.NodeCount == 0 {
+		opts.NodeCount = source.NodeCount
+	}
+	if opts.FlavorID == "" && opts.Flavor == nil {
+		if source.FlavorID != "" {
+			opts.FlavorID = source.FlavorID
+		} else {
+			flavor := source.Flavor
+			opts.Flavor = &flavor
+		}
+	}
+	if opts.Config == nil && len(source.Config) > 0 {
+		opts.Config = source.Config
+	}
+	if opts.Pooler == nil && source.Pooler.Mode != "" {
+		pooler := source.Pooler
+		opts.Pooler = &pooler
+	}
+	if opts.SecurityGroups == nil && len(source.SecurityGroups) > 0 {
+		opts.SecurityGroups = source.SecurityGroups
+	}
+
+	return opts
+}
@@ -0,0 +1,176 @@
+package dbaas
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const restoreSourceID = "5b9a3f52-5a4e-4f4b-9c57-0d1f1c3a2f10"
+
+func testRestoreSource() Datastore {
+	return Datastore{
+		ID:                  restoreSourceID,
+		Name:                "source",
+		TypeID:              datastoreTypeID,
+		SubnetID:            "2c4a1d53-1b5e-4b31-9d3a-0a7d6e9d5f21",
+		FlavorID:            "7a3a6bd5-0c8f-4d2f-9b6d-2b1f2ce5b3f0",
+		Config:              map[string]any{"max_connections": float64(100)},
+		Pooler:              Pooler{Mode: "session", Size: 30},
+		SecurityGroups:      []string{"sg-1"},
+		NodeCount:           2,
+		BackupRetentionDays: 7,
+		AllowRestore:        true,
+		Status:              StatusActive,
+	}
+}
+
+func restoreResponders(t *testing.T, testClient *API, source Datastore, created *DatastoreCreateOpts) {
+	t.Helper()
+
+	sourceResponse, err := httpmock.NewJsonResponder(200, map[string]Datastore{"datastore": source})
+	require.NoError(t, err)
+	httpmock.RegisterResponder("GET", testClient.Endpoint+DatastoresURI+"/"+restoreSourceID, sourceResponse)
+	httpmock.RegisterResponder("POST", testClient.Endpoint+DatastoresURI,
+		func(req *http.Request) (*http.Response, error) {
+			var body struct {
+				Datastore DatastoreCreateOpts `json:"datastore"`
+			}
+			if err := json.NewDecoder(req.Body).Decode(&body); err != nil {
+				return httpmock.NewStringResponse(400, ""), err
+			}
+			*created = body.Datastore
+
+			return httpmock.NewJsonResponse(200, map[string]Datastore{"datastore": {
+				ID:     datastoreID,
+				Name:   body.Datastore.Name,
+				Status: StatusPendingCreate,
+			}})
+		})
+}
+
+func TestRestoreDatastore(t *testing.T) {
+	httpmock.Activate()
+	testClient := SetupTestClient()
+	defer httpmock.DeactivateAndReset()
+
+	var created DatastoreCreateOpts
+	restoreResponders(t, testClient, testRestoreSource(), &created)
+	targetTime := time.Now().Add(-time.Hour).In(time.FixedZone("UTC+3", 3*60*60))
+
+	actual, err := testClient.RestoreDatastore(context.Background(), restoreSourceID, targetTime, DatastoreCreateOpts{})
+
+	require.NoError(t, err)
+	assert.Equal(t, datastoreID, actual.ID)
+	require.NotNil(t, created.Restore)
+	assert.Equal(t, restoreSourceID, created.Restore.DatastoreID)
+	assert.Equal(t, targetTime.UTC().Format("2006-01-02T15:04:05"), created.Restore.TargetTime)
+	assert.Equal(t, "source-restore", created.Name)
+	assert.Equal(t, datastoreTypeID, created.TypeID)
+	assert.Equal(t, "2c4a1d53-1b5e-4b31-9d3a-0a7d6e9d5f21", created.SubnetID)
+	assert.Equal(t, "7a3a6bd5-0c8f-4d2f-9b6d-2b1f2ce5b3f0", created.FlavorID)
+	assert.Nil(t, created.Flavor)
+	assert.Equal(t, 2, created.NodeCount)
+	assert.Equal(t, map[string]any{"max_connections": float64(100)}, created.Config)
+	assert.Equal(t, &Pooler{Mode: "session", Size: 30}, created.Pooler)
+	assert.Equal(t, []string{"sg-1"}, created.SecurityGroups)
+}
+
+func TestRestoreDatastoreOverrides(t *testing.T) {
+	httpmock.Activate()
+	testClient := SetupTestClient()
+	defer httpmock.DeactivateAndReset()
+
+	var created DatastoreCreateOpts
+	restoreResponders(t, testClient, testRestoreSource(), &created)
+	overrides := DatastoreCreateOpts{
+		Name:           "restored",
+		Flavor:         &Flavor{Vcpus: 4, RAM: 8192, Disk: 64},
+		Config:         map[string]any{"max_connections": float64(200)},
+		SecurityGroups: []string{},
+		NodeCount:      1,
+	}
+
+	_, err := testClient.RestoreDatastore(context.Background(), restoreSourceID, time.Now().Add(-time.Hour), overrides)
+
+	require.NoError(t, err)
+	assert.Equal(t, "restored", created.Name)
+	assert.Empty(t, created.FlavorID)
+	assert.Equal(t, &Flavor{Vcpus: 4, RAM: 8192, Disk: 64}, created.Flavor)
+	assert.Equal(t, map[string]any{"max_connections": float64(200)}, created.Config)
+	assert.Empty(t, created.SecurityGroups)
+	assert.Equal(t, 1, created.NodeCount)
+	assert.Equal(t, datastoreTypeID, created.TypeID)
+}
+
+func TestRestoreDatastoreInvalid(t *testing.T) {
+	notAllowed := testRestoreSource()
+	notAllowed.AllowRestore = false
+	created := testRestoreSource()
+	created.CreatedAt = time.Now().Add(-time.Hour).UTC().Format(RestoreTimeFormat)
+
+	tests := []struct {
+		targetTime time.Time
+		name       string
+		contains   string
+		source     Datastore
+	}{
+		{name: "not allowed", source: notAllowed, targetTime: time.Now().Add(-time.Hour), contains: "doesn't allow"},
+		{name: "future", source: testRestoreSource(), targetTime: time.Now().Add(time.Hour), contains: "in the future"},
+		{
+			name:       "retention",
+			source:     testRestoreSource(),
+			targetTime: time.Now().AddDate(0, 0, -8),
+			contains:   "backups are kept for 7 days",
+		},
+		{
+			name:       "before creation",
+			source:     created,
+			targetTime: time.Now().Add(-2 * time.Hour),
+			contains:   "earlier than the datastore creation",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			httpmock.Activate()
+			testClient := SetupTestClient()
+			defer httpmock.DeactivateAndReset()
+
+			var opts DatastoreCreateOpts
+			restoreResponders(t, testClient, test.source, &opts)
+
+			_, err := testClient.RestoreDatastore(
+				context.Background(), restoreSourceID, test.targetTime, DatastoreCreateOpts{})
+
+			require.Error(t, err)
+			assert.ErrorIs(t, err, ErrInvalidRestore)
+			assert.Contains(t, err.Error(), test.contains)
+			assert.Equal(t, 0, httpmock.GetCallCountInfo()["POST "+testClient.Endpoint+DatastoresURI])
+		})
+	}
+}
+
+func TestRestoreDatastoreAndWait(t *testing.T) {
+	httpmock.Activate()
+	testClient := SetupTestClient()
+	defer httpmock.DeactivateAndReset()
+
+	var created DatastoreCreateOpts
+	restoreResponders(t, testClient, testRestoreSource(), &created)
+	httpmock.RegisterResponder("GET", testClient.Endpoint+DatastoresURI+"/"+datastoreID,
+		datastoreStatusResponder(StatusPendingCreate, StatusActive))
+
+	actual, err := testClient.RestoreDatastoreAndWait(
+		context.Background(), restoreSourceID, time.Now().Add(-time.Hour), DatastoreCreateOpts{}, testWaitOpts())
+
+	require.NoError(t, err)
+	assert.Equal(t, StatusActive, actual.Status)
+	assert.Equal(t, 2, httpmock.GetCallCountInfo()["GET "+testClient.Endpoint+DatastoresURI+"/"+datastoreID])
+}
@@ -0,0 +1,170 @@
+package dbaas
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"syscall"
+	"time"
+)
+
+const (
+	// defaultRetryMaxAttempts specifies a default number of attempts for a single request.
+	defaultRetryMaxAttempts = 4
+
+	// defaultRetryMinBackoff specifies a default delay before the first retry.
+	defaultRetryMinBackoff = 500 * time.Millisecond
+
+	// defaultRetryMaxBackoff specifies a default upper bound for a delay between retries.
+	defaultRetryMaxBackoff = 30 * time.Second
+)
+
+// RetryPolicy describes how requests that failed with a transient error are retried.
+// Requests are retried on 429, 502, 503 and 504 responses and on connection resets.
+// GET, HEAD, OPTIONS, PUT and DELETE requests are retried by default,
+// POST requests are retried only if RetryPOST is set.
+type RetryPolicy struct {
+	// MaxAttempts is a total number of attempts including the first one.
+	MaxAttempts int
+
+	// MinBackoff is a base delay of the exponential backoff.
+	MinBackoff time.Duration
+
+	// MaxBackoff is an upper bound for a delay between attempts,
+	// delays requested by the Retry-After header are capped by it as well.
+	MaxBackoff time.Duration
+
+	// RetryPOST enables retries of the non-idempotent POST requests.
+	RetryPOST bool
+}
+
+// NewRetryPolicy returns a retry policy with default settings.
+func NewRetryPolicy() *RetryPolicy {
+	return &RetryPolicy{
+		MaxAttempts: defaultRetryMaxAttempts,
+		MinBackoff:  defaultRetryMinBackoff,
+		MaxBackoff:  defaultRetryMaxBackoff,
+	}
+}
+
+// maxAttempts returns a number of attempts allowed by the policy.
+// Nil policy allows a single attempt.
+func (p *RetryPolicy) maxAttempts() int {
+	if p == nil || p.MaxAttempts < 1 {
+		return 1
+	}
+	return p.MaxAttempts
+}
+
+// retryable checks if a request can be repeated after the given response or error.
+func (p *RetryPolicy) retryable(method string, resp *http.Response, err error) bool {
+	if !p.retryableMethod(method) {
+		return false
+	}
+	if resp == nil {
+		return isConnectionReset(err)
+	}
+	return isRetryableStatusCode(resp.StatusCode)
+}
+
+// retryableMethod checks if requests with the given method can be repeated.
+func (p *RetryPolicy) retryableMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	case http.MethodPost:
+		return p.RetryPOST
+	default:
+		return false
+	}
+}
+
+// backoff returns a delay before the next attempt.
+// Delay from the Retry-After header is preferred over the exponential backoff with jitter.
+func (p *RetryPolicy) backoff(attempt int, resp *http.Response) time.Duration {
+	if resp != nil {
+		if delay, ok := parseRetryAfter(resp.Header.Get("Retry-After")); ok {
+			if p.MaxBackoff > 0 && delay > p.MaxBackoff {
+				return p.MaxBackoff
+			}
+			return delay
+		}
+	}
+
+	if p.MinBackoff <= 0 {
+		return 0
+	}
+	delay := p.MinBackoff
+	for i := 1; i < attempt; i++ {
+		delay *= 2
+		if p.MaxBackoff > 0 && delay >= p.MaxBackoff {
+			delay = p.MaxBackoff
+			break
+		}
+	}
+
+	// Equal jitter: pick a random delay between a half and the whole calculated value.
+	half := delay / 2
+	return half + time.Duration(rand.Int63n(int64(delay-half)+1)) //nolint:gosec
+}
+
+// wait blocks until the delay before the next attempt passes.
+// It returns an error without waiting if the context deadline comes earlier than the delay.
+func (p *RetryPolicy) wait(ctx context.Context, attempt int, resp *http.Response) error {
+	delay := p.backoff(attempt, resp)
+	if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < delay {
+		return context.DeadlineExceeded
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// isRetryableStatusCode checks if the response status code denotes a transient failure.
+func isRetryableStatusCode(statusCode int) bool {
+	switch statusCode {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	default:
+		return false
+	}
+}
+
+// isConnectionReset checks if the error is caused by the connection closed by the remote side.
+func isConnectionReset(err error) bool {
+	return errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, syscall.EPIPE) ||
+		errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, io.EOF)
+}
+
+// parseRetryAfter parses the Retry-After header value which is either
+// a number of seconds or an HTTP date.
+func parseRetryAfter(value string) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		delay := time.Until(date)
+		if delay < 0 {
+			delay = 0
+		}
+		return delay, true
+	}
+	return 0, false
+}
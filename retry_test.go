@@ -0,0 +1,172 @@
+package dbaas
+
+import (
+	"context"
+	"net/http"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupRetryTestClient() *API {
+	testClient := SetupTestClient()
+	testClient.RetryPolicy = &RetryPolicy{
+		MaxAttempts: 3,
+		MinBackoff:  time.Millisecond,
+		MaxBackoff:  10 * time.Millisecond,
+	}
+	return testClient
+}
+
+func TestRetryOnServiceUnavailable(t *testing.T) {
+	httpmock.Activate()
+	testClient := setupRetryTestClient()
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder("GET", testClient.Endpoint+UsersURI+"/"+userID,
+		httpmock.NewStringResponder(503, "").Then(
+			httpmock.NewStringResponder(503, "")).Then(
+			httpmock.NewStringResponder(200, testUserResponse)))
+
+	actual, err := testClient.User(context.Background(), userID)
+
+	require.NoError(t, err)
+	assert.Equal(t, userID, actual.ID)
+	assert.Equal(t, 3, httpmock.GetTotalCallCount())
+}
+
+func TestRetryOnConnectionReset(t *testing.T) {
+	httpmock.Activate()
+	testClient := setupRetryTestClient()
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder("DELETE", testClient.Endpoint+UsersURI+"/"+userID,
+		httpmock.NewErrorResponder(syscall.ECONNRESET).Then(
+			httpmock.NewStringResponder(204, "")))
+
+	err := testClient.DeleteUser(context.Background(), userID)
+
+	require.NoError(t, err)
+	assert.Equal(t, 2, httpmock.GetTotalCallCount())
+}
+
+func TestRetryGivesUpAfterMaxAttempts(t *testing.T) {
+	httpmock.Activate()
+	testClient := setupRetryTestClient()
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder("GET", testClient.Endpoint+UsersURI+"/"+userID,
+		httpmock.NewStringResponder(502, ""))
+
+	_, err := testClient.User(context.Background(), userID)
+
+	require.Error(t, err)
+	assert.Equal(t, 3, httpmock.GetTotalCallCount())
+}
+
+func TestRetryDoesNotRepeatPOSTByDefault(t *testing.T) {
+	httpmock.Activate()
+	testClient := setupRetryTestClient()
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder("POST", testClient.Endpoint+UsersURI,
+		httpmock.NewStringResponder(503, ""))
+
+	_, err := testClient.CreateUser(context.Background(), UserCreateOpts{Name: "user"})
+
+	require.Error(t, err)
+	assert.Equal(t, 1, httpmock.GetTotalCallCount())
+}
+
+func TestRetryPOSTWhenEnabled(t *testing.T) {
+	httpmock.Activate()
+	testClient := setupRetryTestClient()
+	testClient.RetryPolicy.RetryPOST = true
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder("POST", testClient.Endpoint+UsersURI,
+		httpmock.NewStringResponder(503, "").Then(
+			httpmock.NewStringResponder(200, testUserResponse)))
+
+	_, err := testClient.CreateUser(context.Background(), UserCreateOpts{Name: "user"})
+
+	require.NoError(t, err)
+	assert.Equal(t, 2, httpmock.GetTotalCallCount())
+}
+
+func TestRetryDoesNotRepeatClientErrors(t *testing.T) {
+	httpmock.Activate()
+	testClient := setupRetryTestClient()
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder("GET", testClient.Endpoint+UsersURI+"/"+userID,
+		httpmock.NewStringResponder(404, testUserNotFoundResponse))
+
+	_, err := testClient.User(context.Background(), userID)
+
+	require.Error(t, err)
+	assert.Equal(t, 1, httpmock.GetTotalCallCount())
+}
+
+func TestRetryRespectsContextDeadline(t *testing.T) {
+	httpmock.Activate()
+	testClient := setupRetryTestClient()
+	testClient.RetryPolicy.MaxBackoff = time.Minute
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder("GET", testClient.Endpoint+UsersURI+"/"+userID,
+		func(req *http.Request) (*http.Response, error) {
+			resp := httpmock.NewStringResponse(429, "")
+			resp.Header.Set("Retry-After", "30")
+			return resp, nil
+		})
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	start := time.Now()
+	_, err := testClient.User(ctx, userID)
+
+	require.Error(t, err)
+	assert.Less(t, time.Since(start), time.Second)
+	assert.Equal(t, 1, httpmock.GetTotalCallCount())
+}
+
+func TestRetryPolicyBackoff(t *testing.T) {
+	policy := &RetryPolicy{
+		MinBackoff: 100 * time.Millisecond,
+		MaxBackoff: time.Second,
+	}
+
+	for attempt, expected := range map[int]time.Duration{
+		1: 100 * time.Millisecond,
+		2: 200 * time.Millisecond,
+		3: 400 * time.Millisecond,
+		5: time.Second,
+	} {
+		delay := policy.backoff(attempt, nil)
+		assert.GreaterOrEqual(t, delay, expected/2)
+		assert.LessOrEqual(t, delay, expected)
+	}
+
+	resp := &http.Response{Header: http.Header{}}
+	resp.Header.Set("Retry-After", "2")
+	assert.Equal(t, time.Second, policy.backoff(1, resp))
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	delay, ok := parseRetryAfter("3")
+	require.True(t, ok)
+	assert.Equal(t, 3*time.Second, delay)
+
+	delay, ok = parseRetryAfter(time.Now().Add(time.Hour).UTC().Format(http.TimeFormat))
+	require.True(t, ok)
+	assert.Greater(t, delay, 59*time.Minute)
+
+	_, ok = parseRetryAfter("soon")
+	assert.False(t, ok)
+}
@@ -0,0 +1,172 @@
+package dbaas
+
+import (
+	"context"
+)
+
+// DatastoreService is implemented by clients that manage datastores.
+type DatastoreService interface {
+	Datastores(ctx context.Context, params *DatastoreQueryParams) ([]Datastore, error)
+	Datastore(ctx context.Context, datastoreID string) (Datastore, error)
+	CreateDatastore(ctx context.Context, opts DatastoreCreateOpts) (Datastore, error)
+	UpdateDatastore(ctx context.Context, datastoreID string, opts DatastoreUpdateOpts) (Datastore, error)
+	UpdateSecurityGroup(ctx context.Context, datastoreID string, opts DatastoreSecurityGroupOpts) (Datastore, error)
+	DeleteDatastore(ctx context.Context, datastoreID string) error
+	ResizeDatastore(ctx context.Context, datastoreID string, opts DatastoreResizeOpts) (Datastore, error)
+	PoolerDatastore(ctx context.Context, datastoreID string, opts DatastorePoolerOpts) (Datastore, error)
+	FirewallDatastore(ctx context.Context, datastoreID string, opts DatastoreFirewallOpts) (Datastore, error)
+	ConfigDatastore(ctx context.Context, datastoreID string, opts DatastoreConfigOpts) (Datastore, error)
+	PasswordDatastore(ctx context.Context, datastoreID string, opts DatastorePasswordOpts) (Datastore, error)
+	BackupsDatastore(ctx context.Context, datastoreID string, opts DatastoreBackupsOpts) (Datastore, error)
+	EnableLogPlatform(ctx context.Context, datastoreID string, opts LogPlatformOpts) (Datastore, error)
+	DisableLogPlatform(ctx context.Context, datastoreID string) error
+	CreateFloatingIP(ctx context.Context, opts FloatingIPsOpts) error
+	DeleteFloatingIP(ctx context.Context, opts FloatingIPsOpts) error
+	WaitForDatastoreStatus(ctx context.Context, datastoreID string, targets []Status, opts *WaitOpts) (Datastore, error)
+	WaitForDatastoreDeleted(ctx context.Context, datastoreID string, opts *WaitOpts) error
+}
+
+// UserService is implemented by clients that manage users.
+type UserService interface {
+	Users(ctx context.Context, params ...*UserQueryParams) ([]User, error)
+	User(ctx context.Context, userID string) (User, error)
+	CreateUser(ctx context.Context, opts UserCreateOpts) (User, error)
+	UpdateUser(ctx context.Context, userID string, opts UserUpdateOpts) (User, error)
+	DeleteUser(ctx context.Context, userID string) error
+	CreateUserAndWait(ctx context.Context, opts UserCreateOpts, waitOpts *WaitOpts) (User, error)
+	UpdateUserAndWait(ctx context.Context, userID string, opts UserUpdateOpts, waitOpts *WaitOpts) (User, error)
+	DeleteUserAndWait(ctx context.Context, userID string, waitOpts *WaitOpts) error
+}
+
+// DatabaseService is implemented by clients that manage databases.
+type DatabaseService interface {
+	Databases(ctx context.Context, params *DatabaseQueryParams) ([]Database, error)
+	Database(ctx context.Context, databaseID string) (Database, error)
+	CreateDatabase(ctx context.Context, opts DatabaseCreateOpts) (Database, error)
+	UpdateDatabase(ctx context.Context, databaseID string, opts DatabaseUpdateOpts) (Database, error)
+	DeleteDatabase(ctx context.Context, databaseID string) error
+	CreateDatabaseAndWait(ctx context.Context, opts DatabaseCreateOpts, waitOpts *WaitOpts) (Database, error)
+	UpdateDatabaseAndWait(
+		ctx context.Context,
+		databaseID string,
+		opts DatabaseUpdateOpts,
+		waitOpts *WaitOpts,
+	) (Database, error)
+	DeleteDatabaseAndWait(ctx context.Context, databaseID string, waitOpts *WaitOpts) error
+}
+
+// GrantService is implemented by clients that manage grants.
+type GrantService interface {
+	Grants(ctx context.Context, params ...*GrantQueryParams) ([]Grant, error)
+	Grant(ctx context.Context, grantID string) (Grant, error)
+	CreateGrant(ctx context.Context, opts GrantCreateOpts) (Grant, error)
+	DeleteGrant(ctx context.Context, grantID string) error
+	CreateGrantAndWait(ctx context.Context, opts GrantCreateOpts, waitOpts *WaitOpts) (Grant, error)
+	DeleteGrantAndWait(ctx context.Context, grantID string, waitOpts *WaitOpts) error
+}
+
+// ACLService is implemented by clients that manage Kafka ACLs.
+type ACLService interface {
+	ACLs(ctx context.Context, params *ACLQueryParams) ([]ACL, error)
+	ACL(ctx context.Context, aclID string) (ACL, error)
+	CreateACL(ctx context.Context, opts ACLCreateOpts) (ACL, error)
+	UpdateACL(ctx context.Context, aclID string, opts ACLUpdateOpts) (ACL, error)
+	DeleteACL(ctx context.Context, aclID string) error
+	CreateACLAndWait(ctx context.Context, opts ACLCreateOpts, waitOpts *WaitOpts) (ACL, error)
+	UpdateACLAndWait(ctx context.Context, aclID string, opts ACLUpdateOpts, waitOpts *WaitOpts) (ACL, error)
+	DeleteACLAndWait(ctx context.Context, aclID string, waitOpts *WaitOpts) error
+}
+
+// TopicService is implemented by clients that manage Kafka topics.
+type TopicService interface {
+	Topics(ctx context.Context, params *TopicQueryParams) ([]Topic, error)
+	Topic(ctx context.Context, topicID string) (Topic, error)
+	CreateTopic(ctx context.Context, opts TopicCreateOpts) (Topic, error)
+	UpdateTopic(ctx context.Context, topicID string, opts TopicUpdateOpts) (Topic, error)
+	DeleteTopic(ctx context.Context, topicID string) error
+	CreateTopicAndWait(ctx context.Context, opts TopicCreateOpts, waitOpts *WaitOpts) (Topic, error)
+	UpdateTopicAndWait(ctx context.Context, topicID string, opts TopicUpdateOpts, waitOpts *WaitOpts) (Topic, error)
+	DeleteTopicAndWait(ctx context.Context, topicID string, waitOpts *WaitOpts) error
+}
+
+// ExtensionService is implemented by clients that manage PostgreSQL extensions.
+type ExtensionService interface {
+	Extensions(ctx context.Context, params *ExtensionQueryParams) ([]Extension, error)
+	Extension(ctx context.Context, extensionID string) (Extension, error)
+	CreateExtension(ctx context.Context, opts ExtensionCreateOpts) (Extension, error)
+	DeleteExtension(ctx context.Context, extensionID string) error
+	CreateExtensionAndWait(ctx context.Context, opts ExtensionCreateOpts, waitOpts *WaitOpts) (Extension, error)
+	DeleteExtensionAndWait(ctx context.Context, extensionID string, waitOpts *WaitOpts) error
+}
+
+// ReplicationSlotService is implemented by clients that manage PostgreSQL logical replication slots.
+type ReplicationSlotService interface {
+	LogicalReplicationSlots(
+		ctx context.Context,
+		params *LogicalReplicationSlotQueryParams,
+	) ([]LogicalReplicationSlot, error)
+	LogicalReplicationSlot(ctx context.Context, slotID string) (LogicalReplicationSlot, error)
+	CreateLogicalReplicationSlot(
+		ctx context.Context,
+		opts LogicalReplicationSlotCreateOpts,
+	) (LogicalReplicationSlot, error)
+	DeleteLogicalReplicationSlot(ctx context.Context, slotID string) error
+	CreateLogicalReplicationSlotAndWait(
+		ctx context.Context,
+		opts LogicalReplicationSlotCreateOpts,
+		waitOpts *WaitOpts,
+	) (LogicalReplicationSlot, error)
+	DeleteLogicalReplicationSlotAndWait(ctx context.Context, slotID string, waitOpts *WaitOpts) error
+}
+
+// MetricsTokenService is implemented by clients that manage Prometheus metrics tokens.
+type MetricsTokenService interface {
+	PrometheusMetricTokens(
+		ctx context.Context,
+		params ...*PrometheusMetricTokenQueryParams,
+	) ([]PrometheusMetricToken, error)
+	PrometheusMetricToken(ctx context.Context, prometheusMetricTokenID string) (PrometheusMetricToken, error)
+	CreatePrometheusMetricToken(
+		ctx context.Context,
+		opts PrometheusMetricTokenCreateOpts,
+	) (PrometheusMetricToken, error)
+	UpdatePrometheusMetricToken(
+		ctx context.Context,
+		prometheusMetricTokenID string,
+		opts PrometheusMetricTokenUpdateOpts,
+	) (PrometheusMetricToken, error)
+	DeletePrometheusMetricToken(ctx context.Context, prometheusMetricTokenID string) error
+}
+
+// CatalogService is implemented by clients that read the read-only catalogs:
+// datastore types, flavors, configuration parameters and available extensions.
+type CatalogService interface {
+	DatastoreTypes(ctx context.Context) ([]DatastoreType, error)
+	DatastoreType(ctx context.Context, datastoreTypeID string) (DatastoreType, error)
+	Flavors(ctx context.Context, params ...*FlavorQueryParams) ([]FlavorResponse, error)
+	Flavor(ctx context.Context, flavorID string) (FlavorResponse, error)
+	ConfigurationParameters(
+		ctx context.Context,
+		params ...*ConfigurationParameterQueryParams,
+	) ([]ConfigurationParameter, error)
+	ConfigurationParameter(ctx context.Context, configurationParameterID string) (ConfigurationParameter, error)
+	AvailableExtensions(
+		ctx context.Context,
+		params ...*AvailableExtensionQueryParams,
+	) ([]AvailableExtension, error)
+	AvailableExtension(ctx context.Context, availableExtensionID string) (AvailableExtension, error)
+}
+
+// Compile-time checks that API implements all service interfaces.
+var (
+	_ DatastoreService       = (*API)(nil)
+	_ UserService            = (*API)(nil)
+	_ DatabaseService        = (*API)(nil)
+	_ GrantService           = (*API)(nil)
+	_ ACLService             = (*API)(nil)
+	_ TopicService           = (*API)(nil)
+	_ ExtensionService       = (*API)(nil)
+	_ ReplicationSlotService = (*API)(nil)
+	_ MetricsTokenService    = (*API)(nil)
+	_ CatalogService         = (*API)(nil)
+)
@@ -0,0 +1,122 @@
+package dbaas
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	_ "embed" // embeds the root CA bundle
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net"
+)
+
+// ErrNoRootCA is returned if the root CA bundle doesn't contain certificates.
+var ErrNoRootCA = errors.New("no root CA certificates")
+
+// rootCA is the PEM bundle with the root CA of the Managed Databases service.
+//
+//go:embed certs/root.crt
+var rootCA []byte
+
+// TLSOpts represents options of the TLS configuration for a datastore.
+type TLSOpts struct {
+	// Address defaults to hostnames.
+	Address AddressKind
+
+	// RootCA is a PEM bundle that overrides the embedded root CA.
+	// It is required if the embedded bundle has no certificates, see RootCA.
+	RootCA []byte
+
+	// Replica connects to the replicas instead of the master.
+	Replica bool
+}
+
+// RootCA returns the certificates of the embedded PEM bundle with the root CA of the Managed Databases service,
+// e.g. to write them to a file for RootCertPath of ConnectionOpts. Text outside of certificates is dropped.
+// It returns nil if the bundle has no certificates.
+func RootCA() []byte {
+	var bundle []byte
+	rest := rootCA
+	for {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			return bundle
+		}
+		if block.Type == "CERTIFICATE" {
+			bundle = append(bundle, pem.EncodeToMemory(block)...)
+		}
+	}
+}
+
+// RootCAPool returns a pool with certificates of the PEM bundle or of the embedded bundle if it is empty.
+func RootCAPool(bundle []byte) (*x509.CertPool, error) {
+	if len(bundle) == 0 {
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(rootCA) {
+			return nil, fmt.Errorf("%w in the embedded bundle certs/root.crt, pass a bundle explicitly", ErrNoRootCA)
+		}
+		return pool, nil
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(bundle) {
+		return nil, ErrNoRootCA
+	}
+
+	return pool, nil
+}
+
+// NewTLSConfig returns the TLS configuration to connect to the first master or replica endpoint of the datastore.
+// Certificates of connections by IP addresses are verified against hostnames of the instances.
+func NewTLSConfig(datastore Datastore, opts TLSOpts) (*tls.Config, error) {
+	info := NewConnectionInfo(datastore, "")
+	role := InstanceRoleMaster
+	if opts.Replica {
+		role = InstanceRoleReplica
+	}
+	endpoints, err := info.Endpoints(role, opts.Address)
+	if err != nil {
+		return nil, err
+	}
+
+	return info.TLSConfig(endpoints[0].Host, opts.RootCA)
+}
+
+// TLSConfig returns the TLS configuration to connect to the host of the datastore.
+// The host is either a hostname or an IP address of one of the instances. In the latter case
+// the server name is set to the hostname of the instance, because certificates are issued for hostnames.
+// The embedded root CA is used if the bundle is empty.
+func (c ConnectionInfo) TLSConfig(host string, bundle []byte) (*tls.Config, error) {
+	pool, err := RootCAPool(bundle)
+	if err != nil {
+		return nil, err
+	}
+	serverName, err := c.serverName(host)
+	if err != nil {
+		return nil, err
+	}
+
+	return &tls.Config{
+		RootCAs:    pool,
+		ServerName: serverName,
+		MinVersion: tls.VersionTLS12,
+	}, nil
+}
+
+// serverName returns the name that the certificate of the host is verified against.
+func (c ConnectionInfo) serverName(host string) (string, error) {
+	if net.ParseIP(host) == nil {
+		return host, nil
+	}
+	for _, instance := range c.Instances {
+		if instance.PrivateIP != host && instance.FloatingIP != host {
+			continue
+		}
+		if instance.Hostname == "" {
+			return "", fmt.Errorf("%w: instance %s has no hostname", ErrNoAddress, instance.ID)
+		}
+		return instance.Hostname, nil
+	}
+
+	return "", fmt.Errorf("%w: no instance with IP %s", ErrNoAddress, host)
+}
@@ -0,0 +1,181 @@
+package dbaas
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testCertificates creates a root CA and a server certificate for the hostname signed by it.
+func testCertificates(t *testing.T, hostname string) ([]byte, tls.Certificate) {
+	t.Helper()
+
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	caTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "Test Root CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+	caDER, err := x509.CreateCertificate(rand.Reader, caTemplate, caTemplate, &caKey.PublicKey, caKey)
+	require.NoError(t, err)
+
+	serverKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	serverTemplate := &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: hostname},
+		DNSNames:     []string{hostname},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	serverDER, err := x509.CreateCertificate(rand.Reader, serverTemplate, caTemplate, &serverKey.PublicKey, caKey)
+	require.NoError(t, err)
+
+	caPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: caDER})
+	return caPEM, tls.Certificate{Certificate: [][]byte{serverDER}, PrivateKey: serverKey}
+}
+
+// testTLSListener accepts connections and completes TLS handshakes until the test ends.
+func testTLSListener(t *testing.T, certificate tls.Certificate) net.Listener {
+	t.Helper()
+
+	listener, err := tls.Listen("tcp", "127.0.0.1:0", &tls.Config{
+		Certificates: []tls.Certificate{certificate},
+		MinVersion:   tls.VersionTLS12,
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			_ = conn.(*tls.Conn).Handshake()
+			conn.Close()
+		}
+	}()
+
+	return listener
+}
+
+// useTestRootCA replaces the embedded root CA bundle until the test ends.
+func useTestRootCA(t *testing.T, bundle []byte) {
+	t.Helper()
+
+	embedded := rootCA
+	rootCA = bundle
+	t.Cleanup(func() { rootCA = embedded })
+}
+
+func testTLSDatastore() Datastore {
+	return Datastore{
+		Connection: map[string]string{"master": "master.example.org"},
+		Instances: []Instances{
+			{ID: "master-id", IP: "127.0.0.1", FloatingIP: "203.0.113.10", Role: "MASTER", Hostname: "master-host"},
+			{ID: "replica-id", IP: "127.0.0.2", FloatingIP: "None", Role: "REPLICA"},
+		},
+	}
+}
+
+func TestNewTLSConfigByIP(t *testing.T) {
+	caPEM, certificate := testCertificates(t, "master-host")
+	listener := testTLSListener(t, certificate)
+
+	config, err := NewTLSConfig(testTLSDatastore(), TLSOpts{RootCA: caPEM, Address: AddressPrivateIP})
+	require.NoError(t, err)
+	assert.Equal(t, "master-host", config.ServerName)
+
+	conn, err := tls.Dial("tcp", listener.Addr().String(), config)
+	require.NoError(t, err)
+	conn.Close()
+
+	otherCAPEM, _ := testCertificates(t, "master-host")
+	config, err = NewTLSConfig(testTLSDatastore(), TLSOpts{RootCA: otherCAPEM, Address: AddressPrivateIP})
+	require.NoError(t, err)
+
+	_, err = tls.Dial("tcp", listener.Addr().String(), config)
+	assert.Error(t, err)
+}
+
+func TestNewTLSConfigServerName(t *testing.T) {
+	caPEM, _ := testCertificates(t, "master-host")
+
+	config, err := NewTLSConfig(testTLSDatastore(), TLSOpts{RootCA: caPEM})
+	require.NoError(t, err)
+	assert.Equal(t, "master.example.org", config.ServerName)
+
+	config, err = NewTLSConfig(testTLSDatastore(), TLSOpts{RootCA: caPEM, Address: AddressFloatingIP})
+	require.NoError(t, err)
+	assert.Equal(t, "master-host", config.ServerName)
+
+	_, err = NewTLSConfig(testTLSDatastore(), TLSOpts{RootCA: caPEM, Address: AddressPrivateIP, Replica: true})
+	assert.ErrorIs(t, err, ErrNoAddress)
+
+	info := NewConnectionInfo(testTLSDatastore(), "postgresql")
+	_, err = info.TLSConfig("192.0.2.1", caPEM)
+	assert.ErrorIs(t, err, ErrNoAddress)
+}
+
+func TestRootCAPool(t *testing.T) {
+	caPEM, _ := testCertificates(t, "master-host")
+
+	pool, err := RootCAPool(caPEM)
+	require.NoError(t, err)
+	assert.NotNil(t, pool)
+
+	_, err = RootCAPool([]byte("not a certificate"))
+	assert.ErrorIs(t, err, ErrNoRootCA)
+
+}
+
+func TestRootCA(t *testing.T) {
+	caPEM, _ := testCertificates(t, "master-host")
+	useTestRootCA(t, append([]byte("Root CA of the service.\n"), caPEM...))
+
+	assert.Equal(t, caPEM, RootCA())
+
+	useTestRootCA(t, []byte("no certificates"))
+
+	assert.Nil(t, RootCA())
+}
+
+func TestNewTLSConfigEmbeddedRootCA(t *testing.T) {
+	caPEM, certificate := testCertificates(t, "master-host")
+	listener := testTLSListener(t, certificate)
+	useTestRootCA(t, caPEM)
+
+	pool, err := RootCAPool(nil)
+	require.NoError(t, err)
+	assert.False(t, pool.Equal(x509.NewCertPool()))
+
+	config, err := NewTLSConfig(testTLSDatastore(), TLSOpts{Address: AddressPrivateIP})
+	require.NoError(t, err)
+
+	conn, err := tls.Dial("tcp", listener.Addr().String(), config)
+	require.NoError(t, err)
+	conn.Close()
+
+	useTestRootCA(t, []byte("no certificates"))
+	_, err = NewTLSConfig(testTLSDatastore(), TLSOpts{Address: AddressPrivateIP})
+	assert.ErrorIs(t, err, ErrNoRootCA)
+}
@@ -0,0 +1,28 @@
+package dbaas
+
+import (
+	"context"
+)
+
+// TokenSource provides authentication tokens for the API requests.
+// It is consulted before every request, so implementations can renew expired tokens.
+type TokenSource interface {
+	Token(ctx context.Context) (string, error)
+}
+
+// StaticTokenSource is a TokenSource that always returns the same token.
+type StaticTokenSource string
+
+// Token returns the static token.
+func (s StaticTokenSource) Token(context.Context) (string, error) {
+	return string(s), nil
+}
+
+// token returns a token for the next request.
+// Token source takes precedence over the static token.
+func (api *API) token(ctx context.Context) (string, error) {
+	if api.TokenSource == nil {
+		return api.Token, nil
+	}
+	return api.TokenSource.Token(ctx)
+}
@@ -19,6 +19,11 @@ type Topic struct {
 	Partitions  uint16 `json:"partitions"`
 }
 
+// ResourceStatus returns the topic status.
+func (t Topic) ResourceStatus() Status {
+	return t.Status
+}
+
 // TopicCreateOpts represents options for the topic Create request.
 type TopicCreateOpts struct {
 	DatastoreID string `json:"datastore_id"`
@@ -38,6 +43,7 @@ type TopicQueryParams struct {
 	DatastoreID string `json:"datastore_id,omitempty"`
 	Name        string `json:"name,omitempty"`
 	Status      Status `json:"status,omitempty"`
+	Page
 }
 
 const TopicsURI = "/topics"
@@ -87,6 +93,9 @@ func (api *API) Topic(ctx context.Context, topicID string) (Topic, error) {
 
 // CreateTopic creates a new topic.
 func (api *API) CreateTopic(ctx context.Context, opts TopicCreateOpts) (Topic, error) {
+	if err := api.guardEngine(ctx, opts.DatastoreID, CapabilityTopics); err != nil {
+		return Topic{}, err
+	}
 	createTopicOpts := struct {
 		Topic TopicCreateOpts `json:"topic"`
 	}{
@@ -153,3 +162,37 @@ func (api *API) DeleteTopic(ctx context.Context, topicID string) error {
 
 	return nil
 }
+
+// CreateTopicAndWait creates a new topic and waits until it becomes active.
+func (api *API) CreateTopicAndWait(ctx context.Context, opts TopicCreateOpts, waitOpts *WaitOpts) (Topic, error) {
+	topic, err := api.CreateTopic(ctx, opts)
+	if err != nil {
+		return Topic{}, err
+	}
+
+	return WaitFor(ctx, api.Topic, topic.ID, []Status{StatusActive}, waitOpts)
+}
+
+// UpdateTopicAndWait updates an existing topic and waits until it becomes active.
+func (api *API) UpdateTopicAndWait(
+	ctx context.Context,
+	topicID string,
+	opts TopicUpdateOpts,
+	waitOpts *WaitOpts,
+) (Topic, error) {
+	topic, err := api.UpdateTopic(ctx, topicID, opts)
+	if err != nil {
+		return Topic{}, err
+	}
+
+	return waitForUpdate(ctx, api.Topic, topicID, topic, waitOpts)
+}
+
+// DeleteTopicAndWait deletes an existing topic and waits until it disappears.
+func (api *API) DeleteTopicAndWait(ctx context.Context, topicID string, waitOpts *WaitOpts) error {
+	if err := api.DeleteTopic(ctx, topicID); err != nil {
+		return err
+	}
+
+	return WaitForDeleted(ctx, api.Topic, topicID, waitOpts)
+}
@@ -30,6 +30,11 @@ type User struct {
 	Status      Status `json:"status"`
 }
 
+// ResourceStatus returns the user status.
+func (u User) ResourceStatus() Status {
+	return u.Status
+}
+
 const UsersURI = "/users"
 
 // User returns a user based on the ID.
@@ -52,9 +57,24 @@ func (api *API) User(ctx context.Context, userID string) (User, error) {
 	return result.User, nil
 }
 
-// Users returns all users.
-func (api *API) Users(ctx context.Context) ([]User, error) {
-	resp, err := api.makeRequest(ctx, http.MethodGet, UsersURI, nil)
+// UserQueryParams represents available query parameters for user.
+type UserQueryParams struct {
+	ID          string `json:"id,omitempty"`
+	ProjectID   string `json:"project_id,omitempty"`
+	Name        string `json:"name,omitempty"`
+	DatastoreID string `json:"datastore_id,omitempty"`
+	Status      Status `json:"status,omitempty"`
+	Page
+}
+
+// Users returns all users or the users that match the optional params.
+func (api *API) Users(ctx context.Context, params ...*UserQueryParams) ([]User, error) {
+	uri, err := setQueryParams(UsersURI, optionalParams(params))
+	if err != nil {
+		return []User{}, err
+	}
+
+	resp, err := api.makeRequest(ctx, http.MethodGet, uri, nil)
 	if err != nil {
 		return []User{}, err
 	}
@@ -72,6 +92,9 @@ func (api *API) Users(ctx context.Context) ([]User, error) {
 
 // CreateUser creates a new user.
 func (api *API) CreateUser(ctx context.Context, opts UserCreateOpts) (User, error) {
+	if err := api.guardEngine(ctx, opts.DatastoreID, CapabilityUsers); err != nil {
+		return User{}, err
+	}
 	createUserOpts := struct {
 		User UserCreateOpts `json:"user"`
 	}{
@@ -138,3 +161,37 @@ func (api *API) UpdateUser(ctx context.Context, userID string, opts UserUpdateOp
 
 	return result.User, nil
 }
+
+// CreateUserAndWait creates a new user and waits until it becomes active.
+func (api *API) CreateUserAndWait(ctx context.Context, opts UserCreateOpts, waitOpts *WaitOpts) (User, error) {
+	user, err := api.CreateUser(ctx, opts)
+	if err != nil {
+		return User{}, err
+	}
+
+	return WaitFor(ctx, api.User, user.ID, []Status{StatusActive}, waitOpts)
+}
+
+// UpdateUserAndWait updates an existing user and waits until it becomes active.
+func (api *API) UpdateUserAndWait(
+	ctx context.Context,
+	userID string,
+	opts UserUpdateOpts,
+	waitOpts *WaitOpts,
+) (User, error) {
+	user, err := api.UpdateUser(ctx, userID, opts)
+	if err != nil {
+		return User{}, err
+	}
+
+	return waitForUpdate(ctx, api.User, userID, user, waitOpts)
+}
+
+// DeleteUserAndWait deletes an existing user and waits until it disappears.
+func (api *API) DeleteUserAndWait(ctx context.Context, userID string, waitOpts *WaitOpts) error {
+	if err := api.DeleteUser(ctx, userID); err != nil {
+		return err
+	}
+
+	return WaitForDeleted(ctx, api.User, userID, waitOpts)
+}
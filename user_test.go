@@ -103,6 +103,21 @@ func TestUsers(t *testing.T) {
 	assert.Equal(t, expected, actual)
 }
 
+func TestUsersWithParams(t *testing.T) {
+	httpmock.Activate()
+	testClient := SetupTestClient()
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponderWithQuery("GET", testClient.Endpoint+UsersURI,
+		"datastore_id=20d7bcf4-f8d6-4bf6-b8f6-46cb440a87f4&name=user",
+		httpmock.NewStringResponder(200, testUsersResponse))
+
+	actual, err := testClient.Users(context.Background(), &UserQueryParams{DatastoreID: datastoreID, Name: "user"})
+
+	require.NoError(t, err)
+	assert.Len(t, actual, 2)
+}
+
 func TestUser(t *testing.T) {
 	httpmock.Activate()
 	testClient := SetupTestClient()
@@ -0,0 +1,231 @@
+package dbaas
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+const (
+	// defaultWaitInterval specifies a default delay between status checks.
+	defaultWaitInterval = 5 * time.Second
+
+	// defaultWaitMaxInterval specifies a default upper bound for a delay between status checks.
+	defaultWaitMaxInterval = 30 * time.Second
+
+	// defaultWaitBackoff specifies a default multiplier of the delay between status checks.
+	defaultWaitBackoff = 1.5
+)
+
+// ErrWaitFailed is returned by waiters if a resource reaches a failure status.
+var ErrWaitFailed = errors.New("resource reached a failure status")
+
+// WaitOpts represents options for waiting until a resource reaches a target status.
+type WaitOpts struct {
+	// Progress is called after every status check.
+	Progress func(status Status, elapsed time.Duration)
+
+	// Interval is a delay before the first status check.
+	Interval time.Duration
+
+	// MaxInterval is an upper bound for a delay between status checks.
+	MaxInterval time.Duration
+
+	// Backoff is a multiplier applied to the delay after every status check.
+	// Values less than 1 keep the delay constant.
+	Backoff float64
+
+	// Timeout limits the total waiting time in addition to the context deadline.
+	Timeout time.Duration
+}
+
+// failureStatuses are the statuses that can't lead to a target status without a user action.
+func failureStatuses() []Status {
+	return []Status{StatusError, StatusDegraded, StatusDiskFull}
+}
+
+// withDefaults returns a copy of options with default values for unset fields.
+func (opts *WaitOpts) withDefaults() WaitOpts {
+	result := WaitOpts{}
+	if opts != nil {
+		result = *opts
+	}
+	if result.Interval <= 0 {
+		result.Interval = defaultWaitInterval
+	}
+	if result.MaxInterval <= 0 {
+		result.MaxInterval = defaultWaitMaxInterval
+	}
+	if result.MaxInterval < result.Interval {
+		result.MaxInterval = result.Interval
+	}
+	if result.Backoff == 0 {
+		result.Backoff = defaultWaitBackoff
+	}
+	return result
+}
+
+// waitForStatus polls the resource until its status is one of the targets.
+// A failure status, that is not a target, stops waiting with ErrWaitFailed.
+// Transient errors of the getter are ignored, other errors stop waiting.
+func waitForStatus[T any](
+	ctx context.Context,
+	get func(ctx context.Context) (T, error),
+	status func(resource T) Status,
+	targets []Status,
+	opts *WaitOpts,
+) (T, error) {
+	o := opts.withDefaults()
+	if o.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, o.Timeout)
+		defer cancel()
+	}
+
+	var resource T
+	start := time.Now()
+	interval := o.Interval
+	for {
+		if err := sleep(ctx, interval); err != nil {
+			return resource, fmt.Errorf("waiting for status %v: %w", targets, err)
+		}
+
+		current, err := get(ctx)
+		switch {
+		case err == nil:
+			resource = current
+			currentStatus := status(resource)
+			if o.Progress != nil {
+				o.Progress(currentStatus, time.Since(start))
+			}
+			if containsStatus(targets, currentStatus) {
+				return resource, nil
+			}
+			if containsStatus(failureStatuses(), currentStatus) {
+				return resource, fmt.Errorf("%w: %s", ErrWaitFailed, currentStatus)
+			}
+		case !IsRetryable(err):
+			return resource, err
+		}
+
+		if o.Backoff > 1 {
+			interval = time.Duration(float64(interval) * o.Backoff)
+		}
+		if interval > o.MaxInterval {
+			interval = o.MaxInterval
+		}
+	}
+}
+
+// sleep blocks for the given duration or until the context is done.
+func sleep(ctx context.Context, duration time.Duration) error {
+	timer := time.NewTimer(duration)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// containsStatus checks if the status is in the list.
+func containsStatus(statuses []Status, status Status) bool {
+	for _, s := range statuses {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
+
+// StatusResource is implemented by resources that go through pending statuses.
+type StatusResource interface {
+	ResourceStatus() Status
+}
+
+// WaitFor waits until the resource with the given ID reaches one of the target statuses.
+// The resource is loaded with the getter, which is usually a method value like api.User.
+// It fails immediately if the resource reaches ERROR, DEGRADED or DISK_FULL status,
+// unless this status is one of the targets.
+func WaitFor[T StatusResource](
+	ctx context.Context,
+	get func(ctx context.Context, id string) (T, error),
+	id string,
+	targets []Status,
+	opts *WaitOpts,
+) (T, error) {
+	resource, err := waitForStatus(ctx,
+		func(ctx context.Context) (T, error) { return get(ctx, id) },
+		func(resource T) Status { return resource.ResourceStatus() },
+		targets, opts)
+	if err != nil {
+		return resource, fmt.Errorf("wait for %s: %w", id, err)
+	}
+
+	return resource, nil
+}
+
+// waitForUpdate waits until the updated resource becomes active.
+// The resource returned by the update request is returned as is if it is already active.
+// Otherwise the server has reported a pending status, so polling can't see the state from before the update.
+func waitForUpdate[T StatusResource](
+	ctx context.Context,
+	get func(ctx context.Context, id string) (T, error),
+	id string,
+	updated T,
+	opts *WaitOpts,
+) (T, error) {
+	if updated.ResourceStatus() == StatusActive {
+		return updated, nil
+	}
+
+	return WaitFor(ctx, get, id, []Status{StatusActive}, opts)
+}
+
+// WaitForDeleted waits until the resource with the given ID is deleted.
+// Resource that is not found or has DELETED status is considered deleted.
+func WaitForDeleted[T StatusResource](
+	ctx context.Context,
+	get func(ctx context.Context, id string) (T, error),
+	id string,
+	opts *WaitOpts,
+) error {
+	_, err := waitForStatus(ctx,
+		func(ctx context.Context) (Status, error) {
+			resource, err := get(ctx, id)
+			if errors.Is(err, ErrNotFound) {
+				return StatusDeleted, nil
+			}
+			if err != nil {
+				return "", err
+			}
+			return resource.ResourceStatus(), nil
+		},
+		func(status Status) Status { return status },
+		[]Status{StatusDeleted}, opts)
+	if err != nil {
+		return fmt.Errorf("wait for %s deletion: %w", id, err)
+	}
+
+	return nil
+}
+
+// WaitForDatastoreStatus waits until the datastore reaches one of the target statuses.
+// It fails immediately if the datastore reaches ERROR, DEGRADED or DISK_FULL status,
+// unless this status is one of the targets.
+func (api *API) WaitForDatastoreStatus(
+	ctx context.Context,
+	datastoreID string,
+	targets []Status,
+	opts *WaitOpts,
+) (Datastore, error) {
+	return WaitFor(ctx, api.Datastore, datastoreID, targets, opts)
+}
+
+// WaitForDatastoreDeleted waits until the datastore is deleted.
+// Datastore that is not found or has DELETED status is considered deleted.
+func (api *API) WaitForDatastoreDeleted(ctx context.Context, datastoreID string, opts *WaitOpts) error {
+	return WaitForDeleted(ctx, api.Datastore, datastoreID, opts)
+}
@@ -0,0 +1,213 @@
+package dbaas
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testDatastoreStatusResponse = `{
+	"datastore": {
+		"id": "20d7bcf4-f8d6-4bf6-b8f6-46cb440a87f4",
+		"status": "%s"
+	}
+}`
+
+func testWaitOpts() *WaitOpts {
+	return &WaitOpts{
+		Interval:    time.Millisecond,
+		MaxInterval: 5 * time.Millisecond,
+		Backoff:     2,
+	}
+}
+
+func datastoreStatusResponder(statuses ...Status) httpmock.Responder {
+	responder := httpmock.NewStringResponder(200, fmt.Sprintf(testDatastoreStatusResponse, statuses[0]))
+	for _, status := range statuses[1:] {
+		responder = responder.Then(
+			httpmock.NewStringResponder(200, fmt.Sprintf(testDatastoreStatusResponse, status)))
+	}
+	return responder
+}
+
+func TestWaitForDatastoreStatus(t *testing.T) {
+	httpmock.Activate()
+	testClient := SetupTestClient()
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder("GET", testClient.Endpoint+DatastoresURI+"/"+datastoreID,
+		datastoreStatusResponder(StatusPendingCreate, StatusPendingCreate, StatusActive))
+
+	var progress []Status
+	opts := testWaitOpts()
+	opts.Progress = func(status Status, _ time.Duration) {
+		progress = append(progress, status)
+	}
+
+	actual, err := testClient.WaitForDatastoreStatus(context.Background(), datastoreID, []Status{StatusActive}, opts)
+
+	require.NoError(t, err)
+	assert.Equal(t, StatusActive, actual.Status)
+	assert.Equal(t, []Status{StatusPendingCreate, StatusPendingCreate, StatusActive}, progress)
+}
+
+func TestWaitForDatastoreStatusFailure(t *testing.T) {
+	httpmock.Activate()
+	testClient := SetupTestClient()
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder("GET", testClient.Endpoint+DatastoresURI+"/"+datastoreID,
+		datastoreStatusResponder(StatusResizing, StatusError, StatusActive))
+
+	actual, err := testClient.WaitForDatastoreStatus(
+		context.Background(), datastoreID, []Status{StatusActive}, testWaitOpts())
+
+	require.ErrorIs(t, err, ErrWaitFailed)
+	assert.Equal(t, StatusError, actual.Status)
+	assert.Equal(t, 2, httpmock.GetTotalCallCount())
+}
+
+func TestWaitForDatastoreStatusIgnoresTransientErrors(t *testing.T) {
+	httpmock.Activate()
+	testClient := SetupTestClient()
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder("GET", testClient.Endpoint+DatastoresURI+"/"+datastoreID,
+		httpmock.NewStringResponder(503, "").Then(datastoreStatusResponder(StatusActive)))
+
+	actual, err := testClient.WaitForDatastoreStatus(
+		context.Background(), datastoreID, []Status{StatusActive}, testWaitOpts())
+
+	require.NoError(t, err)
+	assert.Equal(t, StatusActive, actual.Status)
+}
+
+func TestWaitForDatastoreStatusTimeout(t *testing.T) {
+	httpmock.Activate()
+	testClient := SetupTestClient()
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder("GET", testClient.Endpoint+DatastoresURI+"/"+datastoreID,
+		datastoreStatusResponder(StatusPendingUpdate))
+
+	opts := testWaitOpts()
+	opts.Timeout = 20 * time.Millisecond
+
+	_, err := testClient.WaitForDatastoreStatus(context.Background(), datastoreID, []Status{StatusActive}, opts)
+
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestWaitForDatastoreDeleted(t *testing.T) {
+	httpmock.Activate()
+	testClient := SetupTestClient()
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder("GET", testClient.Endpoint+DatastoresURI+"/"+datastoreID,
+		datastoreStatusResponder(StatusPendingDelete).Then(
+			httpmock.NewStringResponder(404, fmt.Sprintf(testDatastoreNotFoundResponse, datastoreID))))
+
+	err := testClient.WaitForDatastoreDeleted(context.Background(), datastoreID, testWaitOpts())
+
+	require.NoError(t, err)
+	assert.Equal(t, 2, httpmock.GetTotalCallCount())
+}
+
+func TestCreateUserAndWait(t *testing.T) {
+	httpmock.Activate()
+	testClient := SetupTestClient()
+	defer httpmock.DeactivateAndReset()
+
+	pendingUserResponse := `{"user": {"id": "20d7bcf4-f8d6-4bf6-b8f6-46cb440a87f4", "status": "PENDING_CREATE"}}`
+	httpmock.RegisterResponder("POST", testClient.Endpoint+UsersURI,
+		httpmock.NewStringResponder(200, pendingUserResponse))
+	httpmock.RegisterResponder("GET", testClient.Endpoint+UsersURI+"/"+userID,
+		httpmock.NewStringResponder(200, pendingUserResponse).Then(
+			httpmock.NewStringResponder(200, testUserResponse)))
+
+	createUserOpts := UserCreateOpts{
+		Name:        "user",
+		Password:    "secret",
+		DatastoreID: datastoreID,
+	}
+
+	actual, err := testClient.CreateUserAndWait(context.Background(), createUserOpts, testWaitOpts())
+
+	require.NoError(t, err)
+	assert.Equal(t, StatusActive, actual.Status)
+	assert.Equal(t, 3, httpmock.GetTotalCallCount())
+}
+
+func TestUpdateUserAndWait(t *testing.T) {
+	httpmock.Activate()
+	testClient := SetupTestClient()
+	defer httpmock.DeactivateAndReset()
+
+	pendingUserResponse := `{"user": {"id": "20d7bcf4-f8d6-4bf6-b8f6-46cb440a87f4", "status": "PENDING_UPDATE"}}`
+	httpmock.RegisterResponder("PUT", testClient.Endpoint+UsersURI+"/"+userID,
+		httpmock.NewStringResponder(200, pendingUserResponse))
+	httpmock.RegisterResponder("GET", testClient.Endpoint+UsersURI+"/"+userID,
+		httpmock.NewStringResponder(200, pendingUserResponse).Then(
+			httpmock.NewStringResponder(200, testUserResponse)))
+
+	actual, err := testClient.UpdateUserAndWait(context.Background(), userID,
+		UserUpdateOpts{Password: "secret"}, testWaitOpts())
+
+	require.NoError(t, err)
+	assert.Equal(t, StatusActive, actual.Status)
+	assert.Equal(t, 3, httpmock.GetTotalCallCount())
+}
+
+func TestUpdateUserAndWaitAlreadyActive(t *testing.T) {
+	httpmock.Activate()
+	testClient := SetupTestClient()
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder("PUT", testClient.Endpoint+UsersURI+"/"+userID,
+		httpmock.NewStringResponder(200, testUserResponse))
+
+	actual, err := testClient.UpdateUserAndWait(context.Background(), userID,
+		UserUpdateOpts{Password: "secret"}, testWaitOpts())
+
+	require.NoError(t, err)
+	assert.Equal(t, StatusActive, actual.Status)
+	assert.Equal(t, 1, httpmock.GetTotalCallCount())
+}
+
+func TestUpdateTopicAndWait(t *testing.T) {
+	httpmock.Activate()
+	testClient := SetupTestClient()
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder("PUT", testClient.Endpoint+TopicsURI+"/"+topicID,
+		httpmock.NewStringResponder(200, `{"topic": {"id": "`+topicID+`", "status": "PENDING_UPDATE"}}`))
+	httpmock.RegisterResponder("GET", testClient.Endpoint+TopicsURI+"/"+topicID,
+		httpmock.NewStringResponder(200, `{"topic": {"id": "`+topicID+`", "status": "ERROR"}}`))
+
+	_, err := testClient.UpdateTopicAndWait(context.Background(), topicID,
+		TopicUpdateOpts{Partitions: 2}, testWaitOpts())
+
+	require.ErrorIs(t, err, ErrWaitFailed)
+}
+
+func TestDeleteGrantAndWait(t *testing.T) {
+	httpmock.Activate()
+	testClient := SetupTestClient()
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder("DELETE", testClient.Endpoint+GrantsURI+"/"+grantID,
+		httpmock.NewStringResponder(204, ""))
+	httpmock.RegisterResponder("GET", testClient.Endpoint+GrantsURI+"/"+grantID,
+		httpmock.NewStringResponder(200, `{"grant": {"id": "`+grantID+`", "status": "PENDING_DELETE"}}`).Then(
+			httpmock.NewStringResponder(404, `{"error": {"code": 404, "title": "Not Found", "message": ""}}`)))
+
+	err := testClient.DeleteGrantAndWait(context.Background(), grantID, testWaitOpts())
+
+	require.NoError(t, err)
+	assert.Equal(t, 3, httpmock.GetTotalCallCount())
+}